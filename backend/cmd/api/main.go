@@ -12,6 +12,7 @@ import (
 	"github.com/chalak/backend/internal/config"
 	"github.com/chalak/backend/internal/delivery/http/handler"
 	"github.com/chalak/backend/internal/delivery/http/router"
+	"github.com/chalak/backend/internal/delivery/worker"
 	"github.com/chalak/backend/internal/repository/postgres"
 	"github.com/chalak/backend/internal/usecase"
 	"github.com/chalak/backend/pkg/auth"
@@ -19,6 +20,7 @@ import (
 	"github.com/chalak/backend/pkg/database"
 	"github.com/chalak/backend/pkg/logger"
 	"github.com/chalak/backend/pkg/queue"
+	"github.com/chalak/backend/pkg/storage"
 	"github.com/chalak/backend/pkg/validator"
 )
 
@@ -29,6 +31,8 @@ type App struct {
 	cache       *cache.RedisCache
 	queueClient *queue.Client
 	queueServer *queue.Server
+	scheduler   *queue.Scheduler
+	storage     storage.Storage
 	validator   *validator.Validator
 	jwtService  *auth.JWTService
 }
@@ -92,6 +96,7 @@ func initializeApp() (*App, error) {
 	var redisCache *cache.RedisCache
 	var queueClient *queue.Client
 	var queueServer *queue.Server
+	var scheduler *queue.Scheduler
 
 	redisCache, err = cache.NewRedis(
 		cfg.GetRedisAddr(),
@@ -119,8 +124,23 @@ func initializeApp() (*App, error) {
 			10,
 		)
 		log.Info(context.Background(), "queue server initialized", nil)
+
+		scheduler = queue.NewScheduler(
+			cfg.GetRedisAddr(),
+			cfg.Redis.Password,
+			cfg.Redis.DB,
+		)
+		log.Info(context.Background(), "scheduler initialized", nil)
 	}
 
+	store, err := newStorage(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	log.Info(context.Background(), "file storage initialized", map[string]interface{}{
+		"driver": cfg.Storage.Driver,
+	})
+
 	validatorInstance := validator.New()
 	jwtService := auth.NewJWTService(
 		cfg.JWT.Secret,
@@ -135,11 +155,36 @@ func initializeApp() (*App, error) {
 		cache:       redisCache,
 		queueClient: queueClient,
 		queueServer: queueServer,
+		scheduler:   scheduler,
+		storage:     store,
 		validator:   validatorInstance,
 		jwtService:  jwtService,
 	}, nil
 }
 
+// newStorage picks the file storage backend from config: an S3-compatible
+// bucket, or the local disk by default.
+func newStorage(cfg *config.Config) (storage.Storage, error) {
+	if cfg.Storage.Driver == "s3" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return storage.NewS3(ctx, storage.S3Options{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+			Bucket:    cfg.Storage.S3.Bucket,
+			Region:    cfg.Storage.S3.Region,
+			UseSSL:    cfg.Storage.S3.UseSSL,
+		})
+	}
+
+	if cfg.Storage.SigningKey == cfg.JWT.Secret {
+		return nil, fmt.Errorf("storage signing key must differ from the JWT secret")
+	}
+	return storage.NewLocal(cfg.Storage.LocalPath, cfg.Storage.PublicURL, cfg.Storage.SigningKey)
+}
+
 // Handlers type now defined in router package
 
 func (app *App) initializeHandlers() *router.Handlers {
@@ -153,26 +198,54 @@ func (app *App) initializeHandlers() *router.Handlers {
 	)
 	authHandler := handler.NewAuthHandler(authUseCase, app.validator, app.logger)
 
+	// Units of work spanning several repositories
+	transactor := postgres.NewTransactor(app.db.DB)
+
+	// Institute module
+	instituteRepo := postgres.NewInstituteRepository(app.db.DB)
+	instituteUseCase := usecase.NewInstituteUseCase(instituteRepo, app.storage, app.cfg.GetMaxUploadSize(), app.logger)
+	instituteHandler := handler.NewInstituteHandler(instituteUseCase, app.validator, app.logger)
+
 	// Student module
 	studentRepo := postgres.NewStudentRepository(app.db.DB)
-	studentUseCase := usecase.NewStudentUseCase(studentRepo, app.logger)
+	studentUseCase := usecase.NewStudentUseCase(studentRepo, userRepo, app.logger)
 	studentHandler := handler.NewStudentHandler(studentUseCase, app.logger)
 
-	// Attendance module
-	attendanceRepo := postgres.NewAttendanceRepository(app.db.DB)
-	attendanceUseCase := usecase.NewAttendanceUseCase(attendanceRepo, app.logger)
-	attendanceHandler := handler.NewAttendanceHandler(attendanceUseCase, app.validator, app.logger)
-
 	// Invoice module
 	invoiceRepo := postgres.NewInvoiceRepository(app.db.DB)
-	invoiceUseCase := usecase.NewInvoiceUseCase(invoiceRepo, app.logger)
+	paymentRepo := postgres.NewPaymentRepository(app.db.DB)
+	promoRepo := postgres.NewPromoRepository(app.db.DB)
+	fiscalYearMonth, fiscalYearDay := app.cfg.GetFiscalYearStart()
+	invoiceUseCase := usecase.NewInvoiceUseCase(
+		invoiceRepo,
+		instituteRepo,
+		paymentRepo,
+		studentRepo,
+		promoRepo,
+		app.storage,
+		transactor,
+		usecase.InvoiceNumbering{
+			Format:     app.cfg.GetInvoiceNumberFormat(),
+			StartMonth: fiscalYearMonth,
+			StartDay:   fiscalYearDay,
+		},
+		app.logger,
+	)
 	invoiceHandler := handler.NewInvoiceHandler(invoiceUseCase, app.validator, app.logger)
 
+	// Promo code module
+	promoUseCase := usecase.NewPromoUseCase(promoRepo, app.logger)
+	promoHandler := handler.NewPromoHandler(promoUseCase, app.validator, app.logger)
+
 	// Payment module
-	paymentRepo := postgres.NewPaymentRepository(app.db.DB)
-	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, invoiceRepo)
+	paymentUseCase := usecase.NewPaymentUseCase(paymentRepo, invoiceRepo, transactor)
 	paymentHandler := handler.NewPaymentHandler(paymentUseCase, app.validator, app.logger)
 
+	// Refund module
+	refundRepo := postgres.NewRefundRepository(app.db.DB)
+	refundUseCase := usecase.NewRefundUseCase(refundRepo, invoiceRepo, paymentRepo, transactor, app.logger)
+	refundHandler := handler.NewRefundHandler(refundUseCase, app.validator, app.logger)
+
 	// Employee module
 	employeeRepo := postgres.NewEmployeeRepository(app.db.DB)
 	employeeUseCase := usecase.NewEmployeeUseCase(employeeRepo, app.logger)
@@ -180,7 +253,13 @@ func (app *App) initializeHandlers() *router.Handlers {
 
 	// Expense module
 	expenseRepo := postgres.NewExpenseRepository(app.db.DB)
-	expenseUseCase := usecase.NewExpenseUseCase(expenseRepo, app.logger)
+	expenseUseCase := usecase.NewExpenseUseCase(
+		expenseRepo,
+		app.storage,
+		app.cfg.GetMaxUploadSize(),
+		app.cfg.GetSignedURLExpiry(),
+		app.logger,
+	)
 	expenseHandler := handler.NewExpenseHandler(expenseUseCase, app.validator, app.logger)
 
 	// Notification module
@@ -198,6 +277,156 @@ func (app *App) initializeHandlers() *router.Handlers {
 	packageUseCase := usecase.NewPackageUseCase(packageRepo, app.logger)
 	packageHandler := handler.NewPackageHandler(packageUseCase, app.validator, app.logger)
 
+	// Installment module
+	installmentRepo := postgres.NewInstallmentRepository(app.db.DB)
+	installmentUseCase := usecase.NewInstallmentUseCase(
+		installmentRepo,
+		invoiceUseCase,
+		studentRepo,
+		packageRepo,
+		transactor,
+		app.cfg.GetInstallmentLeadTime(),
+		app.logger,
+	)
+	installmentHandler := handler.NewInstallmentHandler(installmentUseCase, app.validator, app.logger)
+
+	if app.queueServer != nil {
+		installmentWorker := worker.NewInstallmentWorker(installmentUseCase, app.logger)
+		app.queueServer.RegisterHandler(worker.TypeInstallmentInvoices, installmentWorker.HandleIssueInvoices)
+	}
+	if app.scheduler != nil {
+		if err := app.scheduler.Register(app.cfg.GetInstallmentCron(), worker.TypeInstallmentInvoices); err != nil {
+			app.logger.Error(context.Background(), "failed to schedule installment invoices", err, map[string]interface{}{})
+		}
+	}
+
+	// Vehicle module
+	vehicleRepo := postgres.NewVehicleRepository(app.db.DB)
+	vehicleUseCase := usecase.NewVehicleUseCase(vehicleRepo, userRepo, notificationRepo, app.logger)
+	vehicleHandler := handler.NewVehicleHandler(vehicleUseCase, app.validator, app.logger)
+
+	if app.queueServer != nil {
+		vehicleWorker := worker.NewVehicleWorker(vehicleUseCase, app.cfg.GetVehicleDocumentWindow(), app.logger)
+		app.queueServer.RegisterHandler(worker.TypeVehicleDocumentExpiry, vehicleWorker.HandleDocumentExpiry)
+	}
+	if app.scheduler != nil {
+		if err := app.scheduler.Register(app.cfg.GetVehicleDocumentCron(), worker.TypeVehicleDocumentExpiry); err != nil {
+			app.logger.Error(context.Background(), "failed to schedule vehicle document reminders", err, map[string]interface{}{})
+		}
+	}
+
+	// Lesson module
+	lessonRepo := postgres.NewLessonRepository(app.db.DB)
+	lessonUseCase := usecase.NewLessonUseCase(lessonRepo, courseRepo, employeeRepo, studentRepo, vehicleRepo, app.logger)
+	lessonHandler := handler.NewLessonHandler(lessonUseCase, app.validator, app.logger)
+
+	// Booking module
+	bookingRepo := postgres.NewBookingRepository(app.db.DB)
+	bookingUseCase := usecase.NewBookingUseCase(
+		bookingRepo,
+		lessonRepo,
+		lessonUseCase,
+		studentRepo,
+		employeeRepo,
+		notificationRepo,
+		app.cache,
+		app.cfg.GetBookingHoldTTL(),
+		app.logger,
+	)
+	bookingHandler := handler.NewBookingHandler(bookingUseCase, app.validator, app.logger)
+
+	// Attendance module
+	attendanceRepo := postgres.NewAttendanceRepository(app.db.DB)
+	attendanceUseCase := usecase.NewAttendanceUseCase(attendanceRepo, lessonRepo, app.logger)
+	attendanceHandler := handler.NewAttendanceHandler(attendanceUseCase, app.validator, app.logger)
+
+	// QR self check-in
+	checkInUseCase := usecase.NewCheckInUseCase(
+		attendanceRepo,
+		attendanceUseCase,
+		lessonRepo,
+		studentRepo,
+		app.jwtService,
+		app.cache,
+		app.cfg.GetCheckInTokenTTL(),
+		app.cfg.GetCheckInLateAfter(),
+		app.logger,
+	)
+	checkInHandler := handler.NewCheckInHandler(checkInUseCase, app.validator, app.logger)
+
+	// Lesson GPS tracks
+	trackUseCase := usecase.NewTrackUseCase(
+		attendanceRepo,
+		app.storage,
+		app.cfg.GetMaxUploadSize(),
+		app.cfg.GetSignedURLExpiry(),
+		app.logger,
+	)
+	trackHandler := handler.NewTrackHandler(trackUseCase, app.logger)
+
+	// Skill module
+	skillRepo := postgres.NewSkillRepository(app.db.DB)
+	skillUseCase := usecase.NewSkillUseCase(skillRepo, courseRepo, attendanceRepo, lessonRepo, studentRepo, app.logger)
+	skillHandler := handler.NewSkillHandler(skillUseCase, app.validator, app.logger)
+
+	// License test module
+	licenseTestRepo := postgres.NewLicenseTestRepository(app.db.DB)
+	licenseTestUseCase := usecase.NewLicenseTestUseCase(
+		licenseTestRepo,
+		studentRepo,
+		courseRepo,
+		employeeRepo,
+		app.cfg.GetLicenseTestMaxAttempts(),
+		app.logger,
+	)
+	licenseTestHandler := handler.NewLicenseTestHandler(licenseTestUseCase, app.validator, app.logger)
+
+	// Enrollment module
+	enrollmentRepo := postgres.NewEnrollmentRepository(app.db.DB)
+	enrollmentUseCase := usecase.NewEnrollmentUseCase(
+		enrollmentRepo,
+		studentRepo,
+		packageRepo,
+		courseRepo,
+		invoiceUseCase,
+		transactor,
+		app.logger,
+	)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentUseCase, app.validator, app.logger)
+
+	// Student document module
+	documentRepo := postgres.NewDocumentRepository(app.db.DB)
+	documentUseCase := usecase.NewDocumentUseCase(
+		documentRepo,
+		studentRepo,
+		app.storage,
+		app.cfg.GetMaxUploadSize(),
+		app.cfg.GetSignedURLExpiry(),
+		app.logger,
+	)
+	documentHandler := handler.NewDocumentHandler(documentUseCase, app.logger)
+
+	// Certificate module
+	certificateRepo := postgres.NewCertificateRepository(app.db.DB)
+	certificateUseCase := usecase.NewCertificateUseCase(
+		certificateRepo,
+		studentRepo,
+		courseRepo,
+		enrollmentRepo,
+		attendanceRepo,
+		instituteRepo,
+		app.storage,
+		app.cfg.GetSignedURLExpiry(),
+		app.logger,
+	)
+	certificateHandler := handler.NewCertificateHandler(certificateUseCase, app.validator, app.logger)
+
+	// Local storage downloads are served by the API
+	var fileHandler *handler.FileHandler
+	if local, ok := app.storage.(*storage.LocalStorage); ok {
+		fileHandler = handler.NewFileHandler(local, app.logger)
+	}
+
 	// Report module
 	reportRepo := postgres.NewReportRepository(app.db.DB)
 	reportUseCase := usecase.NewReportUseCase(reportRepo)
@@ -205,16 +434,31 @@ func (app *App) initializeHandlers() *router.Handlers {
 
 	return &router.Handlers{
 		Auth:         authHandler,
+		Institute:    instituteHandler,
 		Student:      studentHandler,
+		LicenseTest:  licenseTestHandler,
+		Document:     documentHandler,
+		Certificate:  certificateHandler,
+		Enrollment:   enrollmentHandler,
+		Vehicle:      vehicleHandler,
+		Lesson:       lessonHandler,
+		Booking:      bookingHandler,
 		Attendance:   attendanceHandler,
+		CheckIn:      checkInHandler,
+		Track:        trackHandler,
+		Skill:        skillHandler,
 		Invoice:      invoiceHandler,
 		Payment:      paymentHandler,
+		Refund:       refundHandler,
+		Installment:  installmentHandler,
+		Promo:        promoHandler,
 		Employee:     employeeHandler,
 		Expense:      expenseHandler,
 		Notification: notificationHandler,
 		Course:       courseHandler,
 		Package:      packageHandler,
 		Report:       reportHandler,
+		File:         fileHandler,
 	}
 }
 
@@ -235,6 +479,12 @@ func (app *App) startServer(server *http.Server) error {
 		}()
 	}
 
+	if app.scheduler != nil {
+		if err := app.scheduler.Start(); err != nil {
+			app.logger.Error(context.Background(), "scheduler error", err, map[string]interface{}{})
+		}
+	}
+
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 
@@ -272,4 +522,7 @@ func (app *App) cleanup() {
 	if app.queueServer != nil {
 		app.queueServer.Stop()
 	}
+	if app.scheduler != nil {
+		app.scheduler.Stop()
+	}
 }
\ No newline at end of file
@@ -71,7 +71,7 @@ func run() error {
 	)
 
 	studentRepo := postgres.NewStudentRepository(db.DB)
-	studentUseCase := usecase.NewStudentUseCase(studentRepo, log)
+	studentUseCase := usecase.NewStudentUseCase(studentRepo, postgres.NewUserRepository(db.DB), log)
 	studentHandler := handler.NewStudentHandler(studentUseCase, log)
 
 	rt := router.New(studentHandler, tokenService, log)
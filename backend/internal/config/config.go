@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,6 +14,12 @@ type Config struct {
 	Redis    RedisConfig
 	JWT      JWTConfig
 	Logging  LoggingConfig
+	Jobs     JobsConfig
+	Booking  BookingConfig
+	License  LicenseConfig
+	Storage  StorageConfig
+	CheckIn  CheckInConfig
+	Invoice  InvoiceConfig
 }
 
 type ServerConfig struct {
@@ -49,6 +56,50 @@ type LoggingConfig struct {
 	Level string
 }
 
+type CheckInConfig struct {
+	TokenSeconds     int
+	LateAfterMinutes int
+}
+
+type StorageConfig struct {
+	Driver           string
+	LocalPath        string
+	PublicURL        string
+	SigningKey       string
+	MaxUploadMB      int
+	URLExpiryMinutes int
+	S3               S3Config
+}
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+type InvoiceConfig struct {
+	NumberFormat    string
+	FiscalYearStart string
+}
+
+type LicenseConfig struct {
+	MaxTestAttempts int
+}
+
+type BookingConfig struct {
+	HoldMinutes int
+}
+
+type JobsConfig struct {
+	VehicleDocumentCron       string
+	VehicleDocumentWindowDays int
+	InstallmentCron           string
+	InstallmentLeadDays       int
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -91,4 +142,119 @@ func (c *Config) GetJWTExpiry() time.Duration {
 
 func (c *Config) GetRefreshExpiry() time.Duration {
 	return time.Duration(c.JWT.RefreshExpiryHours) * time.Hour
-}
\ No newline at end of file
+}
+
+// GetVehicleDocumentCron returns when the vehicle document expiry sweep runs,
+// daily at 06:00 unless configured.
+func (c *Config) GetVehicleDocumentCron() string {
+	if c.Jobs.VehicleDocumentCron == "" {
+		return "0 6 * * *"
+	}
+	return c.Jobs.VehicleDocumentCron
+}
+
+// GetVehicleDocumentWindow returns how far ahead of expiry vehicle document
+// reminders start, 30 days unless configured.
+func (c *Config) GetVehicleDocumentWindow() time.Duration {
+	if c.Jobs.VehicleDocumentWindowDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(c.Jobs.VehicleDocumentWindowDays) * 24 * time.Hour
+}
+
+// GetInstallmentCron returns when installment invoices are issued, daily at
+// 05:00 unless configured.
+func (c *Config) GetInstallmentCron() string {
+	if c.Jobs.InstallmentCron == "" {
+		return "0 5 * * *"
+	}
+	return c.Jobs.InstallmentCron
+}
+
+// GetInstallmentLeadTime returns how far ahead of its due date an
+// installment is invoiced, 7 days unless configured.
+func (c *Config) GetInstallmentLeadTime() time.Duration {
+	if c.Jobs.InstallmentLeadDays <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(c.Jobs.InstallmentLeadDays) * 24 * time.Hour
+}
+
+// GetBookingHoldTTL returns how long a student's slot hold lasts before it
+// lapses unconfirmed, 10 minutes unless configured.
+func (c *Config) GetBookingHoldTTL() time.Duration {
+	if c.Booking.HoldMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.Booking.HoldMinutes) * time.Minute
+}
+
+// GetLicenseTestMaxAttempts returns how many times a student may sit each
+// license test, 3 unless configured.
+func (c *Config) GetLicenseTestMaxAttempts() int {
+	if c.License.MaxTestAttempts <= 0 {
+		return 3
+	}
+	return c.License.MaxTestAttempts
+}
+
+// GetMaxUploadSize returns the largest file accepted for upload in bytes,
+// 5 MB unless configured.
+func (c *Config) GetMaxUploadSize() int64 {
+	if c.Storage.MaxUploadMB <= 0 {
+		return 5 << 20
+	}
+	return int64(c.Storage.MaxUploadMB) << 20
+}
+
+// GetSignedURLExpiry returns how long download links stay valid, 15 minutes
+// unless configured.
+func (c *Config) GetSignedURLExpiry() time.Duration {
+	if c.Storage.URLExpiryMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(c.Storage.URLExpiryMinutes) * time.Minute
+}
+
+// GetCheckInTokenTTL returns how long a lesson's QR check-in code stays
+// valid before the display must rotate it, 30 seconds unless configured.
+func (c *Config) GetCheckInTokenTTL() time.Duration {
+	if c.CheckIn.TokenSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.CheckIn.TokenSeconds) * time.Second
+}
+
+// GetCheckInLateAfter returns how long after a lesson starts a QR check-in
+// still counts as present, 10 minutes unless configured.
+func (c *Config) GetCheckInLateAfter() time.Duration {
+	if c.CheckIn.LateAfterMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.CheckIn.LateAfterMinutes) * time.Minute
+}
+
+// GetInvoiceNumberFormat returns the layout of invoice numbers. Counters
+// restart for each institute and fiscal year, so a format missing {code},
+// {fy} or the {seq} counter would repeat numbers and falls back to the
+// default.
+func (c *Config) GetInvoiceNumberFormat() string {
+	format := c.Invoice.NumberFormat
+	if !strings.Contains(format, "{code}") || !strings.Contains(format, "{fy}") ||
+		!(strings.Contains(format, "{seq}") || strings.Contains(format, "{seq:")) {
+		return "INV-{code}-{fy}-{seq:5}"
+	}
+	return format
+}
+
+// GetFiscalYearStart returns the BS month and day each fiscal year begins,
+// given as "MM-DD", or 1 Shrawan, Nepal's fiscal new year, unless
+// configured.
+func (c *Config) GetFiscalYearStart() (int, int) {
+	var month, day int
+	if _, err := fmt.Sscanf(c.Invoice.FiscalYearStart, "%2d-%2d", &month, &day); err != nil ||
+		month < 1 || month > 12 || day < 1 || day > 32 {
+		return 4, 1
+	}
+	return month, day
+}
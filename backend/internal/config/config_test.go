@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoiceNumberFormatKeepsNumbersUnique(t *testing.T) {
+	const fallback = "INV-{code}-{fy}-{seq:5}"
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "{code}/{fy}/{seq}", want: "{code}/{fy}/{seq}"},
+		{format: "{fy}-{code}-{seq:4}", want: "{fy}-{code}-{seq:4}"},
+		{format: "", want: fallback},
+		{format: "INV-{code}-{seq:5}", want: fallback},
+		{format: "INV-{fy}-{seq:5}", want: fallback},
+		{format: "INV-{code}-{fy}", want: fallback},
+	}
+
+	for _, tt := range tests {
+		c := &Config{Invoice: InvoiceConfig{NumberFormat: tt.format}}
+		assert.Equal(t, tt.want, c.GetInvoiceNumberFormat(), "format %q", tt.format)
+	}
+}
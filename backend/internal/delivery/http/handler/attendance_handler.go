@@ -56,13 +56,51 @@ func (h *AttendanceHandler) MarkAttendance(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	att, err := h.useCase.MarkAttendance(ctx, &req, userID)
+	att, created, err := h.useCase.MarkAttendance(ctx, &req, userID)
 	if err != nil {
 		h.respondError(w, r, err)
 		return
 	}
 
-	h.respondJSON(w, http.StatusCreated, att)
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	h.respondJSON(w, status, att)
+}
+
+func (h *AttendanceHandler) MarkBulk(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req attendance.BulkMarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	results, err := h.useCase.MarkBulk(ctx, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"class_id": req.ClassID,
+		"date":     req.Date,
+		"results":  results,
+		"total":    len(results),
+	})
 }
 
 func (h *AttendanceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -105,7 +143,13 @@ func (h *AttendanceHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	att, err := h.useCase.Update(ctx, id, &req)
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	att, err := h.useCase.Update(ctx, id, &req, userID)
 	if err != nil {
 		h.respondError(w, r, err)
 		return
@@ -114,6 +158,28 @@ func (h *AttendanceHandler) Update(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, att)
 }
 
+func (h *AttendanceHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	history, err := h.useCase.GetHistory(ctx, id)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  history,
+		"total": len(history),
+	})
+}
+
 func (h *AttendanceHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idStr := chi.URLParam(r, "id")
@@ -237,6 +303,87 @@ func (h *AttendanceHandler) GetStudentStats(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+func (h *AttendanceHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	var req attendance.CheckOutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	att, err := h.useCase.CheckOut(ctx, id, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, att)
+}
+
+func (h *AttendanceHandler) ListHours(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filter attendance.HoursFilter
+
+	if courseIDStr := r.URL.Query().Get("course_id"); courseIDStr != "" {
+		courseID, err := uuid.Parse(courseIDStr)
+		if err != nil {
+			h.respondError(w, r, apperrors.BadRequest("invalid course ID"))
+			return
+		}
+		filter.CourseID = &courseID
+	}
+
+	if completedStr := r.URL.Query().Get("completed"); completedStr != "" {
+		if completed, err := strconv.ParseBool(completedStr); err == nil {
+			filter.Completed = &completed
+		}
+	}
+
+	hours, err := h.useCase.ListHours(ctx, filter)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  hours,
+		"total": len(hours),
+	})
+}
+
+func (h *AttendanceHandler) GetStudentHours(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	studentIDStr := chi.URLParam(r, "student_id")
+
+	studentID, err := uuid.Parse(studentIDStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	hours, err := h.useCase.ListHours(ctx, attendance.HoursFilter{StudentID: &studentID})
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"student_id": studentID,
+		"courses":    hours,
+	})
+}
+
 func (h *AttendanceHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/usecase"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upsertRepo keeps one record per student, class and date, like the
+// database's unique index.
+type upsertRepo struct {
+	attendance.Repository
+	records map[string]*attendance.Attendance
+}
+
+func (r *upsertRepo) Upsert(ctx context.Context, att *attendance.Attendance) (bool, error) {
+	key := fmt.Sprintf("%s:%s:%s", att.StudentID, att.ClassID, att.Date.Format("2006-01-02"))
+	existing, ok := r.records[key]
+	if !ok {
+		r.records[key] = att
+		return true, nil
+	}
+	existing.Status = att.Status
+	existing.Notes = att.Notes
+	*att = *existing
+	return false, nil
+}
+
+type rosterRepo struct {
+	lesson.Repository
+	session *lesson.Session
+}
+
+func (r *rosterRepo) FindByID(ctx context.Context, id uuid.UUID) (*lesson.Session, error) {
+	return r.session, nil
+}
+
+func (r *rosterRepo) HasStudent(ctx context.Context, sessionID, studentID uuid.UUID) (bool, error) {
+	for _, id := range r.session.StudentIDs {
+		if id == studentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestMarkAttendanceStatusCodes(t *testing.T) {
+	studentID := uuid.New()
+	session := &lesson.Session{ID: uuid.New(), StartTime: time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC), Status: lesson.StatusScheduled, StudentIDs: []uuid.UUID{studentID}}
+	log := logger.New("error")
+	uc := usecase.NewAttendanceUseCase(&upsertRepo{records: map[string]*attendance.Attendance{}}, &rosterRepo{session: session}, log)
+	h := NewAttendanceHandler(uc, validator.New(), log)
+
+	mark := func(status string) (int, attendance.Attendance) {
+		body, _ := json.Marshal(attendance.MarkAttendanceRequest{
+			StudentID: studentID,
+			ClassID:   session.ID,
+			Date:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			Status:    status,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/attendance", bytes.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, uuid.New()))
+		rec := httptest.NewRecorder()
+
+		h.MarkAttendance(rec, req)
+
+		var att attendance.Attendance
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&att))
+		return rec.Code, att
+	}
+
+	code, first := mark(attendance.StatusPresent)
+	assert.Equal(t, http.StatusCreated, code)
+
+	code, second := mark(attendance.StatusLate)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, attendance.StatusLate, second.Status)
+}
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/chalak/backend/internal/delivery/http/middleware"
 	"github.com/chalak/backend/internal/domain/user"
 	"github.com/chalak/backend/internal/usecase"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
 	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -97,10 +100,45 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, loginResp)
 }
 
+// Assign places a registered user in an institute. Only admins may do this.
+func (h *AuthHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if role, _ := ctx.Value(middleware.RoleKey).(string); role != "admin" {
+		h.respondError(w, r, apperrors.Forbidden("only admins can assign users"))
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid user ID"))
+		return
+	}
+
+	var req user.AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	usr, err := h.authUseCase.Assign(ctx, id, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, usr)
+}
+
 func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	userID := ctx.Value("user_id")
+	userID := ctx.Value(middleware.UserIDKey)
 	if userID == nil {
 		h.respondError(w, r, apperrors.Unauthorized("unauthorized"))
 		return
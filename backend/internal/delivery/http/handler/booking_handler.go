@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/booking"
+	"github.com/chalak/backend/internal/usecase"
+	"github.com/chalak/backend/pkg/bs"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type BookingHandler struct {
+	useCase   *usecase.BookingUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewBookingHandler(useCase *usecase.BookingUseCase, validator *validator.Validator, logger logger.Logger) *BookingHandler {
+	return &BookingHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *BookingHandler) GetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	instructorID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid employee ID"))
+		return
+	}
+
+	hours, err := h.useCase.GetWorkingHours(ctx, instructorID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": hours,
+	})
+}
+
+func (h *BookingHandler) SetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	instructorID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid employee ID"))
+		return
+	}
+
+	var req booking.SetWorkingHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	hours, err := h.useCase.SetWorkingHours(ctx, instructorID, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": hours,
+	})
+}
+
+// ListSlots returns the calling student's bookable slots for ?date=YYYY-MM-DD,
+// optionally narrowed to one instructor_id and sized by duration (minutes).
+func (h *BookingHandler) ListSlots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", r.URL.Query().Get("date"), bs.Kathmandu)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("date is required as YYYY-MM-DD"))
+		return
+	}
+
+	filter := booking.SlotFilter{Date: date}
+
+	if instructorIDStr := r.URL.Query().Get("instructor_id"); instructorIDStr != "" {
+		instructorID, err := uuid.Parse(instructorIDStr)
+		if err != nil {
+			h.respondError(w, r, apperrors.BadRequest("invalid instructor ID"))
+			return
+		}
+		filter.InstructorID = &instructorID
+	}
+
+	if durationStr := r.URL.Query().Get("duration"); durationStr != "" {
+		duration, err := strconv.Atoi(durationStr)
+		if err != nil || duration < 15 || duration > 240 {
+			h.respondError(w, r, apperrors.BadRequest("duration must be between 15 and 240 minutes"))
+			return
+		}
+		filter.DurationMinutes = duration
+	}
+
+	slots, err := h.useCase.ListSlots(ctx, userID, filter)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": slots,
+	})
+}
+
+func (h *BookingHandler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req booking.CreateHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	hold, err := h.useCase.CreateHold(ctx, userID, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, hold)
+}
+
+func (h *BookingHandler) ConfirmHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	holdID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid hold ID"))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	session, err := h.useCase.ConfirmHold(ctx, userID, holdID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, session)
+}
+
+func (h *BookingHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	holdID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid hold ID"))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	if err := h.useCase.ReleaseHold(ctx, userID, holdID); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "hold released",
+	})
+}
+
+func (h *BookingHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *BookingHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/certificate"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CertificateHandler struct {
+	useCase   *usecase.CertificateUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewCertificateHandler(useCase *usecase.CertificateUseCase, validator *validator.Validator, logger logger.Logger) *CertificateHandler {
+	return &CertificateHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *CertificateHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	var req certificate.IssueCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	cert, err := h.useCase.Issue(ctx, studentID, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, cert)
+}
+
+func (h *CertificateHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	certificates, err := h.useCase.List(ctx, studentID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  certificates,
+		"total": len(certificates),
+	})
+}
+
+func (h *CertificateHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	certificateID, err := uuid.Parse(chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid certificate ID"))
+		return
+	}
+
+	link, err := h.useCase.DownloadURL(ctx, studentID, certificateID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, link)
+}
+
+func (h *CertificateHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *CertificateHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
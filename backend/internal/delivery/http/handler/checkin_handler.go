@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultQRSize = 320
+	maxQRSize     = 1024
+)
+
+type CheckInHandler struct {
+	useCase   *usecase.CheckInUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewCheckInHandler(useCase *usecase.CheckInUseCase, validator *validator.Validator, logger logger.Logger) *CheckInHandler {
+	return &CheckInHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+// GetCode returns the current check-in token for a lesson so a client can
+// render its own QR code.
+func (h *CheckInHandler) GetCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, ok := h.parseSession(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := h.useCase.GenerateCode(ctx, sessionID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusOK, code)
+}
+
+// GetCodePNG returns the current check-in token as a QR code image.
+func (h *CheckInHandler) GetCodePNG(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sessionID, ok := h.parseSession(w, r)
+	if !ok {
+		return
+	}
+
+	size := defaultQRSize
+	if sizeStr := r.URL.Query().Get("size"); sizeStr != "" {
+		if s, err := strconv.Atoi(sizeStr); err == nil && s > 0 && s <= maxQRSize {
+			size = s
+		}
+	}
+
+	png, code, err := h.useCase.GenerateCodePNG(ctx, sessionID, size)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Code-Expires-At", code.ExpiresAt.Format(time.RFC3339))
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// CheckIn marks the signed-in student in using a scanned token.
+func (h *CheckInHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req attendance.SelfCheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	att, created, err := h.useCase.CheckIn(ctx, userID, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	h.respondJSON(w, status, att)
+}
+
+// parseSession reads the lesson ID and keeps students from fetching codes
+// they could share with classmates who are not in the room.
+func (h *CheckInHandler) parseSession(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if role, _ := r.Context().Value(middleware.RoleKey).(string); role == "student" {
+		h.respondError(w, r, apperrors.Forbidden("students cannot display check-in codes"))
+		return uuid.Nil, false
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid class ID"))
+		return uuid.Nil, false
+	}
+
+	return sessionID, true
+}
+
+func (h *CheckInHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *CheckInHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
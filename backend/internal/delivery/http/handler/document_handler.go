@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type DocumentHandler struct {
+	useCase *usecase.DocumentUseCase
+	logger  logger.Logger
+}
+
+func NewDocumentHandler(useCase *usecase.DocumentUseCase, logger logger.Logger) *DocumentHandler {
+	return &DocumentHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// Upload accepts a multipart form with a "type" field and a "file" part.
+func (h *DocumentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	upload, file, err := readUpload(w, r, h.useCase.MaxUploadSize())
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	doc, err := h.useCase.Upload(ctx, studentID, r.FormValue("type"), upload, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, doc)
+}
+
+func (h *DocumentHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	documents, err := h.useCase.List(ctx, studentID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  documents,
+		"total": len(documents),
+	})
+}
+
+func (h *DocumentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, documentID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	link, err := h.useCase.DownloadURL(ctx, studentID, documentID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, link)
+}
+
+func (h *DocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, documentID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, studentID, documentID); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "document deleted successfully",
+	})
+}
+
+func (h *DocumentHandler) parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	documentID, err := uuid.Parse(chi.URLParam(r, "document_id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid document ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return studentID, documentID, true
+}
+
+func (h *DocumentHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *DocumentHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/enrollment"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type EnrollmentHandler struct {
+	useCase   *usecase.EnrollmentUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewEnrollmentHandler(useCase *usecase.EnrollmentUseCase, validator *validator.Validator, logger logger.Logger) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *EnrollmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req enrollment.CreateEnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	e, err := h.useCase.Create(ctx, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, e)
+}
+
+func (h *EnrollmentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid enrollment ID"))
+		return
+	}
+
+	e, err := h.useCase.GetByID(ctx, id)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, e)
+}
+
+func (h *EnrollmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid enrollment ID"))
+		return
+	}
+
+	var req enrollment.UpdateEnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	e, err := h.useCase.Update(ctx, id, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, e)
+}
+
+func (h *EnrollmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid enrollment ID"))
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, id); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "enrollment deleted successfully",
+	})
+}
+
+func (h *EnrollmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := enrollment.EnrollmentFilter{
+		Limit:  10,
+		Offset: 0,
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	if studentIDStr := r.URL.Query().Get("student_id"); studentIDStr != "" {
+		if studentID, err := uuid.Parse(studentIDStr); err == nil {
+			filter.StudentID = &studentID
+		}
+	}
+
+	if packageIDStr := r.URL.Query().Get("package_id"); packageIDStr != "" {
+		if packageID, err := uuid.Parse(packageIDStr); err == nil {
+			filter.PackageID = &packageID
+		}
+	}
+
+	if courseIDStr := r.URL.Query().Get("course_id"); courseIDStr != "" {
+		if courseID, err := uuid.Parse(courseIDStr); err == nil {
+			filter.CourseID = &courseID
+		}
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = &status
+	}
+
+	enrollments, total, err := h.useCase.List(ctx, filter)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  enrollments,
+		"total": total,
+	})
+}
+
+func (h *EnrollmentHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *EnrollmentHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
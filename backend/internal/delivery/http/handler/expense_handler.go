@@ -226,6 +226,51 @@ func (h *ExpenseHandler) Reject(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *ExpenseHandler) UploadReceipt(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid expense ID"))
+		return
+	}
+
+	upload, file, err := readUpload(w, r, h.useCase.MaxUploadSize())
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	exp, err := h.useCase.UploadReceipt(ctx, id, upload)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, exp)
+}
+
+func (h *ExpenseHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid expense ID"))
+		return
+	}
+
+	link, err := h.useCase.ReceiptURL(ctx, id)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, link)
+}
+
 func (h *ExpenseHandler) GetTotalExpenses(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	instituteIDStr := chi.URLParam(r, "institute_id")
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/go-chi/chi/v5"
+)
+
+// FileHandler serves files kept in local storage. Requests carry no token;
+// the signed URL handed out by the API is the authorization.
+type FileHandler struct {
+	storage *storage.LocalStorage
+	logger  logger.Logger
+}
+
+func NewFileHandler(store *storage.LocalStorage, logger logger.Logger) *FileHandler {
+	return &FileHandler{
+		storage: store,
+		logger:  logger,
+	}
+}
+
+func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := chi.URLParam(r, "*")
+
+	query := r.URL.Query()
+	if err := h.storage.Verify(key, query.Get("expires"), query.Get("signature")); err != nil {
+		h.respondError(w, r, apperrors.Forbidden(err.Error()))
+		return
+	}
+
+	file, err := h.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			h.respondError(w, r, apperrors.NotFound("file not found"))
+			return
+		}
+		h.respondError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, file)
+}
+
+func (h *FileHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type InstituteHandler struct {
+	useCase   *usecase.InstituteUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewInstituteHandler(useCase *usecase.InstituteUseCase, validator *validator.Validator, logger logger.Logger) *InstituteHandler {
+	return &InstituteHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *InstituteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req institute.CreateInstituteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	inst, err := h.useCase.Create(ctx, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, inst)
+}
+
+func (h *InstituteHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid institute ID"))
+		return
+	}
+
+	inst, err := h.useCase.GetByID(ctx, id)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, inst)
+}
+
+func (h *InstituteHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid institute ID"))
+		return
+	}
+
+	var req institute.UpdateInstituteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	inst, err := h.useCase.Update(ctx, id, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, inst)
+}
+
+// UploadLogo replaces the logo printed on the institute's invoices.
+func (h *InstituteHandler) UploadLogo(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid institute ID"))
+		return
+	}
+
+	upload, file, err := readUpload(w, r, h.useCase.MaxUploadSize())
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	inst, err := h.useCase.UploadLogo(ctx, id, upload)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, inst)
+}
+
+func (h *InstituteHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid institute ID"))
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, id); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "institute deleted successfully",
+	})
+}
+
+func (h *InstituteHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := institute.InstituteFilter{
+		Limit:  20,
+		Offset: 0,
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = &status
+	}
+
+	if search := r.URL.Query().Get("search"); search != "" {
+		filter.Search = &search
+	}
+
+	institutes, total, err := h.useCase.List(ctx, filter)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  institutes,
+		"total": total,
+	})
+}
+
+// requireAdmin rejects callers who are not admins. Together with the tenant
+// scope on lookups this limits changes to admins of the institute itself;
+// deleting an institute or changing its status is further limited to
+// platform admins by the use case.
+func (h *InstituteHandler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if role, _ := r.Context().Value(middleware.RoleKey).(string); role != "admin" {
+		h.respondError(w, r, apperrors.Forbidden("only admins can change an institute"))
+		return false
+	}
+	return true
+}
+
+func (h *InstituteHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *InstituteHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/chalak/backend/internal/delivery/http/middleware"
@@ -10,6 +12,7 @@ import (
 	"github.com/chalak/backend/internal/usecase"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/storage"
 	"github.com/chalak/backend/pkg/validator"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -83,7 +86,8 @@ func (h *InvoiceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, inv)
 }
 
-func (h *InvoiceHandler) MarkAsPaid(w http.ResponseWriter, r *http.Request) {
+// DownloadPDF returns the invoice as a printable PDF.
+func (h *InvoiceHandler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idStr := chi.URLParam(r, "id")
 
@@ -93,34 +97,17 @@ func (h *InvoiceHandler) MarkAsPaid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.useCase.MarkAsPaid(ctx, id); err != nil {
-		h.respondError(w, r, err)
-		return
-	}
-
-	h.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "invoice marked as paid",
-	})
-}
-
-func (h *InvoiceHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	idStr := chi.URLParam(r, "id")
-
-	id, err := uuid.Parse(idStr)
+	file, inv, err := h.useCase.RenderPDF(ctx, id)
 	if err != nil {
-		h.respondError(w, r, apperrors.BadRequest("invalid invoice ID"))
-		return
-	}
-
-	if err := h.useCase.Delete(ctx, id); err != nil {
 		h.respondError(w, r, err)
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "invoice deleted successfully",
-	})
+	w.Header().Set("Content-Type", storage.TypePDF)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", inv.InvoiceNumber+".pdf"))
+	w.Header().Set("Content-Length", strconv.Itoa(len(file)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file)
 }
 
 func (h *InvoiceHandler) List(w http.ResponseWriter, r *http.Request) {
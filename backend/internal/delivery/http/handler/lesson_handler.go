@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type LessonHandler struct {
+	useCase   *usecase.LessonUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewLessonHandler(useCase *usecase.LessonUseCase, validator *validator.Validator, logger logger.Logger) *LessonHandler {
+	return &LessonHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *LessonHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req lesson.CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	session, err := h.useCase.Create(ctx, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, session)
+}
+
+func (h *LessonHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid lesson session ID"))
+		return
+	}
+
+	session, err := h.useCase.GetByID(ctx, id)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, session)
+}
+
+func (h *LessonHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid lesson session ID"))
+		return
+	}
+
+	var req lesson.UpdateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	session, err := h.useCase.Update(ctx, id, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, session)
+}
+
+func (h *LessonHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid lesson session ID"))
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, id); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "lesson session deleted successfully",
+	})
+}
+
+// List returns sessions, optionally for a single day (?date=YYYY-MM-DD) or a
+// date_from/date_to range.
+func (h *LessonHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := lesson.SessionFilter{
+		Limit:  50,
+		Offset: 0,
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.respondError(w, r, apperrors.BadRequest("invalid date, expected YYYY-MM-DD"))
+			return
+		}
+		nextDay := date.AddDate(0, 0, 1)
+		filter.DateFrom = &date
+		filter.DateTo = &nextDay
+	}
+
+	if dateFromStr := r.URL.Query().Get("date_from"); dateFromStr != "" {
+		if parsed, err := time.Parse("2006-01-02", dateFromStr); err == nil {
+			filter.DateFrom = &parsed
+		}
+	}
+
+	if dateToStr := r.URL.Query().Get("date_to"); dateToStr != "" {
+		if parsed, err := time.Parse("2006-01-02", dateToStr); err == nil {
+			nextDay := parsed.AddDate(0, 0, 1)
+			filter.DateTo = &nextDay
+		}
+	}
+
+	if courseIDStr := r.URL.Query().Get("course_id"); courseIDStr != "" {
+		if courseID, err := uuid.Parse(courseIDStr); err == nil {
+			filter.CourseID = &courseID
+		}
+	}
+
+	if instructorIDStr := r.URL.Query().Get("instructor_id"); instructorIDStr != "" {
+		if instructorID, err := uuid.Parse(instructorIDStr); err == nil {
+			filter.InstructorID = &instructorID
+		}
+	}
+
+	if studentIDStr := r.URL.Query().Get("student_id"); studentIDStr != "" {
+		if studentID, err := uuid.Parse(studentIDStr); err == nil {
+			filter.StudentID = &studentID
+		}
+	}
+
+	if sessionType := r.URL.Query().Get("type"); sessionType != "" {
+		filter.Type = &sessionType
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = &status
+	}
+
+	sessions, total, err := h.useCase.List(ctx, filter)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  sessions,
+		"total": total,
+	})
+}
+
+func (h *LessonHandler) AddStudents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	idStr := chi.URLParam(r, "id")
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid lesson session ID"))
+		return
+	}
+
+	var req lesson.RosterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	session, err := h.useCase.AddStudents(ctx, id, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, session)
+}
+
+func (h *LessonHandler) RemoveStudent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid lesson session ID"))
+		return
+	}
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "student_id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	if err := h.useCase.RemoveStudent(ctx, id, studentID); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "student removed from lesson session",
+	})
+}
+
+func (h *LessonHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *LessonHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/licensetest"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type LicenseTestHandler struct {
+	useCase   *usecase.LicenseTestUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewLicenseTestHandler(useCase *usecase.LicenseTestUseCase, validator *validator.Validator, logger logger.Logger) *LicenseTestHandler {
+	return &LicenseTestHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *LicenseTestHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	var req licensetest.CreateLicenseTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	t, err := h.useCase.Create(ctx, studentID, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, t)
+}
+
+func (h *LicenseTestHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, testID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := h.useCase.GetByID(ctx, studentID, testID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, t)
+}
+
+func (h *LicenseTestHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, testID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req licensetest.UpdateLicenseTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	t, err := h.useCase.Update(ctx, studentID, testID, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, t)
+}
+
+func (h *LicenseTestHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, testID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, studentID, testID); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "license test deleted successfully",
+	})
+}
+
+func (h *LicenseTestHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	filter := licensetest.LicenseTestFilter{
+		StudentID: &studentID,
+		Limit:     20,
+		Offset:    0,
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filter.Limit = limit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filter.Offset = offset
+		}
+	}
+
+	if testType := r.URL.Query().Get("type"); testType != "" {
+		filter.Type = &testType
+	}
+
+	if result := r.URL.Query().Get("result"); result != "" {
+		filter.Result = &result
+	}
+
+	tests, total, err := h.useCase.List(ctx, filter)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  tests,
+		"total": total,
+	})
+}
+
+func (h *LicenseTestHandler) parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	testID, err := uuid.Parse(chi.URLParam(r, "test_id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid license test ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return studentID, testID, true
+}
+
+func (h *LicenseTestHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *LicenseTestHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -8,6 +8,7 @@ import (
 	pkg "github.com/chalak/backend/internal/domain/package"
 	"github.com/chalak/backend/internal/usecase"
 	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/money"
 	"github.com/chalak/backend/pkg/validator"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -167,13 +168,13 @@ func (h *PackageHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if minPriceStr := query.Get("min_price"); minPriceStr != "" {
-		if minPrice, err := strconv.ParseFloat(minPriceStr, 64); err == nil {
+		if minPrice, err := money.Parse(minPriceStr); err == nil {
 			filter.MinPrice = &minPrice
 		}
 	}
 
 	if maxPriceStr := query.Get("max_price"); maxPriceStr != "" {
-		if maxPrice, err := strconv.ParseFloat(maxPriceStr, 64); err == nil {
+		if maxPrice, err := money.Parse(maxPriceStr); err == nil {
 			filter.MaxPrice = &maxPrice
 		}
 	}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/refund"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type RefundHandler struct {
+	useCase   *usecase.RefundUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewRefundHandler(useCase *usecase.RefundUseCase, validator *validator.Validator, logger logger.Logger) *RefundHandler {
+	return &RefundHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *RefundHandler) RequestCreditNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid invoice ID"))
+		return
+	}
+
+	var req refund.CreateCreditNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := h.useCase.RequestCreditNote(ctx, invoiceID, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, note)
+}
+
+func (h *RefundHandler) ListCreditNotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid invoice ID"))
+		return
+	}
+
+	notes, err := h.useCase.ListCreditNotes(ctx, invoiceID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": notes,
+	})
+}
+
+func (h *RefundHandler) ApproveCreditNote(w http.ResponseWriter, r *http.Request) {
+	h.decideCreditNote(w, r, h.useCase.ApproveCreditNote)
+}
+
+func (h *RefundHandler) RejectCreditNote(w http.ResponseWriter, r *http.Request) {
+	h.decideCreditNote(w, r, h.useCase.RejectCreditNote)
+}
+
+func (h *RefundHandler) RequestReversal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	paymentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid payment ID"))
+		return
+	}
+
+	var req refund.CreateReversalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	rev, err := h.useCase.RequestReversal(ctx, paymentID, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, rev)
+}
+
+func (h *RefundHandler) ListReversals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	paymentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid payment ID"))
+		return
+	}
+
+	reversals, err := h.useCase.ListReversals(ctx, paymentID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": reversals,
+	})
+}
+
+func (h *RefundHandler) ApproveReversal(w http.ResponseWriter, r *http.Request) {
+	h.decideReversal(w, r, h.useCase.ApproveReversal)
+}
+
+func (h *RefundHandler) RejectReversal(w http.ResponseWriter, r *http.Request) {
+	h.decideReversal(w, r, h.useCase.RejectReversal)
+}
+
+func (h *RefundHandler) decideCreditNote(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, id, userID uuid.UUID) (*refund.CreditNote, error)) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid credit note ID"))
+		return
+	}
+
+	userID, ok := h.approver(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := decide(r.Context(), id, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, note)
+}
+
+func (h *RefundHandler) decideReversal(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, id, userID uuid.UUID) (*refund.Reversal, error)) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid payment reversal ID"))
+		return
+	}
+
+	userID, ok := h.approver(w, r)
+	if !ok {
+		return
+	}
+
+	rev, err := decide(r.Context(), id, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rev)
+}
+
+// approver returns the current user when they may approve or reject
+// refunds. Anyone can ask for one, but only admins sign it off.
+func (h *RefundHandler) approver(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if role, _ := r.Context().Value(middleware.RoleKey).(string); role != "admin" {
+		h.respondError(w, r, apperrors.Forbidden("only admins can approve or reject refunds"))
+		return uuid.Nil, false
+	}
+	return h.currentUser(w, r)
+}
+
+func (h *RefundHandler) currentUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, ok := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (h *RefundHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *RefundHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
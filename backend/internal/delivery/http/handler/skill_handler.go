@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/domain/skill"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type SkillHandler struct {
+	useCase   *usecase.SkillUseCase
+	validator *validator.Validator
+	logger    logger.Logger
+}
+
+func NewSkillHandler(useCase *usecase.SkillUseCase, validator *validator.Validator, logger logger.Logger) *SkillHandler {
+	return &SkillHandler{
+		useCase:   useCase,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *SkillHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	courseID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid course ID"))
+		return
+	}
+
+	var req skill.CreateSkillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	s, err := h.useCase.Create(ctx, courseID, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, s)
+}
+
+func (h *SkillHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	courseID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid course ID"))
+		return
+	}
+
+	skills, err := h.useCase.List(ctx, courseID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  skills,
+		"total": len(skills),
+	})
+}
+
+func (h *SkillHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	courseID, skillID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req skill.UpdateSkillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	s, err := h.useCase.Update(ctx, courseID, skillID, &req)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, s)
+}
+
+func (h *SkillHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	courseID, skillID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.Delete(ctx, courseID, skillID); err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "skill deleted successfully",
+	})
+}
+
+func (h *SkillHandler) Grade(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attendanceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	var req skill.GradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid request body"))
+		return
+	}
+
+	if validationErrors := h.validator.Validate(&req); validationErrors != nil {
+		h.respondError(w, r, apperrors.Validation(validationErrors))
+		return
+	}
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	scores, err := h.useCase.Grade(ctx, attendanceID, &req, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": scores,
+	})
+}
+
+func (h *SkillHandler) GetScores(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attendanceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	scores, err := h.useCase.GetScores(ctx, attendanceID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": scores,
+	})
+}
+
+func (h *SkillHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid student ID"))
+		return
+	}
+
+	courseID, err := uuid.Parse(r.URL.Query().Get("course_id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("course_id is required"))
+		return
+	}
+
+	progress, err := h.useCase.GetProgress(ctx, studentID, courseID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, progress)
+}
+
+func (h *SkillHandler) parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	courseID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid course ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	skillID, err := uuid.Parse(chi.URLParam(r, "skill_id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid skill ID"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return courseID, skillID, true
+}
+
+func (h *SkillHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *SkillHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
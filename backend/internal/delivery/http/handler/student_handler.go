@@ -7,6 +7,7 @@ import (
 
 	"github.com/chalak/backend/internal/domain/student"
 	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -51,7 +52,7 @@ func (h *StudentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	s, err := h.useCase.CreateStudent(r.Context(), req)
 	if err != nil {
 		h.logger.Error(r.Context(), "failed to create student", err, nil)
-		h.respondError(w, http.StatusInternalServerError, "failed to create student", err)
+		h.respondError(w, apperrors.GetStatusCode(err), "failed to create student", err)
 		return
 	}
 
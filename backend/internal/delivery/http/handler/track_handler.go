@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chalak/backend/internal/delivery/http/middleware"
+	"github.com/chalak/backend/internal/usecase"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type TrackHandler struct {
+	useCase *usecase.TrackUseCase
+	logger  logger.Logger
+}
+
+func NewTrackHandler(useCase *usecase.TrackUseCase, logger logger.Logger) *TrackHandler {
+	return &TrackHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+func (h *TrackHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attendanceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	upload, file, err := readUpload(w, r, h.useCase.MaxUploadSize())
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	userID, ok := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !ok {
+		h.respondError(w, r, apperrors.Unauthorized("user not authenticated"))
+		return
+	}
+
+	track, err := h.useCase.Upload(ctx, attendanceID, upload, userID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, track)
+}
+
+func (h *TrackHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attendanceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	track, err := h.useCase.Get(ctx, attendanceID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, track)
+}
+
+func (h *TrackHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attendanceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, apperrors.BadRequest("invalid attendance ID"))
+		return
+	}
+
+	link, err := h.useCase.DownloadURL(ctx, attendanceID)
+	if err != nil {
+		h.respondError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, link)
+}
+
+func (h *TrackHandler) respondJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *TrackHandler) respondError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := apperrors.GetStatusCode(err)
+
+	var appErr *apperrors.AppError
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+
+	if errors, ok := err.(*apperrors.AppError); ok {
+		appErr = errors
+		if appErr.Details != nil {
+			response["details"] = appErr.Details
+		}
+	}
+
+	h.logger.Error(r.Context(), "request error", err, map[string]interface{}{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"status_code": statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/storage"
+)
+
+// multipartOverhead leaves room for form boundaries and other fields on top
+// of the file itself.
+const multipartOverhead = 1 << 20
+
+// readUpload reads the "file" part of a multipart request. The body is capped
+// at maxSize so oversized uploads are rejected before being buffered. The
+// caller must close the returned file.
+func readUpload(w http.ResponseWriter, r *http.Request, maxSize int64) (*storage.Upload, multipart.File, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, nil, apperrors.TooLarge("file is too large")
+		}
+		return nil, nil, apperrors.BadRequest("invalid multipart form")
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, nil, apperrors.BadRequest("file is required")
+	}
+
+	return &storage.Upload{
+		File:     file,
+		Size:     header.Size,
+		FileName: header.Filename,
+	}, file, nil
+}
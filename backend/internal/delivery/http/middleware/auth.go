@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 
 	"github.com/chalak/backend/pkg/auth"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
 )
 
 type contextKey string
@@ -15,31 +18,68 @@ const (
 	RoleKey   contextKey = "role"
 )
 
+// AuthMiddleware authenticates the request and confines it to the institute
+// named in the caller's token.
 func AuthMiddleware(tokenService auth.TokenService) func(http.Handler) http.Handler {
+	return authenticate(tokenService, false)
+}
+
+// AccountMiddleware authenticates the request like AuthMiddleware but also
+// admits accounts that are not yet assigned to an institute. It is for
+// routes about the caller's own account, such as /auth/me.
+func AccountMiddleware(tokenService auth.TokenService) func(http.Handler) http.Handler {
+	return authenticate(tokenService, true)
+}
+
+func authenticate(tokenService auth.TokenService, allowUnassigned bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "missing authorization header", http.StatusUnauthorized)
+				respondError(w, apperrors.Unauthorized("missing authorization header"))
 				return
 			}
 
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "invalid authorization header format", http.StatusUnauthorized)
+				respondError(w, apperrors.Unauthorized("invalid authorization header format"))
 				return
 			}
 
 			claims, err := tokenService.ValidateToken(parts[1])
 			if err != nil {
-				http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+				respondError(w, apperrors.Unauthorized("invalid or expired token"))
 				return
 			}
 
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, RoleKey, claims.Role)
 
+			// Only platform admins may act without an institute; everyone
+			// else is confined to the institute named in their token and,
+			// until they have one, to their own account.
+			if claims.InstituteID != nil {
+				ctx = tenant.WithInstituteID(ctx, *claims.InstituteID)
+			} else if claims.Role != "admin" && !allowUnassigned {
+				respondError(w, apperrors.Forbidden("account is not assigned to an institute"))
+				return
+			}
+
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
-}
\ No newline at end of file
+}
+
+// respondError writes err in the same JSON shape the handlers use.
+func respondError(w http.ResponseWriter, err error) {
+	response := map[string]interface{}{
+		"error": err.Error(),
+	}
+	if appErr, ok := err.(*apperrors.AppError); ok && appErr.Details != nil {
+		response["details"] = appErr.Details
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apperrors.GetStatusCode(err))
+	json.NewEncoder(w).Encode(response)
+}
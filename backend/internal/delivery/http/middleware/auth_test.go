@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/pkg/auth"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnassignedAccountsOnlyReachTheirAccount(t *testing.T) {
+	tokens := auth.NewJWTService("test-secret", time.Hour, 24*time.Hour)
+	instituteID := uuid.New()
+
+	tests := []struct {
+		name        string
+		role        string
+		instituteID *uuid.UUID
+		wantAPI     int
+		wantAccount int
+	}{
+		{name: "institute member", role: "instructor", instituteID: &instituteID, wantAPI: http.StatusOK, wantAccount: http.StatusOK},
+		{name: "platform admin", role: "admin", wantAPI: http.StatusOK, wantAccount: http.StatusOK},
+		{name: "self-registered user", role: "user", wantAPI: http.StatusForbidden, wantAccount: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tokens.GenerateToken(uuid.New(), tt.role, tt.instituteID)
+			require.NoError(t, err)
+
+			serve := func(mw func(http.Handler) http.Handler) int {
+				var scoped bool
+				h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, scoped = tenant.InstituteID(r.Context())
+				}))
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				if rec.Code == http.StatusOK {
+					assert.Equal(t, tt.instituteID != nil, scoped)
+				}
+				return rec.Code
+			}
+
+			assert.Equal(t, tt.wantAPI, serve(AuthMiddleware(tokens)))
+			assert.Equal(t, tt.wantAccount, serve(AccountMiddleware(tokens)))
+		})
+	}
+}
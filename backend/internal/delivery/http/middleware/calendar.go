@@ -0,0 +1,296 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chalak/backend/pkg/bs"
+	apperrors "github.com/chalak/backend/pkg/errors"
+)
+
+// CalendarHeader selects the calendar a request reads and writes dates in.
+// The calendar query parameter does the same for links that cannot set
+// headers.
+const CalendarHeader = "X-Calendar"
+
+var errNotJSON = errors.New("body is not JSON")
+
+var (
+	dateOnly     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	adMidnightTS = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})T00:00:00(\.0+)?Z$`)
+)
+
+// dateParams are the query parameters that filter by calendar day.
+var dateParams = map[string]bool{
+	"date":       true,
+	"date_from":  true,
+	"date_to":    true,
+	"start_date": true,
+	"end_date":   true,
+}
+
+// dateFields are the JSON fields of the API's requests and responses that
+// hold a calendar day rather than an instant. Nothing else is converted, so
+// notes, descriptions and timestamps such as created_at pass through as they
+// are.
+var dateFields = map[string]bool{
+	"bluebook_expiry":   true,
+	"completed_on":      true,
+	"date":              true,
+	"date_of_birth":     true,
+	"due_date":          true,
+	"due_dates":         true,
+	"end_date":          true,
+	"expected_end_date": true,
+	"first_date":        true,
+	"first_due_date":    true,
+	"hire_date":         true,
+	"insurance_expiry":  true,
+	"last_date":         true,
+	"next_due_date":     true,
+	"payment_date":      true,
+	"pollution_expiry":  true,
+	"scheduled_date":    true,
+	"start_date":        true,
+	"valid_from":        true,
+	"valid_until":       true,
+}
+
+// CalendarMiddleware lets clients work in Bikram Sambat. When a request asks
+// for BS, the date parameters of the query string and the date fields of the
+// JSON body are read as BS and handed to the handlers in AD, and the date
+// fields of the JSON response are written back as BS.
+func CalendarMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calendar := r.URL.Query().Get("calendar")
+		if calendar == "" {
+			calendar = r.Header.Get(CalendarHeader)
+		}
+		if !strings.EqualFold(calendar, "bs") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		for key, values := range query {
+			if !dateParams[key] {
+				continue
+			}
+			for i, v := range values {
+				ad, err := bsToAD(v)
+				if err != nil {
+					respondError(w, dateError(key, err))
+					return
+				}
+				values[i] = ad
+			}
+		}
+		r.URL.RawQuery = query.Encode()
+
+		if r.Body != nil && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, apperrors.BadRequest("failed to read request body"))
+				return
+			}
+			if converted, err := convertJSON(body, bsToADTimestamp); err == nil {
+				body = converted
+			} else if !errors.Is(err, errNotJSON) {
+				respondError(w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+
+		w.Header().Set(CalendarHeader, "BS")
+		cw := &calendarWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(cw, r.WithContext(bs.WithCalendar(r.Context())))
+		cw.flush()
+	})
+}
+
+// calendarWriter holds back JSON responses so their dates can be rewritten.
+// Anything else, such as file downloads, is passed straight through.
+type calendarWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buf         *bytes.Buffer
+}
+
+func (cw *calendarWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = status
+	if strings.HasPrefix(cw.Header().Get("Content-Type"), "application/json") {
+		cw.buf = new(bytes.Buffer)
+		return
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *calendarWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.buf != nil {
+		return cw.buf.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *calendarWriter) flush() {
+	if cw.buf == nil {
+		return
+	}
+
+	body := cw.buf.Bytes()
+	if converted, err := convertJSON(body, adToBS); err == nil {
+		body = converted
+	}
+	cw.Header().Del("Content-Length")
+	cw.ResponseWriter.WriteHeader(cw.status)
+	cw.ResponseWriter.Write(body)
+}
+
+// convertJSON rewrites the date fields of a JSON document with fn. Numbers
+// are kept as written so amounts do not lose precision on the way through.
+func convertJSON(data []byte, fn func(string) (string, error)) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errNotJSON
+	}
+	doc, err := convertValue(doc, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.NewEncoder(&out).Encode(doc); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// convertValue looks through v for date fields and rewrites their values,
+// or each value of a list of dates, with fn.
+func convertValue(v interface{}, fn func(string) (string, error)) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if dateFields[key] {
+				converted, err := convertDates(item, fn)
+				if err != nil {
+					return nil, dateError(key, err)
+				}
+				v[key] = converted
+				continue
+			}
+			converted, err := convertValue(item, fn)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+	case []interface{}:
+		for i, item := range v {
+			converted, err := convertValue(item, fn)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	}
+	return v, nil
+}
+
+// convertDates rewrites the value of a date field: a date, or a list of them.
+// Nulls and anything else are left alone.
+func convertDates(v interface{}, fn func(string) (string, error)) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return fn(v)
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				converted, err := fn(s)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = converted
+			}
+		}
+	}
+	return v, nil
+}
+
+// dateError reports a BS date that could not be read.
+func dateError(field string, err error) error {
+	return apperrors.BadRequest(field + " is not a valid BS date (YYYY-MM-DD)").WithDetails(map[string]interface{}{
+		"field": field,
+		"error": err.Error(),
+	})
+}
+
+// bsToAD converts a BS YYYY-MM-DD date to the AD date in the same format.
+func bsToAD(s string) (string, error) {
+	ad, err := parseBS(s)
+	if err != nil {
+		return "", err
+	}
+	return ad.Format("2006-01-02"), nil
+}
+
+// bsToADTimestamp converts BS date-only strings in a request body to AD
+// midnight UTC, which the time.Time fields of request structs accept.
+func bsToADTimestamp(s string) (string, error) {
+	if !dateOnly.MatchString(s) {
+		return s, nil
+	}
+	ad, err := parseBS(s)
+	if err != nil {
+		return "", err
+	}
+	return ad.Format(time.RFC3339), nil
+}
+
+func parseBS(s string) (time.Time, error) {
+	d, err := bs.Parse(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return d.ToAD()
+}
+
+// adToBS converts calendar days in a response to BS, leaving any value the
+// converter cannot place untouched.
+func adToBS(s string) (string, error) {
+	day := s
+	if m := adMidnightTS.FindStringSubmatch(s); m != nil {
+		day = m[1]
+	} else if !dateOnly.MatchString(s) {
+		return s, nil
+	}
+
+	ad, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return s, nil
+	}
+	d, err := bs.FromAD(ad)
+	if err != nil {
+		return s, nil
+	}
+	return d.String(), nil
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarMiddlewareConvertsOnlyDateFields(t *testing.T) {
+	var gotBody map[string]interface{}
+	var gotQuery string
+	handler := CalendarMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"due_date":   "2024-07-16T00:00:00Z",
+			"created_at": "2024-07-16T00:00:00Z",
+			"notes":      "paid on 2024-07-16",
+		})
+	}))
+
+	body := `{"due_date":"2081-04-01","due_dates":["2081-01-01"],"notes":"moved from 2081-04-01","amount":1500.50}`
+	req := httptest.NewRequest(http.MethodPost, "/invoices?calendar=bs&date_from=2081-04-01&search=2081-04-01", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, gotQuery, "date_from=2024-07-16")
+	assert.Contains(t, gotQuery, "search=2081-04-01")
+	assert.Equal(t, "2024-07-16T00:00:00Z", gotBody["due_date"])
+	assert.Equal(t, []interface{}{"2024-04-13T00:00:00Z"}, gotBody["due_dates"])
+	assert.Equal(t, "moved from 2081-04-01", gotBody["notes"])
+	assert.Equal(t, 1500.5, gotBody["amount"])
+
+	var resp map[string]interface{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "2081-04-01", resp["due_date"])
+	assert.Equal(t, "2024-07-16T00:00:00Z", resp["created_at"])
+	assert.Equal(t, "paid on 2024-07-16", resp["notes"])
+	assert.Equal(t, "BS", rec.Header().Get(CalendarHeader))
+}
+
+func TestCalendarMiddlewareRejectsBadDates(t *testing.T) {
+	handler := CalendarMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run")
+	}))
+
+	tests := []struct {
+		name  string
+		url   string
+		body  string
+		field string
+	}{
+		{name: "query", url: "/reports?calendar=bs&start_date=2081-13-01", field: "start_date"},
+		{name: "body", url: "/invoices", body: `{"due_date":"2081-01-32"}`, field: "due_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(CalendarHeader, "BS")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			raw, err := io.ReadAll(rec.Body)
+			require.NoError(t, err)
+			var resp struct {
+				Error   string                 `json:"error"`
+				Details map[string]interface{} `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(raw, &resp), string(raw))
+			assert.Contains(t, resp.Error, tt.field)
+			assert.Equal(t, tt.field, resp.Details["field"])
+		})
+	}
+}
+
+func TestCalendarMiddlewarePassesADRequestsThrough(t *testing.T) {
+	handler := CalendarMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"due_date":"2024-07-16"}`)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/invoices", nil))
+
+	assert.JSONEq(t, `{"due_date":"2024-07-16"}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(CalendarHeader))
+}
@@ -14,16 +14,31 @@ import (
 
 type Handlers struct {
 	Auth         *handler.AuthHandler
+	Institute    *handler.InstituteHandler
 	Student      *handler.StudentHandler
+	LicenseTest  *handler.LicenseTestHandler
+	Document     *handler.DocumentHandler
+	Certificate  *handler.CertificateHandler
+	Enrollment   *handler.EnrollmentHandler
+	Vehicle      *handler.VehicleHandler
+	Lesson       *handler.LessonHandler
+	Booking      *handler.BookingHandler
 	Attendance   *handler.AttendanceHandler
+	CheckIn      *handler.CheckInHandler
+	Track        *handler.TrackHandler
+	Skill        *handler.SkillHandler
 	Invoice      *handler.InvoiceHandler
 	Payment      *handler.PaymentHandler
+	Refund       *handler.RefundHandler
+	Installment  *handler.InstallmentHandler
+	Promo        *handler.PromoHandler
 	Employee     *handler.EmployeeHandler
 	Expense      *handler.ExpenseHandler
 	Notification *handler.NotificationHandler
 	Course       *handler.CourseHandler
 	Package      *handler.PackageHandler
 	Report       *handler.ReportHandler
+	File         *handler.FileHandler
 }
 
 type Router struct {
@@ -55,11 +70,12 @@ func (rt *Router) Setup() http.Handler {
 	rt.mux.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", middleware.CalendarHeader},
+		ExposedHeaders:   []string{"Link", middleware.CalendarHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	rt.mux.Use(middleware.CalendarMiddleware)
 
 	rt.mux.Get("/health", rt.healthCheck)
 
@@ -72,15 +88,33 @@ func (rt *Router) Setup() http.Handler {
 
 			// Protected auth routes
 			r.Group(func(r chi.Router) {
-				r.Use(middleware.AuthMiddleware(rt.tokenService))
+				r.Use(middleware.AccountMiddleware(rt.tokenService))
 				r.Get("/me", rt.handlers.Auth.GetMe)
 			})
 		})
 
+		// Signed downloads from local storage (the URL signature authorizes)
+		if rt.handlers.File != nil {
+			r.Get("/files/*", rt.handlers.File.Download)
+		}
+
 		// Protected routes (all require authentication)
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.AuthMiddleware(rt.tokenService))
 
+			// Users
+			r.Put("/users/{id}/assignment", rt.handlers.Auth.Assign)
+
+			// Institutes
+			r.Route("/institutes", func(r chi.Router) {
+				r.Post("/", rt.handlers.Institute.Create)
+				r.Get("/", rt.handlers.Institute.List)
+				r.Get("/{id}", rt.handlers.Institute.GetByID)
+				r.Put("/{id}", rt.handlers.Institute.Update)
+				r.Put("/{id}/logo", rt.handlers.Institute.UploadLogo)
+				r.Delete("/{id}", rt.handlers.Institute.Delete)
+			})
+
 			// Students
 			r.Route("/students", func(r chi.Router) {
 				r.Post("/", rt.handlers.Student.Create)
@@ -88,16 +122,90 @@ func (rt *Router) Setup() http.Handler {
 				r.Get("/{id}", rt.handlers.Student.GetByID)
 				r.Put("/{id}", rt.handlers.Student.Update)
 				r.Delete("/{id}", rt.handlers.Student.Delete)
+
+				r.Route("/{id}/tests", func(r chi.Router) {
+					r.Post("/", rt.handlers.LicenseTest.Create)
+					r.Get("/", rt.handlers.LicenseTest.List)
+					r.Get("/{test_id}", rt.handlers.LicenseTest.GetByID)
+					r.Put("/{test_id}", rt.handlers.LicenseTest.Update)
+					r.Delete("/{test_id}", rt.handlers.LicenseTest.Delete)
+				})
+
+				r.Get("/{id}/progress", rt.handlers.Skill.GetProgress)
+				r.Get("/{id}/installment-plans", rt.handlers.Installment.ListByStudent)
+
+				r.Route("/{id}/documents", func(r chi.Router) {
+					r.Post("/", rt.handlers.Document.Upload)
+					r.Get("/", rt.handlers.Document.List)
+					r.Get("/{document_id}/download", rt.handlers.Document.Download)
+					r.Delete("/{document_id}", rt.handlers.Document.Delete)
+				})
+
+				r.Route("/{id}/certificates", func(r chi.Router) {
+					r.Post("/", rt.handlers.Certificate.Issue)
+					r.Get("/", rt.handlers.Certificate.List)
+					r.Get("/{certificate_id}/download", rt.handlers.Certificate.Download)
+				})
+			})
+
+			// Enrollments
+			r.Route("/enrollments", func(r chi.Router) {
+				r.Post("/", rt.handlers.Enrollment.Create)
+				r.Get("/", rt.handlers.Enrollment.List)
+				r.Get("/{id}", rt.handlers.Enrollment.GetByID)
+				r.Put("/{id}", rt.handlers.Enrollment.Update)
+				r.Delete("/{id}", rt.handlers.Enrollment.Delete)
+			})
+
+			// Vehicles
+			r.Route("/vehicles", func(r chi.Router) {
+				r.Post("/", rt.handlers.Vehicle.Create)
+				r.Get("/", rt.handlers.Vehicle.List)
+				r.Get("/{id}", rt.handlers.Vehicle.GetByID)
+				r.Put("/{id}", rt.handlers.Vehicle.Update)
+				r.Delete("/{id}", rt.handlers.Vehicle.Delete)
+			})
+
+			// Lesson sessions
+			r.Route("/lessons", func(r chi.Router) {
+				r.Post("/", rt.handlers.Lesson.Create)
+				r.Get("/", rt.handlers.Lesson.List)
+				r.Get("/{id}", rt.handlers.Lesson.GetByID)
+				r.Put("/{id}", rt.handlers.Lesson.Update)
+				r.Delete("/{id}", rt.handlers.Lesson.Delete)
+				r.Post("/{id}/students", rt.handlers.Lesson.AddStudents)
+				r.Delete("/{id}/students/{student_id}", rt.handlers.Lesson.RemoveStudent)
+				r.Get("/{id}/checkin-code", rt.handlers.CheckIn.GetCode)
+				r.Get("/{id}/checkin-code.png", rt.handlers.CheckIn.GetCodePNG)
+			})
+
+			// Self-service booking
+			r.Route("/bookings", func(r chi.Router) {
+				r.Get("/slots", rt.handlers.Booking.ListSlots)
+				r.Post("/holds", rt.handlers.Booking.CreateHold)
+				r.Post("/holds/{id}/confirm", rt.handlers.Booking.ConfirmHold)
+				r.Delete("/holds/{id}", rt.handlers.Booking.ReleaseHold)
 			})
 
 			// Attendance
 			r.Route("/attendance", func(r chi.Router) {
 				r.Post("/", rt.handlers.Attendance.MarkAttendance)
+				r.Post("/bulk", rt.handlers.Attendance.MarkBulk)
+				r.Post("/checkin", rt.handlers.CheckIn.CheckIn)
 				r.Get("/", rt.handlers.Attendance.List)
 				r.Get("/{id}", rt.handlers.Attendance.GetByID)
 				r.Put("/{id}", rt.handlers.Attendance.Update)
 				r.Delete("/{id}", rt.handlers.Attendance.Delete)
+				r.Get("/hours", rt.handlers.Attendance.ListHours)
+				r.Put("/{id}/checkout", rt.handlers.Attendance.CheckOut)
+				r.Get("/{id}/history", rt.handlers.Attendance.GetHistory)
+				r.Put("/{id}/track", rt.handlers.Track.Upload)
+				r.Get("/{id}/track", rt.handlers.Track.Get)
+				r.Get("/{id}/track/download", rt.handlers.Track.Download)
 				r.Get("/students/{student_id}/stats", rt.handlers.Attendance.GetStudentStats)
+				r.Get("/students/{student_id}/hours", rt.handlers.Attendance.GetStudentHours)
+				r.Get("/{id}/skills", rt.handlers.Skill.GetScores)
+				r.Put("/{id}/skills", rt.handlers.Skill.Grade)
 			})
 
 			// Invoices
@@ -105,8 +213,9 @@ func (rt *Router) Setup() http.Handler {
 				r.Post("/", rt.handlers.Invoice.Create)
 				r.Get("/", rt.handlers.Invoice.List)
 				r.Get("/{id}", rt.handlers.Invoice.GetByID)
-				r.Put("/{id}/pay", rt.handlers.Invoice.MarkAsPaid)
-				r.Delete("/{id}", rt.handlers.Invoice.Delete)
+				r.Get("/{id}/pdf", rt.handlers.Invoice.DownloadPDF)
+				r.Post("/{id}/credit-notes", rt.handlers.Refund.RequestCreditNote)
+				r.Get("/{id}/credit-notes", rt.handlers.Refund.ListCreditNotes)
 				r.Get("/institutes/{institute_id}/revenue", rt.handlers.Invoice.GetRevenue)
 			})
 
@@ -115,8 +224,32 @@ func (rt *Router) Setup() http.Handler {
 				r.Post("/", rt.handlers.Payment.AddPayment)
 				r.Get("/invoice/{invoice_id}", rt.handlers.Payment.GetPaymentsByInvoice)
 				r.Get("/{id}", rt.handlers.Payment.GetPaymentByID)
+				r.Post("/{id}/reversals", rt.handlers.Refund.RequestReversal)
+				r.Get("/{id}/reversals", rt.handlers.Refund.ListReversals)
 			})
 
+			// Promo codes
+			r.Route("/promo-codes", func(r chi.Router) {
+				r.Post("/", rt.handlers.Promo.Create)
+				r.Get("/", rt.handlers.Promo.List)
+				r.Get("/{id}", rt.handlers.Promo.GetByID)
+				r.Put("/{id}", rt.handlers.Promo.Update)
+				r.Delete("/{id}", rt.handlers.Promo.Delete)
+			})
+
+			// Installment plans
+			r.Route("/installment-plans", func(r chi.Router) {
+				r.Post("/", rt.handlers.Installment.Create)
+				r.Get("/{id}", rt.handlers.Installment.GetByID)
+				r.Put("/{id}/cancel", rt.handlers.Installment.Cancel)
+			})
+
+			// Credit notes and payment reversals
+			r.Put("/credit-notes/{id}/approve", rt.handlers.Refund.ApproveCreditNote)
+			r.Put("/credit-notes/{id}/reject", rt.handlers.Refund.RejectCreditNote)
+			r.Put("/payment-reversals/{id}/approve", rt.handlers.Refund.ApproveReversal)
+			r.Put("/payment-reversals/{id}/reject", rt.handlers.Refund.RejectReversal)
+
 			// Employees
 			r.Route("/employees", func(r chi.Router) {
 				r.Post("/", rt.handlers.Employee.Create)
@@ -125,6 +258,8 @@ func (rt *Router) Setup() http.Handler {
 				r.Put("/{id}", rt.handlers.Employee.Update)
 				r.Delete("/{id}", rt.handlers.Employee.Delete)
 				r.Put("/{id}/terminate", rt.handlers.Employee.Terminate)
+				r.Get("/{id}/working-hours", rt.handlers.Booking.GetWorkingHours)
+				r.Put("/{id}/working-hours", rt.handlers.Booking.SetWorkingHours)
 			})
 
 			// Expenses
@@ -136,6 +271,8 @@ func (rt *Router) Setup() http.Handler {
 				r.Delete("/{id}", rt.handlers.Expense.Delete)
 				r.Put("/{id}/approve", rt.handlers.Expense.Approve)
 				r.Put("/{id}/reject", rt.handlers.Expense.Reject)
+				r.Put("/{id}/receipt", rt.handlers.Expense.UploadReceipt)
+				r.Get("/{id}/receipt", rt.handlers.Expense.GetReceipt)
 				// Analytics endpoint will be implemented later
 			})
 
@@ -158,6 +295,10 @@ func (rt *Router) Setup() http.Handler {
 				r.Get("/code/{code}", rt.handlers.Course.GetByCode)
 				r.Put("/{id}", rt.handlers.Course.Update)
 				r.Delete("/{id}", rt.handlers.Course.Delete)
+				r.Get("/{id}/skills", rt.handlers.Skill.List)
+				r.Post("/{id}/skills", rt.handlers.Skill.Create)
+				r.Put("/{id}/skills/{skill_id}", rt.handlers.Skill.Update)
+				r.Delete("/{id}/skills/{skill_id}", rt.handlers.Skill.Delete)
 			})
 
 			// Packages
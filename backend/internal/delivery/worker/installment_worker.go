@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/usecase"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/hibiken/asynq"
+)
+
+const TypeInstallmentInvoices = "installment:issue_invoices"
+
+type InstallmentWorker struct {
+	useCase *usecase.InstallmentUseCase
+	logger  logger.Logger
+}
+
+func NewInstallmentWorker(useCase *usecase.InstallmentUseCase, logger logger.Logger) *InstallmentWorker {
+	return &InstallmentWorker{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// HandleIssueInvoices runs the daily sweep that invoices installments coming
+// due. It carries no payload and works across all institutes.
+func (w *InstallmentWorker) HandleIssueInvoices(ctx context.Context, t *asynq.Task) error {
+	if _, err := w.useCase.IssueDueInvoices(ctx); err != nil {
+		return fmt.Errorf("installment invoices: %w", err)
+	}
+	return nil
+}
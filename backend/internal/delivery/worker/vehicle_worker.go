@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/usecase"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/hibiken/asynq"
+)
+
+const TypeVehicleDocumentExpiry = "vehicle:document_expiry"
+
+type VehicleWorker struct {
+	useCase        *usecase.VehicleUseCase
+	reminderWindow time.Duration
+	logger         logger.Logger
+}
+
+func NewVehicleWorker(useCase *usecase.VehicleUseCase, reminderWindow time.Duration, logger logger.Logger) *VehicleWorker {
+	return &VehicleWorker{
+		useCase:        useCase,
+		reminderWindow: reminderWindow,
+		logger:         logger,
+	}
+}
+
+// HandleDocumentExpiry runs the daily sweep for vehicle documents nearing
+// expiry. It carries no payload and works across all institutes.
+func (w *VehicleWorker) HandleDocumentExpiry(ctx context.Context, t *asynq.Task) error {
+	if _, err := w.useCase.NotifyExpiringDocuments(ctx, w.reminderWindow); err != nil {
+		return fmt.Errorf("vehicle document expiry: %w", err)
+	}
+	return nil
+}
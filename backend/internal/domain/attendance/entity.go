@@ -3,6 +3,7 @@ package attendance
 import (
 	"time"
 
+	"github.com/chalak/backend/pkg/geo"
 	"github.com/google/uuid"
 )
 
@@ -16,6 +17,7 @@ type Attendance struct {
 	Status             string     `json:"status" gorm:"type:varchar(20);not null"`
 	CheckInAt          *time.Time `json:"check_in_at,omitempty" gorm:"type:timestamp"`
 	CheckOutAt         *time.Time `json:"check_out_at,omitempty" gorm:"type:timestamp"`
+	DurationMinutes    *int       `json:"duration_minutes,omitempty" gorm:"type:int"`
 	Notes              string     `json:"notes" gorm:"type:text"`
 	MarkedBy           uuid.UUID  `json:"marked_by" gorm:"type:uuid;not null"`
 	CreatedAt          time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
@@ -27,6 +29,52 @@ func (Attendance) TableName() string {
 	return "attendances"
 }
 
+// StatusChange is one entry in an attendance record's history. The first
+// entry has no FromStatus and records the status it was marked with.
+type StatusChange struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AttendanceID uuid.UUID `json:"attendance_id" gorm:"type:uuid;not null;index"`
+	FromStatus   *string   `json:"from_status,omitempty" gorm:"type:varchar(20)"`
+	ToStatus     string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	ChangedBy    uuid.UUID `json:"changed_by" gorm:"type:uuid;not null"`
+	ChangedAt    time.Time `json:"changed_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (StatusChange) TableName() string {
+	return "attendance_history"
+}
+
+// Track is the GPS route recorded during a practical lesson. The uploaded
+// GPX or GeoJSON file is kept in storage under FileKey; the summary is
+// computed from it on upload.
+type Track struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AttendanceID    uuid.UUID  `json:"attendance_id" gorm:"type:uuid;not null;uniqueIndex"`
+	FileKey         string     `json:"-" gorm:"type:varchar(255);not null"`
+	FileName        string     `json:"file_name" gorm:"type:varchar(255);not null"`
+	Format          string     `json:"format" gorm:"type:varchar(10);not null"`
+	Size            int64      `json:"size" gorm:"type:bigint;not null"`
+	DistanceMeters  float64    `json:"distance_meters" gorm:"type:decimal(10,1);not null"`
+	DurationSeconds int64      `json:"duration_seconds" gorm:"type:int;not null"`
+	MaxSpeedKPH     float64    `json:"max_speed_kph" gorm:"column:max_speed_kph;type:decimal(6,1);not null"`
+	Points          int        `json:"points" gorm:"type:int;not null"`
+	StartedAt       *time.Time `json:"started_at,omitempty" gorm:"type:timestamp"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" gorm:"type:timestamp"`
+	UploadedBy      uuid.UUID  `json:"uploaded_by" gorm:"type:uuid;not null"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Track) TableName() string {
+	return "attendance_tracks"
+}
+
+// TrackDetail is a track with its route as GeoJSON for drawing on a map.
+type TrackDetail struct {
+	*Track
+	Route geo.Feature `json:"route"`
+}
+
 const (
 	StatusPresent = "present"
 	StatusAbsent  = "absent"
@@ -42,6 +90,79 @@ type MarkAttendanceRequest struct {
 	Notes     string    `json:"notes"`
 }
 
+// BulkMarkRequest marks a whole class roll call at once. Entries are
+// validated together and saved only if every one is valid.
+type BulkMarkRequest struct {
+	ClassID uuid.UUID   `json:"class_id" validate:"required"`
+	Date    time.Time   `json:"date" validate:"required"`
+	Entries []BulkEntry `json:"entries" validate:"required,min=1,max=200,dive"`
+}
+
+type BulkEntry struct {
+	StudentID uuid.UUID `json:"student_id" validate:"required"`
+	Status    string    `json:"status" validate:"required,oneof=present absent late excused"`
+	Notes     string    `json:"notes"`
+}
+
+// BulkResult is the outcome of one entry of a bulk request, in request
+// order.
+type BulkResult struct {
+	Row        int         `json:"row"`
+	StudentID  uuid.UUID   `json:"student_id"`
+	Error      string      `json:"error,omitempty"`
+	Attendance *Attendance `json:"attendance,omitempty"`
+}
+
+// CheckInCode is the rotating token shown as a QR code during a lesson.
+type CheckInCode struct {
+	SessionID uuid.UUID `json:"session_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SelfCheckInRequest carries the token a student scanned from a lesson's QR
+// code.
+type SelfCheckInRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// CheckOutRequest ends a lesson for a checked-in student. CheckOutAt
+// defaults to now.
+type CheckOutRequest struct {
+	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// CourseProgress totals the lessons a student attended on one course.
+type CourseProgress struct {
+	Lessons   int        `json:"lessons"`
+	Hours     float64    `json:"hours"`
+	FirstDate *time.Time `json:"first_date,omitempty"`
+	LastDate  *time.Time `json:"last_date,omitempty"`
+}
+
+// CourseHours compares the hours a student has logged on a course with the
+// hours the course requires.
+type CourseHours struct {
+	StudentID      uuid.UUID `json:"student_id"`
+	StudentName    string    `json:"student_name"`
+	CourseID       uuid.UUID `json:"course_id"`
+	CourseName     string    `json:"course_name"`
+	Lessons        int       `json:"lessons"`
+	LoggedHours    float64   `json:"logged_hours"`
+	RequiredHours  float64   `json:"required_hours"`
+	RemainingHours float64   `json:"remaining_hours"`
+	Completed      bool      `json:"completed"`
+}
+
+// HoursFilter selects the students and courses to report hours for. Every
+// course a student is enrolled in or has attended is included.
+type HoursFilter struct {
+	StudentID *uuid.UUID
+	CourseID  *uuid.UUID
+	Completed *bool
+}
+
 type AttendanceFilter struct {
 	StudentID *uuid.UUID
 	ClassID   *uuid.UUID
@@ -9,9 +9,23 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, attendance *Attendance) error
+	// UpsertBatch saves each record as Upsert does, all in one transaction
+	// or none of them.
+	UpsertBatch(ctx context.Context, attendances []*Attendance) error
+	// Upsert saves the student's attendance for the class and date, updating
+	// the record already marked if there is one. It reports whether a new
+	// record was created; on update attendance is filled from the stored row.
+	Upsert(ctx context.Context, attendance *Attendance) (bool, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*Attendance, error)
 	Update(ctx context.Context, attendance *Attendance) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, filter AttendanceFilter) ([]*Attendance, int64, error)
 	GetStudentAttendanceStats(ctx context.Context, studentID uuid.UUID, dateFrom, dateTo time.Time) (map[string]int, error)
+	ListHistory(ctx context.Context, attendanceID uuid.UUID) ([]*StatusChange, error)
+	GetCourseProgress(ctx context.Context, studentID, courseID uuid.UUID) (*CourseProgress, error)
+	ListCourseHours(ctx context.Context, filter HoursFilter) ([]*CourseHours, error)
+	// SaveTrack stores the GPS track for track.AttendanceID, replacing any
+	// track uploaded before.
+	SaveTrack(ctx context.Context, track *Track) error
+	FindTrack(ctx context.Context, attendanceID uuid.UUID) (*Track, error)
 }
\ No newline at end of file
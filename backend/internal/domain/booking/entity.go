@@ -0,0 +1,74 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkingHours is one weekly window in which an instructor takes lessons.
+// Times are "HH:MM" in Nepal time.
+type WorkingHours struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstructorID uuid.UUID `json:"instructor_id" gorm:"type:uuid;not null;index"`
+	Weekday      int       `json:"weekday" gorm:"type:smallint;not null"`
+	StartTime    string    `json:"start_time" gorm:"type:varchar(5);not null"`
+	EndTime      string    `json:"end_time" gorm:"type:varchar(5);not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (WorkingHours) TableName() string {
+	return "instructor_working_hours"
+}
+
+// Slot is a bookable time range with an instructor.
+type Slot struct {
+	InstructorID uuid.UUID `json:"instructor_id"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+}
+
+// Hold reserves a slot for a student until ExpiresAt. Holds live in Redis
+// only; an unconfirmed hold simply expires.
+type Hold struct {
+	ID           uuid.UUID  `json:"id"`
+	InstituteID  uuid.UUID  `json:"institute_id"`
+	StudentID    uuid.UUID  `json:"student_id"`
+	InstructorID uuid.UUID  `json:"instructor_id"`
+	CourseID     uuid.UUID  `json:"course_id"`
+	VehicleID    *uuid.UUID `json:"vehicle_id,omitempty"`
+	StartTime    time.Time  `json:"start_time"`
+	EndTime      time.Time  `json:"end_time"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+}
+
+type WorkingHoursEntry struct {
+	Weekday   int    `json:"weekday" validate:"min=0,max=6"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+}
+
+// SetWorkingHoursRequest replaces an instructor's whole weekly schedule.
+type SetWorkingHoursRequest struct {
+	Hours []WorkingHoursEntry `json:"hours" validate:"dive"`
+}
+
+type CreateHoldRequest struct {
+	InstructorID    uuid.UUID  `json:"instructor_id" validate:"required"`
+	CourseID        uuid.UUID  `json:"course_id" validate:"required"`
+	VehicleID       *uuid.UUID `json:"vehicle_id,omitempty"`
+	StartTime       time.Time  `json:"start_time" validate:"required"`
+	DurationMinutes int        `json:"duration_minutes" validate:"omitempty,min=15,max=240"`
+}
+
+type SlotFilter struct {
+	InstructorID    *uuid.UUID
+	Date            time.Time
+	DurationMinutes int
+}
+
+type WorkingHoursFilter struct {
+	InstructorID *uuid.UUID
+	Weekday      *int
+}
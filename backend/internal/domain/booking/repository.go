@@ -0,0 +1,12 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	ReplaceWorkingHours(ctx context.Context, instructorID uuid.UUID, hours []*WorkingHours) error
+	ListWorkingHours(ctx context.Context, filter WorkingHoursFilter) ([]*WorkingHours, error)
+}
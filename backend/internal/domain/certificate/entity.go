@@ -0,0 +1,34 @@
+package certificate
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Certificate records a course completion certificate issued to a student.
+// The rendered PDF lives in storage under FileKey.
+type Certificate struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID   uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	StudentID     uuid.UUID  `json:"student_id" gorm:"type:uuid;not null;index"`
+	CourseID      uuid.UUID  `json:"course_id" gorm:"type:uuid;not null"`
+	SerialNumber  string     `json:"serial_number" gorm:"type:varchar(80);not null;uniqueIndex"`
+	AttendedHours float64    `json:"attended_hours" gorm:"type:decimal(8,2);not null"`
+	StartDate     time.Time  `json:"start_date" gorm:"type:date;not null"`
+	CompletedOn   time.Time  `json:"completed_on" gorm:"type:date;not null"`
+	FileKey       string     `json:"-" gorm:"type:varchar(255);not null"`
+	IssuedBy      uuid.UUID  `json:"issued_by" gorm:"type:uuid;not null"`
+	IssuedAt      time.Time  `json:"issued_at" gorm:"type:timestamp;not null"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (Certificate) TableName() string {
+	return "certificates"
+}
+
+type IssueCertificateRequest struct {
+	CourseID uuid.UUID `json:"course_id" validate:"required"`
+}
@@ -0,0 +1,17 @@
+package certificate
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// NextSerial returns the next number from the certificate serial
+	// sequence. Numbers are never reused, even if issuing fails afterwards.
+	NextSerial(ctx context.Context) (int64, error)
+	Create(ctx context.Context, c *Certificate) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Certificate, error)
+	FindByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) (*Certificate, error)
+	ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*Certificate, error)
+}
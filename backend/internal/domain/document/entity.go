@@ -0,0 +1,43 @@
+package document
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Document is a file kept on a student's record, such as their citizenship
+// card or learner license. The file itself lives in storage under FileKey.
+type Document struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	StudentID   uuid.UUID  `json:"student_id" gorm:"type:uuid;not null;index"`
+	Type        string     `json:"type" gorm:"type:varchar(30);not null"`
+	FileKey     string     `json:"-" gorm:"type:varchar(255);not null"`
+	FileName    string     `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType string     `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64      `json:"size" gorm:"type:bigint;not null"`
+	UploadedBy  uuid.UUID  `json:"uploaded_by" gorm:"type:uuid;not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (Document) TableName() string {
+	return "student_documents"
+}
+
+const (
+	TypeCitizenship    = "citizenship"
+	TypePhoto          = "photo"
+	TypeLearnerLicense = "learner_license"
+)
+
+// ValidType reports whether t is a document type students can upload.
+func ValidType(t string) bool {
+	switch t {
+	case TypeCitizenship, TypePhoto, TypeLearnerLicense:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+package document
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, d *Document) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Document, error)
+	FindByStudentAndType(ctx context.Context, studentID uuid.UUID, docType string) (*Document, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*Document, error)
+}
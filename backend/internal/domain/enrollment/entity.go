@@ -0,0 +1,65 @@
+package enrollment
+
+import (
+	"time"
+
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+type Enrollment struct {
+	ID                 uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	StudentID          uuid.UUID    `json:"student_id" gorm:"type:uuid;not null;index"`
+	InstituteID        uuid.UUID    `json:"institute_id" gorm:"type:uuid;not null;index"`
+	PackageID          *uuid.UUID   `json:"package_id,omitempty" gorm:"type:uuid;index"`
+	CourseID           *uuid.UUID   `json:"course_id,omitempty" gorm:"type:uuid;index"`
+	StartDate          time.Time    `json:"start_date" gorm:"type:date;not null"`
+	ExpectedEndDate    *time.Time   `json:"expected_end_date,omitempty" gorm:"type:date"`
+	Status             string       `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
+	Price              money.Amount `json:"price" gorm:"type:decimal(10,2);not null"`
+	DiscountPercentage float64      `json:"discount_percentage" gorm:"type:decimal(5,2);not null;default:0"`
+	Amount             money.Amount `json:"amount" gorm:"type:decimal(10,2);not null"`
+	InvoiceID          *uuid.UUID   `json:"invoice_id,omitempty" gorm:"type:uuid;index"`
+	Notes              string       `json:"notes" gorm:"type:text"`
+	CreatedBy          uuid.UUID    `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt          time.Time    `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time    `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt          *time.Time   `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (Enrollment) TableName() string {
+	return "enrollments"
+}
+
+const (
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+// CreateEnrollmentRequest enrolls a student in exactly one of a package or a
+// course. DueDate applies to the generated invoice and defaults to StartDate.
+type CreateEnrollmentRequest struct {
+	StudentID uuid.UUID  `json:"student_id" validate:"required"`
+	PackageID *uuid.UUID `json:"package_id,omitempty" validate:"required_without=CourseID,excluded_with=CourseID"`
+	CourseID  *uuid.UUID `json:"course_id,omitempty" validate:"required_without=PackageID,excluded_with=PackageID"`
+	StartDate time.Time  `json:"start_date" validate:"required"`
+	DueDate   *time.Time `json:"due_date,omitempty"`
+	Notes     string     `json:"notes"`
+	PromoCode string     `json:"promo_code" validate:"max=50"`
+}
+
+type UpdateEnrollmentRequest struct {
+	Status          *string    `json:"status,omitempty" validate:"omitempty,oneof=active completed cancelled"`
+	ExpectedEndDate *time.Time `json:"expected_end_date,omitempty"`
+	Notes           *string    `json:"notes,omitempty"`
+}
+
+type EnrollmentFilter struct {
+	StudentID *uuid.UUID
+	PackageID *uuid.UUID
+	CourseID  *uuid.UUID
+	Status    *string
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,18 @@
+package enrollment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, e *Enrollment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Enrollment, error)
+	Update(ctx context.Context, e *Enrollment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter EnrollmentFilter) ([]*Enrollment, int64, error)
+	// IsEnrolledInCourse reports whether the student has a live enrollment
+	// in the course itself or in a package that includes it.
+	IsEnrolledInCourse(ctx context.Context, studentID, courseID uuid.UUID) (bool, error)
+}
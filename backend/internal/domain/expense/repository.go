@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/chalak/backend/pkg/money"
 	"github.com/google/uuid"
 )
 
@@ -15,6 +16,6 @@ type Repository interface {
 	List(ctx context.Context, filter ExpenseFilter) ([]*Expense, int64, error)
 	Approve(ctx context.Context, id uuid.UUID, approvedBy uuid.UUID) error
 	Reject(ctx context.Context, id uuid.UUID, rejectedBy uuid.UUID) error
-	GetTotalExpenses(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (float64, error)
-	GetExpensesByCategory(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (map[string]float64, error)
+	GetTotalExpenses(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (money.Amount, error)
+	GetExpensesByCategory(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (map[string]money.Amount, error)
 }
\ No newline at end of file
@@ -0,0 +1,84 @@
+package installment
+
+import (
+	"time"
+
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+// Plan splits what a student owes, usually a package, into installments
+// due on set dates. Each installment gets its own invoice, either all at
+// once when the plan is created or shortly before it falls due.
+type Plan struct {
+	ID               uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID      uuid.UUID     `json:"institute_id" gorm:"type:uuid;not null;index"`
+	StudentID        uuid.UUID     `json:"student_id" gorm:"type:uuid;not null;index"`
+	PackageID        *uuid.UUID    `json:"package_id,omitempty" gorm:"type:uuid"`
+	Description      string        `json:"description" gorm:"type:varchar(255);not null"`
+	TotalAmount      money.Amount  `json:"total_amount" gorm:"type:decimal(10,2);not null"`
+	InstallmentCount int           `json:"installment_count" gorm:"not null"`
+	InvoiceUpfront   bool          `json:"invoice_upfront" gorm:"not null;default:false"`
+	Status           string        `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
+	Installments     []Installment `json:"installments" gorm:"foreignKey:PlanID"`
+	Progress         *Progress     `json:"progress,omitempty" gorm:"-"`
+	CreatedBy        uuid.UUID     `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt        time.Time     `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt        time.Time     `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Plan) TableName() string {
+	return "installment_plans"
+}
+
+// Installment is one scheduled part of a plan. InvoiceID stays empty until
+// its invoice has been issued.
+type Installment struct {
+	ID        uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PlanID    uuid.UUID        `json:"plan_id" gorm:"type:uuid;not null;index"`
+	Sequence  int              `json:"sequence" gorm:"not null"`
+	Amount    money.Amount     `json:"amount" gorm:"type:decimal(10,2);not null"`
+	DueDate   time.Time        `json:"due_date" gorm:"type:date;not null"`
+	InvoiceID *uuid.UUID       `json:"invoice_id,omitempty" gorm:"type:uuid"`
+	Invoice   *invoice.Invoice `json:"invoice,omitempty" gorm:"foreignKey:InvoiceID"`
+	CreatedAt time.Time        `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time        `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Installment) TableName() string {
+	return "installments"
+}
+
+// Progress is how far a student has got through a plan, worked out from the
+// invoices issued so far and the installments still to come.
+type Progress struct {
+	PaidAmount       money.Amount  `json:"paid_amount"`
+	RemainingAmount  money.Amount  `json:"remaining_amount"`
+	InstallmentsPaid int           `json:"installments_paid"`
+	NextDueDate      *time.Time    `json:"next_due_date,omitempty"`
+	NextDueAmount    *money.Amount `json:"next_due_amount,omitempty"`
+	Completed        bool          `json:"completed"`
+}
+
+const (
+	StatusActive   = "active"
+	StatusCanceled = "canceled"
+)
+
+// CreatePlanRequest sets the schedule either as explicit due dates, one per
+// installment, or as a first due date repeated every IntervalMonths.
+// TotalAmount includes tax. With a package and no total, the plan charges the
+// package's discounted price and whatever tax the institute adds to it.
+type CreatePlanRequest struct {
+	StudentID      uuid.UUID    `json:"student_id" validate:"required"`
+	InstituteID    uuid.UUID    `json:"institute_id"`
+	PackageID      *uuid.UUID   `json:"package_id"`
+	Description    string       `json:"description" validate:"max=255"`
+	TotalAmount    money.Amount `json:"total_amount" validate:"gte=0"`
+	Installments   int          `json:"installments" validate:"required,min=2,max=12"`
+	FirstDueDate   time.Time    `json:"first_due_date"`
+	IntervalMonths int          `json:"interval_months" validate:"omitempty,min=1,max=12"`
+	DueDates       []time.Time  `json:"due_dates"`
+	InvoiceUpfront bool         `json:"invoice_upfront"`
+}
@@ -0,0 +1,25 @@
+package installment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create saves the plan together with its installments.
+	Create(ctx context.Context, plan *Plan) error
+	// FindByID loads the plan with its installments in order and the
+	// invoices issued for them.
+	FindByID(ctx context.Context, id uuid.UUID) (*Plan, error)
+	ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*Plan, error)
+	Update(ctx context.Context, plan *Plan) error
+	// FindInstallmentForUpdate locks the installment row until the
+	// surrounding transaction ends, so its invoice is only issued once.
+	FindInstallmentForUpdate(ctx context.Context, id uuid.UUID) (*Installment, error)
+	UpdateInstallment(ctx context.Context, inst *Installment) error
+	// FindUninvoiced returns active plans with installments due before the
+	// given time that have no invoice yet, loading only those installments.
+	FindUninvoiced(ctx context.Context, dueBefore time.Time) ([]*Plan, error)
+}
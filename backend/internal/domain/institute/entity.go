@@ -0,0 +1,117 @@
+package institute
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Institute struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string     `json:"name" gorm:"type:varchar(255);not null"`
+	Code      string     `json:"code" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Email     string     `json:"email" gorm:"type:varchar(255)"`
+	Phone     string     `json:"phone" gorm:"type:varchar(20)"`
+	Address   string     `json:"address" gorm:"type:text"`
+	Status    string     `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
+	Tax       TaxConfig  `json:"tax" gorm:"embedded;embeddedPrefix:tax_"`
+	Branding  Branding   `json:"branding" gorm:"embedded;embeddedPrefix:invoice_"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+	// TaxExemptions are the packages and courses billed without tax, such
+	// as exam fees collected on the licensing office's behalf.
+	TaxExemptions []TaxExemption `json:"tax_exemptions" gorm:"foreignKey:InstituteID"`
+}
+
+// TaxConfig is how an institute charges tax on its invoices. A zero Rate
+// means the institute is not registered for tax and invoices carry none.
+// With Inclusive set, item prices already include tax and it is worked out
+// of them rather than added on top.
+type TaxConfig struct {
+	Name      string  `json:"name" gorm:"type:varchar(20);not null;default:'VAT'"`
+	Rate      float64 `json:"rate" gorm:"type:decimal(5,2);not null;default:0"`
+	Inclusive bool    `json:"inclusive" gorm:"not null;default:false"`
+	Number    string  `json:"number" gorm:"type:varchar(30)"`
+}
+
+// TaxExemption frees the invoice items billing a package or a course from
+// tax. Exactly one of the two is set.
+type TaxExemption struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	PackageID   *uuid.UUID `json:"package_id,omitempty" gorm:"type:uuid"`
+	CourseID    *uuid.UUID `json:"course_id,omitempty" gorm:"type:uuid"`
+}
+
+func (TaxExemption) TableName() string {
+	return "institute_tax_exemptions"
+}
+
+// Branding is what an institute prints on its invoices besides its name and
+// contact details. LogoKey is the storage key of the uploaded logo.
+type Branding struct {
+	LogoKey string `json:"logo_key,omitempty" gorm:"type:varchar(255)"`
+	Footer  string `json:"footer" gorm:"type:text"`
+}
+
+func (Institute) TableName() string {
+	return "institutes"
+}
+
+// TaxExempt reports whether an item billing the package or course is free of
+// tax. Items that name neither are always taxed.
+func (i *Institute) TaxExempt(packageID, courseID *uuid.UUID) bool {
+	for _, e := range i.TaxExemptions {
+		if (e.PackageID != nil && packageID != nil && *e.PackageID == *packageID) ||
+			(e.CourseID != nil && courseID != nil && *e.CourseID == *courseID) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+type CreateInstituteRequest struct {
+	Name    string `json:"name" validate:"required,min=2,max=255"`
+	Code    string `json:"code" validate:"required,min=2,max=50"`
+	Email   string `json:"email" validate:"omitempty,email"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+}
+
+type UpdateInstituteRequest struct {
+	Name     *string                 `json:"name,omitempty" validate:"omitempty,min=2,max=255"`
+	Email    *string                 `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    *string                 `json:"phone,omitempty"`
+	Address  *string                 `json:"address,omitempty"`
+	Status   *string                 `json:"status,omitempty" validate:"omitempty,oneof=active inactive"`
+	Tax      *UpdateTaxConfigRequest `json:"tax,omitempty"`
+	Branding *UpdateBrandingRequest  `json:"branding,omitempty"`
+}
+
+type UpdateTaxConfigRequest struct {
+	Name      *string  `json:"name,omitempty" validate:"omitempty,min=1,max=20"`
+	Rate      *float64 `json:"rate,omitempty" validate:"omitempty,gte=0,lt=100"`
+	Inclusive *bool    `json:"inclusive,omitempty"`
+	Number    *string  `json:"number,omitempty" validate:"omitempty,max=30"`
+	// ExemptPackageIDs and ExemptCourseIDs replace the institute's tax
+	// exemptions of that kind.
+	ExemptPackageIDs *[]uuid.UUID `json:"exempt_package_ids,omitempty"`
+	ExemptCourseIDs  *[]uuid.UUID `json:"exempt_course_ids,omitempty"`
+}
+
+type UpdateBrandingRequest struct {
+	Footer *string `json:"footer,omitempty" validate:"omitempty,max=500"`
+}
+
+type InstituteFilter struct {
+	Status *string
+	Search *string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,17 @@
+package institute
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, institute *Institute) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Institute, error)
+	FindByCode(ctx context.Context, code string) (*Institute, error)
+	Update(ctx context.Context, institute *Institute) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter InstituteFilter) ([]*Institute, int64, error)
+	IsActive(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -3,43 +3,69 @@ package invoice
 import (
 	"time"
 
+	"github.com/chalak/backend/pkg/money"
 	"github.com/google/uuid"
 )
 
 type Invoice struct {
-	ID            uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	InvoiceNumber string        `json:"invoice_number" gorm:"type:varchar(50);uniqueIndex;not null"`
-	StudentID     uuid.UUID     `json:"student_id" gorm:"type:uuid;not null;index"`
-	InstituteID   uuid.UUID     `json:"institute_id" gorm:"type:uuid;not null;index"`
-	Amount        float64       `json:"amount" gorm:"type:decimal(10,2);not null"`
-	TaxAmount     float64       `json:"tax_amount" gorm:"type:decimal(10,2);default:0"`
-	TotalAmount   float64       `json:"total_amount" gorm:"type:decimal(10,2);not null"`
-	PaidAmount    float64       `json:"paid_amount" gorm:"type:decimal(10,2);not null;default:0"`
-	Status        string        `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
-	DueDate       time.Time     `json:"due_date" gorm:"type:date;not null"`
-	PaidAt        *time.Time    `json:"paid_at,omitempty" gorm:"type:timestamp"`
-	Notes         string        `json:"notes" gorm:"type:text"`
-	Items         []InvoiceItem `json:"items" gorm:"foreignKey:InvoiceID"`
-	CreatedBy     uuid.UUID     `json:"created_by" gorm:"type:uuid;not null"`
-	CreatedAt     time.Time     `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt     time.Time     `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	DeletedAt     *time.Time    `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+	ID             uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InvoiceNumber  string        `json:"invoice_number" gorm:"type:varchar(50);uniqueIndex;not null"`
+	StudentID      uuid.UUID     `json:"student_id" gorm:"type:uuid;not null;index"`
+	InstituteID    uuid.UUID     `json:"institute_id" gorm:"type:uuid;not null;index"`
+	Amount         money.Amount  `json:"amount" gorm:"type:decimal(10,2);not null"`
+	TaxableAmount  money.Amount  `json:"taxable_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	ExemptAmount   money.Amount  `json:"exempt_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	TaxName        string        `json:"tax_name,omitempty" gorm:"type:varchar(20)"`
+	TaxInclusive   bool          `json:"tax_inclusive" gorm:"not null;default:false"`
+	TaxAmount      money.Amount  `json:"tax_amount" gorm:"type:decimal(10,2);default:0"`
+	TotalAmount    money.Amount  `json:"total_amount" gorm:"type:decimal(10,2);not null"`
+	DiscountAmount money.Amount  `json:"discount_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	PromoCodeID    *uuid.UUID    `json:"promo_code_id,omitempty" gorm:"type:uuid"`
+	CreditedAmount money.Amount  `json:"credited_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	PaidAmount     money.Amount  `json:"paid_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	Status         string        `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	DueDate        time.Time     `json:"due_date" gorm:"type:date;not null"`
+	PaidAt         *time.Time    `json:"paid_at,omitempty" gorm:"type:timestamp"`
+	Notes          string        `json:"notes" gorm:"type:text"`
+	Items          []InvoiceItem `json:"items" gorm:"foreignKey:InvoiceID"`
+	CreatedBy      uuid.UUID     `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time     `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt      *time.Time    `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
 }
 
 func (Invoice) TableName() string {
 	return "invoices"
 }
 
+// AmountDue is what the student owes in total once approved credit notes are
+// taken off.
+func (i *Invoice) AmountDue() money.Amount {
+	return i.TotalAmount - i.CreditedAmount
+}
+
+// Balance is what is still to be paid. It is negative when the student has
+// paid more than is now due and is owed a refund.
+func (i *Invoice) Balance() money.Amount {
+	return i.AmountDue() - i.PaidAmount
+}
+
 type InvoiceItem struct {
-	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	InvoiceID   uuid.UUID  `json:"invoice_id" gorm:"type:uuid;not null;index"`
-	Description string     `json:"description" gorm:"type:varchar(255);not null"`
-	Quantity    int        `json:"quantity" gorm:"type:int;not null;default:1"`
-	UnitPrice   float64    `json:"unit_price" gorm:"type:decimal(10,2);not null"`
-	Amount      float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
-	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt   time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp"`
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InvoiceID   uuid.UUID    `json:"invoice_id" gorm:"type:uuid;not null;index"`
+	Description string       `json:"description" gorm:"type:varchar(255);not null"`
+	Quantity    int          `json:"quantity" gorm:"type:int;not null;default:1"`
+	UnitPrice   money.Amount `json:"unit_price" gorm:"type:decimal(10,2);not null"`
+	Amount      money.Amount `json:"amount" gorm:"type:decimal(10,2);not null"`
+	TaxExempt   bool         `json:"tax_exempt" gorm:"not null;default:false"`
+	TaxRate     float64      `json:"tax_rate" gorm:"type:decimal(5,2);not null;default:0"`
+	TaxAmount   money.Amount `json:"tax_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	PackageID   *uuid.UUID   `json:"package_id,omitempty" gorm:"type:uuid"`
+	CourseID    *uuid.UUID   `json:"course_id,omitempty" gorm:"type:uuid"`
+	IsDiscount  bool         `json:"is_discount" gorm:"not null;default:false"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time    `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty" gorm:"type:timestamp"`
 }
 
 func (InvoiceItem) TableName() string {
@@ -55,16 +81,32 @@ const (
 
 type CreateInvoiceRequest struct {
 	StudentID   uuid.UUID            `json:"student_id" validate:"required"`
-	InstituteID uuid.UUID            `json:"institute_id" validate:"required"`
+	InstituteID uuid.UUID            `json:"institute_id"`
 	DueDate     time.Time            `json:"due_date" validate:"required"`
 	Notes       string               `json:"notes"`
+	PromoCode   string               `json:"promo_code" validate:"max=50"`
 	Items       []CreateInvoiceItem  `json:"items" validate:"required,min=1,dive"`
+	// TaxInclusive prices the items as already including tax, whatever the
+	// institute charges by default, so the invoice totals exactly what was
+	// agreed beforehand. Clients cannot set it.
+	TaxInclusive bool `json:"-"`
 }
 
+// CreateInvoiceItem may name the package or course it bills, which decides
+// whether it is taxed and whether a promo code restricted to those applies
+// to it.
 type CreateInvoiceItem struct {
-	Description string  `json:"description" validate:"required"`
-	Quantity    int     `json:"quantity" validate:"required,gte=1"`
-	UnitPrice   float64 `json:"unit_price" validate:"required,gte=0"`
+	Description string       `json:"description" validate:"required"`
+	Quantity    int          `json:"quantity" validate:"required,gte=1"`
+	UnitPrice   money.Amount `json:"unit_price" validate:"gte=0"`
+	PackageID   *uuid.UUID   `json:"package_id"`
+	CourseID    *uuid.UUID   `json:"course_id"`
+	// TaxExempt follows from the institute's tax exemptions for the package
+	// or course billed. Clients cannot set it.
+	TaxExempt bool `json:"-"`
+	// IsDiscount marks the negative lines a promo code adds. Clients cannot
+	// set it.
+	IsDiscount bool `json:"-"`
 }
 
 type InvoiceFilter struct {
@@ -4,16 +4,23 @@ import (
 	"context"
 	"time"
 
+	"github.com/chalak/backend/pkg/money"
 	"github.com/google/uuid"
 )
 
 type Repository interface {
 	Create(ctx context.Context, invoice *Invoice) error
+	// NextNumber allocates the next invoice number in the institute's fiscal
+	// year. It must run in the transaction that creates the invoice: the
+	// counter stays locked until it commits, and rolls back with it, so
+	// numbers are neither repeated nor skipped.
+	NextNumber(ctx context.Context, instituteID uuid.UUID, fiscalYear string) (int64, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*Invoice, error)
+	// FindByIDForUpdate loads the invoice without its items and locks the
+	// row until the surrounding transaction ends.
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*Invoice, error)
 	FindByInvoiceNumber(ctx context.Context, invoiceNumber string) (*Invoice, error)
 	Update(ctx context.Context, invoice *Invoice) error
-	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, filter InvoiceFilter) ([]*Invoice, int64, error)
-	MarkAsPaid(ctx context.Context, id uuid.UUID) error
-	GetTotalRevenue(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (float64, error)
+	GetTotalRevenue(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (money.Amount, error)
 }
\ No newline at end of file
@@ -0,0 +1,95 @@
+package lesson
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a scheduled lesson. Attendance records reference it through
+// their ClassID.
+type Session struct {
+	ID           uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID  uuid.UUID   `json:"institute_id" gorm:"type:uuid;not null;index"`
+	CourseID     uuid.UUID   `json:"course_id" gorm:"type:uuid;not null;index"`
+	InstructorID uuid.UUID   `json:"instructor_id" gorm:"type:uuid;not null;index"`
+	VehicleID    *uuid.UUID  `json:"vehicle_id,omitempty" gorm:"type:uuid;index"`
+	StartTime    time.Time   `json:"start_time" gorm:"type:timestamp;not null;index"`
+	EndTime      time.Time   `json:"end_time" gorm:"type:timestamp;not null"`
+	Type         string      `json:"type" gorm:"type:varchar(20);not null"`
+	Capacity     int         `json:"capacity" gorm:"type:int;not null"`
+	Location     string      `json:"location" gorm:"type:varchar(255)"`
+	Status       string      `json:"status" gorm:"type:varchar(20);not null;default:'scheduled'"`
+	Notes        string      `json:"notes" gorm:"type:text"`
+	StudentIDs   []uuid.UUID `json:"student_ids" gorm:"-"`
+	CreatedAt    time.Time   `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time   `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt    *time.Time  `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (Session) TableName() string {
+	return "lesson_sessions"
+}
+
+// SessionStudent is a student on a session's roster.
+type SessionStudent struct {
+	SessionID uuid.UUID `json:"session_id" gorm:"type:uuid;primary_key"`
+	StudentID uuid.UUID `json:"student_id" gorm:"type:uuid;primary_key"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (SessionStudent) TableName() string {
+	return "lesson_session_students"
+}
+
+const (
+	TypeTheory    = "theory"
+	TypePractical = "practical"
+)
+
+const (
+	StatusScheduled = "scheduled"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+type CreateSessionRequest struct {
+	CourseID     uuid.UUID   `json:"course_id" validate:"required"`
+	InstructorID uuid.UUID   `json:"instructor_id" validate:"required"`
+	VehicleID    *uuid.UUID  `json:"vehicle_id,omitempty"`
+	StartTime    time.Time   `json:"start_time" validate:"required"`
+	EndTime      time.Time   `json:"end_time" validate:"required,gtfield=StartTime"`
+	Type         string      `json:"type" validate:"required,oneof=theory practical"`
+	Capacity     int         `json:"capacity" validate:"required,min=1"`
+	Location     string      `json:"location" validate:"max=255"`
+	Notes        string      `json:"notes"`
+	StudentIDs   []uuid.UUID `json:"student_ids"`
+}
+
+type UpdateSessionRequest struct {
+	InstructorID *uuid.UUID `json:"instructor_id,omitempty"`
+	VehicleID    *uuid.UUID `json:"vehicle_id,omitempty"`
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	Type         *string    `json:"type,omitempty" validate:"omitempty,oneof=theory practical"`
+	Capacity     *int       `json:"capacity,omitempty" validate:"omitempty,min=1"`
+	Location     *string    `json:"location,omitempty" validate:"omitempty,max=255"`
+	Status       *string    `json:"status,omitempty" validate:"omitempty,oneof=scheduled completed cancelled"`
+	Notes        *string    `json:"notes,omitempty"`
+}
+
+type RosterRequest struct {
+	StudentIDs []uuid.UUID `json:"student_ids" validate:"required,min=1"`
+}
+
+type SessionFilter struct {
+	CourseID     *uuid.UUID
+	InstructorID *uuid.UUID
+	StudentID    *uuid.UUID
+	Type         *string
+	Status       *string
+	DateFrom     *time.Time
+	DateTo       *time.Time
+	Limit        int
+	Offset       int
+}
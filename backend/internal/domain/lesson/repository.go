@@ -0,0 +1,22 @@
+package lesson
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository writes that book resources (Create, Update, AddStudents) check
+// for overlapping sessions in the same transaction and fail with a
+// *ConflictError instead of double-booking.
+type Repository interface {
+	Create(ctx context.Context, session *Session) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	Update(ctx context.Context, session *Session) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter SessionFilter) ([]*Session, int64, error)
+	AddStudents(ctx context.Context, session *Session, studentIDs []uuid.UUID) error
+	RemoveStudent(ctx context.Context, sessionID, studentID uuid.UUID) error
+	GetStudents(ctx context.Context, sessionID uuid.UUID) ([]uuid.UUID, error)
+	HasStudent(ctx context.Context, sessionID, studentID uuid.UUID) (bool, error)
+}
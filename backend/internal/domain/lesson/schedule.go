@@ -0,0 +1,38 @@
+package lesson
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resources that a session books for its whole time range.
+const (
+	ResourceInstructor = "instructor"
+	ResourceVehicle    = "vehicle"
+	ResourceStudent    = "student"
+)
+
+// Conflict is an existing session holding one of the resources over an
+// overlapping time range. Ranges are half-open, so back-to-back lessons do
+// not conflict.
+type Conflict struct {
+	Resource   string    `json:"resource"`
+	ResourceID uuid.UUID `json:"resource_id"`
+	SessionID  uuid.UUID `json:"session_id"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// ConflictError is returned by repository writes that would double-book an
+// instructor, vehicle or student. Conflicts is only empty when a database
+// constraint caught an overlap that was gone again by the time it was looked
+// up.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("lesson session overlaps %d existing booking(s)", len(e.Conflicts))
+}
@@ -0,0 +1,68 @@
+package licensetest
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LicenseTest is one attempt at a government license exam: the written test
+// or the trial (road) test. Attempts are numbered per student and type.
+type LicenseTest struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID   uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	StudentID     uuid.UUID  `json:"student_id" gorm:"type:uuid;not null;index"`
+	CourseID      *uuid.UUID `json:"course_id,omitempty" gorm:"type:uuid;index"`
+	InstructorID  *uuid.UUID `json:"instructor_id,omitempty" gorm:"type:uuid;index"`
+	Type          string     `json:"type" gorm:"type:varchar(20);not null"`
+	ScheduledDate time.Time  `json:"scheduled_date" gorm:"type:date;not null"`
+	TestCenter    string     `json:"test_center" gorm:"type:varchar(255);not null"`
+	Result        string     `json:"result" gorm:"type:varchar(20);not null;default:'pending'"`
+	AttemptNumber int        `json:"attempt_number" gorm:"type:int;not null"`
+	Remarks       string     `json:"remarks" gorm:"type:text"`
+	CreatedBy     uuid.UUID  `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (LicenseTest) TableName() string {
+	return "license_tests"
+}
+
+const (
+	TypeWritten = "written"
+	TypeTrial   = "trial"
+)
+
+const (
+	ResultPending = "pending"
+	ResultPassed  = "passed"
+	ResultFailed  = "failed"
+	ResultAbsent  = "absent"
+)
+
+type CreateLicenseTestRequest struct {
+	Type          string     `json:"type" validate:"required,oneof=written trial"`
+	ScheduledDate time.Time  `json:"scheduled_date" validate:"required"`
+	TestCenter    string     `json:"test_center" validate:"required,max=255"`
+	CourseID      *uuid.UUID `json:"course_id,omitempty"`
+	InstructorID  *uuid.UUID `json:"instructor_id,omitempty"`
+	Remarks       string     `json:"remarks"`
+}
+
+type UpdateLicenseTestRequest struct {
+	ScheduledDate *time.Time `json:"scheduled_date,omitempty"`
+	TestCenter    *string    `json:"test_center,omitempty" validate:"omitempty,max=255"`
+	Result        *string    `json:"result,omitempty" validate:"omitempty,oneof=pending passed failed absent"`
+	InstructorID  *uuid.UUID `json:"instructor_id,omitempty"`
+	Remarks       *string    `json:"remarks,omitempty"`
+}
+
+type LicenseTestFilter struct {
+	StudentID *uuid.UUID
+	Type      *string
+	Result    *string
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,15 @@
+package licensetest
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, t *LicenseTest) error
+	FindByID(ctx context.Context, id uuid.UUID) (*LicenseTest, error)
+	Update(ctx context.Context, t *LicenseTest) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter LicenseTestFilter) ([]*LicenseTest, int64, error)
+}
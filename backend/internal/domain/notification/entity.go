@@ -32,6 +32,7 @@ const (
 	TypePayment    = "payment"
 	TypeAnnouncement = "announcement"
 	TypeReminder   = "reminder"
+	TypeLesson     = "lesson"
 
 	SentViaPush  = "push"
 	SentViaEmail = "email"
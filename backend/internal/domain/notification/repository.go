@@ -15,4 +15,7 @@ type Repository interface {
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
 	GetUnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
+	// ExistsWithData reports whether the user already has a notification of
+	// the given type whose JSON data contains data.
+	ExistsWithData(ctx context.Context, userID uuid.UUID, notifType string, data string) (bool, error)
 }
\ No newline at end of file
@@ -0,0 +1,109 @@
+package promo
+
+import (
+	"time"
+
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+// PromoCode is a discount a student can claim when they are invoiced. It
+// takes either a percentage or a fixed amount off the items it applies to:
+// every item, or only those billing one of its target packages or courses.
+// Zero usage limits mean unlimited.
+type PromoCode struct {
+	ID                uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID       uuid.UUID    `json:"institute_id" gorm:"type:uuid;not null;index"`
+	Code              string       `json:"code" gorm:"type:varchar(50);not null"`
+	Description       string       `json:"description" gorm:"type:varchar(255)"`
+	DiscountType      string       `json:"discount_type" gorm:"type:varchar(20);not null"`
+	Percentage        float64      `json:"percentage,omitempty" gorm:"type:decimal(5,2);not null;default:0"`
+	Amount            money.Amount `json:"amount,omitempty" gorm:"type:decimal(10,2);not null;default:0"`
+	ValidFrom         *time.Time   `json:"valid_from,omitempty" gorm:"type:timestamp"`
+	ValidUntil        *time.Time   `json:"valid_until,omitempty" gorm:"type:timestamp"`
+	MaxUses           int          `json:"max_uses" gorm:"not null;default:0"`
+	MaxUsesPerStudent int          `json:"max_uses_per_student" gorm:"not null;default:0"`
+	IsActive          bool         `json:"is_active" gorm:"not null;default:true"`
+	Targets           []Target     `json:"targets" gorm:"foreignKey:PromoCodeID"`
+	Usage             *Usage       `json:"usage,omitempty" gorm:"-"`
+	CreatedBy         uuid.UUID    `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt         time.Time    `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt         time.Time    `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt         *time.Time   `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (PromoCode) TableName() string {
+	return "promo_codes"
+}
+
+// Target limits a promo code to a package or a course. Exactly one of the
+// two is set.
+type Target struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PromoCodeID uuid.UUID  `json:"promo_code_id" gorm:"type:uuid;not null;index"`
+	PackageID   *uuid.UUID `json:"package_id,omitempty" gorm:"type:uuid"`
+	CourseID    *uuid.UUID `json:"course_id,omitempty" gorm:"type:uuid"`
+}
+
+func (Target) TableName() string {
+	return "promo_code_targets"
+}
+
+// Redemption records a promo code used on an invoice and the discount it
+// gave. Redemptions on canceled invoices no longer count towards the limits.
+type Redemption struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PromoCodeID uuid.UUID    `json:"promo_code_id" gorm:"type:uuid;not null;index"`
+	InstituteID uuid.UUID    `json:"institute_id" gorm:"type:uuid;not null"`
+	StudentID   uuid.UUID    `json:"student_id" gorm:"type:uuid;not null"`
+	InvoiceID   uuid.UUID    `json:"invoice_id" gorm:"type:uuid;not null"`
+	Amount      money.Amount `json:"amount" gorm:"type:decimal(10,2);not null"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Redemption) TableName() string {
+	return "promo_redemptions"
+}
+
+// Usage is how often a promo code has been redeemed and what it has cost.
+type Usage struct {
+	Uses     int          `json:"uses"`
+	Discount money.Amount `json:"discount"`
+}
+
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+type CreatePromoCodeRequest struct {
+	InstituteID       uuid.UUID    `json:"institute_id"`
+	Code              string       `json:"code" validate:"required,min=3,max=50"`
+	Description       string       `json:"description" validate:"max=255"`
+	DiscountType      string       `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	Percentage        float64      `json:"percentage" validate:"omitempty,gt=0,max=100"`
+	Amount            money.Amount `json:"amount" validate:"omitempty,gt=0"`
+	ValidFrom         *time.Time   `json:"valid_from"`
+	ValidUntil        *time.Time   `json:"valid_until"`
+	MaxUses           int          `json:"max_uses" validate:"min=0"`
+	MaxUsesPerStudent int          `json:"max_uses_per_student" validate:"min=0"`
+	PackageIDs        []uuid.UUID  `json:"package_ids"`
+	CourseIDs         []uuid.UUID  `json:"course_ids"`
+}
+
+// UpdatePromoCodeRequest changes when and how often a code can be used. The
+// discount itself is fixed once created so past redemptions stay explained.
+type UpdatePromoCodeRequest struct {
+	Description       *string    `json:"description,omitempty" validate:"omitempty,max=255"`
+	ValidFrom         *time.Time `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time `json:"valid_until,omitempty"`
+	MaxUses           *int       `json:"max_uses,omitempty" validate:"omitempty,min=0"`
+	MaxUsesPerStudent *int       `json:"max_uses_per_student,omitempty" validate:"omitempty,min=0"`
+	IsActive          *bool      `json:"is_active,omitempty"`
+}
+
+type PromoCodeFilter struct {
+	IsActive *bool
+	Limit    int
+	Offset   int
+}
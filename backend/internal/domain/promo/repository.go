@@ -0,0 +1,26 @@
+package promo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// Create saves the code together with its targets.
+	Create(ctx context.Context, code *PromoCode) error
+	FindByID(ctx context.Context, id uuid.UUID) (*PromoCode, error)
+	// FindByCodeForUpdate looks a code up in an institute, ignoring case, and
+	// locks it until the surrounding transaction ends so concurrent
+	// invoices cannot redeem it past its limits.
+	FindByCodeForUpdate(ctx context.Context, instituteID uuid.UUID, code string) (*PromoCode, error)
+	Update(ctx context.Context, code *PromoCode) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter PromoCodeFilter) ([]*PromoCode, int64, error)
+
+	CreateRedemption(ctx context.Context, redemption *Redemption) error
+	// CountRedemptions counts redemptions of a code on invoices that still
+	// exist, only those of one student when studentID is set.
+	CountRedemptions(ctx context.Context, promoCodeID uuid.UUID, studentID *uuid.UUID) (int, error)
+	Usage(ctx context.Context, promoCodeID uuid.UUID) (*Usage, error)
+}
@@ -0,0 +1,67 @@
+package refund
+
+import (
+	"time"
+
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+// CreditNote lowers what a student owes on an invoice, for example when they
+// drop out before finishing the course. It takes effect once approved; money
+// already paid above the new amount due is returned with a Reversal.
+type CreditNote struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID uuid.UUID    `json:"institute_id" gorm:"type:uuid;not null;index"`
+	InvoiceID   uuid.UUID    `json:"invoice_id" gorm:"type:uuid;not null;index"`
+	Amount      money.Amount `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Reason      string       `json:"reason" gorm:"type:text;not null"`
+	Status      string       `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	RequestedBy uuid.UUID    `json:"requested_by" gorm:"type:uuid;not null"`
+	ApprovedBy  *uuid.UUID   `json:"approved_by,omitempty" gorm:"type:uuid"`
+	ApprovedAt  *time.Time   `json:"approved_at,omitempty" gorm:"type:timestamp"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time    `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (CreditNote) TableName() string {
+	return "credit_notes"
+}
+
+// Reversal takes back all or part of a payment, such as a refund or a
+// bounced cheque. The payment itself is never changed; once the reversal is
+// approved the invoice's paid amount drops by the reversed amount.
+type Reversal struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID uuid.UUID    `json:"institute_id" gorm:"type:uuid;not null;index"`
+	InvoiceID   uuid.UUID    `json:"invoice_id" gorm:"type:uuid;not null;index"`
+	PaymentID   uuid.UUID    `json:"payment_id" gorm:"type:uuid;not null;index"`
+	Amount      money.Amount `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Reason      string       `json:"reason" gorm:"type:text;not null"`
+	Status      string       `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	RequestedBy uuid.UUID    `json:"requested_by" gorm:"type:uuid;not null"`
+	ApprovedBy  *uuid.UUID   `json:"approved_by,omitempty" gorm:"type:uuid"`
+	ApprovedAt  *time.Time   `json:"approved_at,omitempty" gorm:"type:timestamp"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time    `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Reversal) TableName() string {
+	return "payment_reversals"
+}
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+type CreateCreditNoteRequest struct {
+	Amount money.Amount `json:"amount" validate:"required,gt=0"`
+	Reason string       `json:"reason" validate:"required,min=3,max=1000"`
+}
+
+type CreateReversalRequest struct {
+	Amount money.Amount `json:"amount" validate:"required,gt=0"`
+	Reason string       `json:"reason" validate:"required,min=3,max=1000"`
+}
@@ -0,0 +1,23 @@
+package refund
+
+import (
+	"context"
+
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	CreateCreditNote(ctx context.Context, note *CreditNote) error
+	FindCreditNote(ctx context.Context, id uuid.UUID) (*CreditNote, error)
+	ListCreditNotes(ctx context.Context, invoiceID uuid.UUID) ([]*CreditNote, error)
+	UpdateCreditNote(ctx context.Context, note *CreditNote) error
+
+	CreateReversal(ctx context.Context, reversal *Reversal) error
+	FindReversal(ctx context.Context, id uuid.UUID) (*Reversal, error)
+	ListReversals(ctx context.Context, paymentID uuid.UUID) ([]*Reversal, error)
+	UpdateReversal(ctx context.Context, reversal *Reversal) error
+	// ReversedAmount totals the pending and approved reversals of a payment,
+	// which is how much of it can no longer be reversed.
+	ReversedAmount(ctx context.Context, paymentID uuid.UUID) (money.Amount, error)
+}
@@ -1,6 +1,10 @@
 package report
 
-import "time"
+import (
+	"time"
+
+	"github.com/chalak/backend/pkg/money"
+)
 
 // ReportType represents the type of report
 type ReportType string
@@ -15,17 +19,17 @@ const (
 
 // AttendanceReport represents attendance statistics
 type AttendanceReport struct {
-	StartDate       time.Time              `json:"start_date"`
-	EndDate         time.Time              `json:"end_date"`
-	TotalStudents   int                    `json:"total_students"`
-	TotalDays       int                    `json:"total_days"`
-	PresentCount    int                    `json:"present_count"`
-	AbsentCount     int                    `json:"absent_count"`
-	LateCount       int                    `json:"late_count"`
-	ExcusedCount    int                    `json:"excused_count"`
-	AttendanceRate  float64                `json:"attendance_rate"`
-	DailyStats      []DailyAttendanceStat  `json:"daily_stats"`
-	StudentStats    []StudentAttendanceStat `json:"student_stats,omitempty"`
+	StartDate      time.Time               `json:"start_date"`
+	EndDate        time.Time               `json:"end_date"`
+	TotalStudents  int                     `json:"total_students"`
+	TotalDays      int                     `json:"total_days"`
+	PresentCount   int                     `json:"present_count"`
+	AbsentCount    int                     `json:"absent_count"`
+	LateCount      int                     `json:"late_count"`
+	ExcusedCount   int                     `json:"excused_count"`
+	AttendanceRate float64                 `json:"attendance_rate"`
+	DailyStats     []DailyAttendanceStat   `json:"daily_stats"`
+	StudentStats   []StudentAttendanceStat `json:"student_stats,omitempty"`
 }
 
 // DailyAttendanceStat represents attendance for a specific day
@@ -53,63 +57,76 @@ type StudentAttendanceStat struct {
 
 // FinancialReport represents financial overview
 type FinancialReport struct {
-	StartDate         time.Time              `json:"start_date"`
-	EndDate           time.Time              `json:"end_date"`
-	TotalRevenue      float64                `json:"total_revenue"`
-	TotalExpenses     float64                `json:"total_expenses"`
-	NetProfit         float64                `json:"net_profit"`
-	PaidInvoices      int                    `json:"paid_invoices"`
-	PendingInvoices   int                    `json:"pending_invoices"`
-	OverdueInvoices   int                    `json:"overdue_invoices"`
-	TotalInvoices     int                    `json:"total_invoices"`
+	StartDate          time.Time             `json:"start_date"`
+	EndDate            time.Time             `json:"end_date"`
+	TotalRevenue       money.Amount          `json:"total_revenue"`
+	Refunds            money.Amount          `json:"refunds"`
+	CreditNotes        money.Amount          `json:"credit_notes"`
+	Discounts          money.Amount          `json:"discounts"`
+	TotalExpenses      money.Amount          `json:"total_expenses"`
+	NetProfit          money.Amount          `json:"net_profit"`
+	PaidInvoices       int                   `json:"paid_invoices"`
+	PendingInvoices    int                   `json:"pending_invoices"`
+	OverdueInvoices    int                   `json:"overdue_invoices"`
+	TotalInvoices      int                   `json:"total_invoices"`
 	PaymentMethodStats []PaymentMethodStat   `json:"payment_method_stats"`
-	MonthlyRevenue    []MonthlyRevenueStat   `json:"monthly_revenue"`
-	ExpenseCategories []ExpenseCategoryStat  `json:"expense_categories"`
+	MonthlyRevenue     []MonthlyRevenueStat  `json:"monthly_revenue"`
+	ExpenseCategories  []ExpenseCategoryStat `json:"expense_categories"`
+	DiscountCodes      []DiscountCodeStat    `json:"discount_codes"`
+}
+
+// DiscountCodeStat is what a promo code cost over the report period
+type DiscountCodeStat struct {
+	Code   string       `json:"code"`
+	Uses   int          `json:"uses"`
+	Amount money.Amount `json:"amount"`
 }
 
 // PaymentMethodStat represents payment statistics by method
 type PaymentMethodStat struct {
-	PaymentMethod string  `json:"payment_method"`
-	Count         int     `json:"count"`
-	TotalAmount   float64 `json:"total_amount"`
+	PaymentMethod string       `json:"payment_method"`
+	Count         int          `json:"count"`
+	TotalAmount   money.Amount `json:"total_amount"`
 }
 
 // MonthlyRevenueStat represents revenue for a specific month
 type MonthlyRevenueStat struct {
-	Month       string  `json:"month"`
-	Year        int     `json:"year"`
-	Revenue     float64 `json:"revenue"`
-	Expenses    float64 `json:"expenses"`
-	NetProfit   float64 `json:"net_profit"`
-	Invoices    int     `json:"invoices"`
+	Month     string       `json:"month"`
+	Year      int          `json:"year"`
+	Revenue   money.Amount `json:"revenue"`
+	Expenses  money.Amount `json:"expenses"`
+	NetProfit money.Amount `json:"net_profit"`
+	Invoices  int          `json:"invoices"`
 }
 
 // ExpenseCategoryStat represents expenses by category
 type ExpenseCategoryStat struct {
-	Category    string  `json:"category"`
-	Count       int     `json:"count"`
-	TotalAmount float64 `json:"total_amount"`
-	Percentage  float64 `json:"percentage"`
+	Category    string       `json:"category"`
+	Count       int          `json:"count"`
+	TotalAmount money.Amount `json:"total_amount"`
+	Percentage  float64      `json:"percentage"`
 }
 
 // StudentReport represents student enrollment and performance
 type StudentReport struct {
-	StartDate           time.Time           `json:"start_date"`
-	EndDate             time.Time           `json:"end_date"`
-	TotalStudents       int                 `json:"total_students"`
-	ActiveStudents      int                 `json:"active_students"`
-	InactiveStudents    int                 `json:"inactive_students"`
-	NewEnrollments      int                 `json:"new_enrollments"`
-	CourseDistribution  []CourseDistStat    `json:"course_distribution"`
-	PackageDistribution []PackageDistStat   `json:"package_distribution"`
-	GenderDistribution  []GenderDistStat    `json:"gender_distribution"`
+	StartDate           time.Time         `json:"start_date"`
+	EndDate             time.Time         `json:"end_date"`
+	TotalStudents       int               `json:"total_students"`
+	ActiveStudents      int               `json:"active_students"`
+	InactiveStudents    int               `json:"inactive_students"`
+	NewEnrollments      int               `json:"new_enrollments"`
+	CourseDistribution  []CourseDistStat  `json:"course_distribution"`
+	PackageDistribution []PackageDistStat `json:"package_distribution"`
+	GenderDistribution  []GenderDistStat  `json:"gender_distribution"`
+	CoursePassRates     []PassRateStat    `json:"course_pass_rates"`
+	InstructorPassRates []PassRateStat    `json:"instructor_pass_rates"`
 }
 
 // CourseDistStat represents student distribution by course
 type CourseDistStat struct {
-	CourseID   string `json:"course_id"`
-	CourseName string `json:"course_name"`
-	Count      int    `json:"count"`
+	CourseID   string  `json:"course_id"`
+	CourseName string  `json:"course_name"`
+	Count      int     `json:"count"`
 	Percentage float64 `json:"percentage"`
 }
 
@@ -128,68 +145,78 @@ type GenderDistStat struct {
 	Percentage float64 `json:"percentage"`
 }
 
+// PassRateStat represents license test outcomes for a course or instructor.
+// Attempts counts tests with a recorded result; pending tests are left out.
+type PassRateStat struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Attempts int     `json:"attempts"`
+	Passed   int     `json:"passed"`
+	PassRate float64 `json:"pass_rate"`
+}
+
 // RevenueReport represents detailed revenue analysis
 type RevenueReport struct {
-	StartDate          time.Time            `json:"start_date"`
-	EndDate            time.Time            `json:"end_date"`
-	TotalRevenue       float64              `json:"total_revenue"`
-	TotalPayments      int                  `json:"total_payments"`
-	AveragePayment     float64              `json:"average_payment"`
-	CourseRevenue      []CourseRevenueStat  `json:"course_revenue"`
-	PackageRevenue     []PackageRevenueStat `json:"package_revenue"`
-	DailyRevenue       []DailyRevenueStat   `json:"daily_revenue"`
+	StartDate      time.Time            `json:"start_date"`
+	EndDate        time.Time            `json:"end_date"`
+	TotalRevenue   money.Amount         `json:"total_revenue"`
+	TotalPayments  int                  `json:"total_payments"`
+	AveragePayment money.Amount         `json:"average_payment"`
+	CourseRevenue  []CourseRevenueStat  `json:"course_revenue"`
+	PackageRevenue []PackageRevenueStat `json:"package_revenue"`
+	DailyRevenue   []DailyRevenueStat   `json:"daily_revenue"`
 }
 
 // CourseRevenueStat represents revenue by course
 type CourseRevenueStat struct {
-	CourseID   string  `json:"course_id"`
-	CourseName string  `json:"course_name"`
-	Revenue    float64 `json:"revenue"`
-	Students   int     `json:"students"`
-	Percentage float64 `json:"percentage"`
+	CourseID   string       `json:"course_id"`
+	CourseName string       `json:"course_name"`
+	Revenue    money.Amount `json:"revenue"`
+	Students   int          `json:"students"`
+	Percentage float64      `json:"percentage"`
 }
 
 // PackageRevenueStat represents revenue by package
 type PackageRevenueStat struct {
-	PackageID   string  `json:"package_id"`
-	PackageName string  `json:"package_name"`
-	Revenue     float64 `json:"revenue"`
-	Students    int     `json:"students"`
-	Percentage  float64 `json:"percentage"`
+	PackageID   string       `json:"package_id"`
+	PackageName string       `json:"package_name"`
+	Revenue     money.Amount `json:"revenue"`
+	Students    int          `json:"students"`
+	Percentage  float64      `json:"percentage"`
 }
 
 // DailyRevenueStat represents revenue for a specific day
 type DailyRevenueStat struct {
-	Date     time.Time `json:"date"`
-	Revenue  float64   `json:"revenue"`
-	Payments int       `json:"payments"`
+	Date     time.Time    `json:"date"`
+	Revenue  money.Amount `json:"revenue"`
+	Payments int          `json:"payments"`
 }
 
 // ExpenseReport represents detailed expense analysis
 type ExpenseReport struct {
-	StartDate          time.Time               `json:"start_date"`
-	EndDate            time.Time               `json:"end_date"`
-	TotalExpenses      float64                 `json:"total_expenses"`
-	TotalTransactions  int                     `json:"total_transactions"`
-	AverageExpense     float64                 `json:"average_expense"`
-	CategoryBreakdown  []ExpenseCategoryStat   `json:"category_breakdown"`
-	MonthlyExpenses    []MonthlyExpenseStat    `json:"monthly_expenses"`
-	TopExpenses        []TopExpenseStat        `json:"top_expenses"`
+	StartDate         time.Time             `json:"start_date"`
+	EndDate           time.Time             `json:"end_date"`
+	TotalExpenses     money.Amount          `json:"total_expenses"`
+	TotalTransactions int                   `json:"total_transactions"`
+	AverageExpense    money.Amount          `json:"average_expense"`
+	CategoryBreakdown []ExpenseCategoryStat `json:"category_breakdown"`
+	MonthlyExpenses   []MonthlyExpenseStat  `json:"monthly_expenses"`
+	TopExpenses       []TopExpenseStat      `json:"top_expenses"`
 }
 
 // MonthlyExpenseStat represents expenses for a specific month
 type MonthlyExpenseStat struct {
-	Month      string  `json:"month"`
-	Year       int     `json:"year"`
-	Expenses   float64 `json:"expenses"`
-	Count      int     `json:"count"`
+	Month    string       `json:"month"`
+	Year     int          `json:"year"`
+	Expenses money.Amount `json:"expenses"`
+	Count    int          `json:"count"`
 }
 
 // TopExpenseStat represents the highest expenses
 type TopExpenseStat struct {
-	ID          string    `json:"id"`
-	Category    string    `json:"category"`
-	Description string    `json:"description"`
-	Amount      float64   `json:"amount"`
-	Date        time.Time `json:"date"`
+	ID          string       `json:"id"`
+	Category    string       `json:"category"`
+	Description string       `json:"description"`
+	Amount      money.Amount `json:"amount"`
+	Date        time.Time    `json:"date"`
 }
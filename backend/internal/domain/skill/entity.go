@@ -0,0 +1,111 @@
+package skill
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Skill is one item of a course's grading checklist, e.g. "Hill start".
+// Courses are shared, so each institute keeps its own checklist. A student
+// is ready for the trial once their latest score on every active skill of
+// their institute's checklist reaches its PassScore.
+type Skill struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	CourseID    uuid.UUID  `json:"course_id" gorm:"type:uuid;not null;index"`
+	Name        string     `json:"name" gorm:"type:varchar(100);not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	MaxScore    int        `json:"max_score" gorm:"type:int;not null;default:5"`
+	PassScore   int        `json:"pass_score" gorm:"type:int;not null;default:4"`
+	SortOrder   int        `json:"sort_order" gorm:"type:int;not null;default:0"`
+	IsActive    bool       `json:"is_active" gorm:"type:boolean;not null;default:true"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (Skill) TableName() string {
+	return "course_skills"
+}
+
+// Score is an instructor's grade for one skill in one attended lesson.
+type Score struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AttendanceID uuid.UUID `json:"attendance_id" gorm:"type:uuid;not null;uniqueIndex:idx_skill_scores_attendance_skill"`
+	SkillID      uuid.UUID `json:"skill_id" gorm:"type:uuid;not null;uniqueIndex:idx_skill_scores_attendance_skill"`
+	StudentID    uuid.UUID `json:"student_id" gorm:"type:uuid;not null;index"`
+	Score        int       `json:"score" gorm:"type:int;not null"`
+	Comment      string    `json:"comment" gorm:"type:text"`
+	GradedBy     uuid.UUID `json:"graded_by" gorm:"type:uuid;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Score) TableName() string {
+	return "skill_scores"
+}
+
+// ScoreEntry is a score with the date of the lesson it was given in.
+type ScoreEntry struct {
+	SkillID      uuid.UUID `json:"-"`
+	AttendanceID uuid.UUID `json:"attendance_id"`
+	Date         time.Time `json:"date"`
+	Score        int       `json:"score"`
+}
+
+const (
+	TrendImproving = "improving"
+	TrendSteady    = "steady"
+	TrendDeclining = "declining"
+)
+
+// SkillProgress summarises a student's scores on one skill, oldest first.
+// Trend compares the latest score with the average of up to three before it.
+type SkillProgress struct {
+	SkillID   uuid.UUID    `json:"skill_id"`
+	Name      string       `json:"name"`
+	MaxScore  int          `json:"max_score"`
+	PassScore int          `json:"pass_score"`
+	Latest    *int         `json:"latest"`
+	Average   float64      `json:"average"`
+	Trend     string       `json:"trend,omitempty"`
+	History   []ScoreEntry `json:"history"`
+}
+
+type Progress struct {
+	StudentID uuid.UUID       `json:"student_id"`
+	CourseID  uuid.UUID       `json:"course_id"`
+	Skills    []SkillProgress `json:"skills"`
+	Ready     bool            `json:"ready_for_trial"`
+}
+
+type CreateSkillRequest struct {
+	InstituteID uuid.UUID `json:"institute_id"`
+	Name        string    `json:"name" validate:"required,max=100"`
+	Description string    `json:"description"`
+	MaxScore    int       `json:"max_score" validate:"omitempty,min=1,max=100"`
+	PassScore   int       `json:"pass_score" validate:"omitempty,min=1,max=100"`
+	SortOrder   int       `json:"sort_order"`
+}
+
+type UpdateSkillRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,max=100"`
+	Description *string `json:"description,omitempty"`
+	MaxScore    *int    `json:"max_score,omitempty" validate:"omitempty,min=1,max=100"`
+	PassScore   *int    `json:"pass_score,omitempty" validate:"omitempty,min=1,max=100"`
+	SortOrder   *int    `json:"sort_order,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
+type ScoreInput struct {
+	SkillID uuid.UUID `json:"skill_id" validate:"required"`
+	Score   int       `json:"score" validate:"min=0"`
+	Comment string    `json:"comment"`
+}
+
+// GradeRequest records scores for the skills practised in one lesson.
+// Scores for skills already graded in that lesson are replaced.
+type GradeRequest struct {
+	Scores []ScoreInput `json:"scores" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,22 @@
+package skill
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, s *Skill) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Skill, error)
+	Update(ctx context.Context, s *Skill) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByCourse returns the course's checklist kept by instituteID, or
+	// every institute's checklist when instituteID is nil.
+	ListByCourse(ctx context.Context, courseID uuid.UUID, instituteID *uuid.UUID, activeOnly bool) ([]*Skill, error)
+	SaveScores(ctx context.Context, scores []*Score) error
+	ListScoresByAttendance(ctx context.Context, attendanceID uuid.UUID) ([]*Score, error)
+	// ListScoreHistory returns the student's scores on the course's skills,
+	// oldest lesson first.
+	ListScoreHistory(ctx context.Context, studentID, courseID uuid.UUID) ([]ScoreEntry, error)
+}
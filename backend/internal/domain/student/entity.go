@@ -15,6 +15,7 @@ type Student struct {
 	DateOfBirth time.Time  `json:"date_of_birth" gorm:"type:date;not null"`
 	Address     string     `json:"address" gorm:"type:text"`
 	InstituteID uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;uniqueIndex"`
 	Status      string     `json:"status" gorm:"type:varchar(20);default:'active';not null"`
 	EnrolledAt  time.Time  `json:"enrolled_at" gorm:"type:timestamp;not null"`
 	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
@@ -33,7 +34,8 @@ type CreateStudentRequest struct {
 	Phone       string    `json:"phone" validate:"required"`
 	DateOfBirth time.Time `json:"date_of_birth" validate:"required"`
 	Address     string    `json:"address"`
-	InstituteID uuid.UUID `json:"institute_id" validate:"required"`
+	InstituteID uuid.UUID `json:"institute_id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
 }
 
 type UpdateStudentRequest struct {
@@ -44,6 +46,7 @@ type UpdateStudentRequest struct {
 	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
 	Address     *string    `json:"address,omitempty"`
 	Status      *string    `json:"status,omitempty" validate:"omitempty,oneof=active inactive suspended"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
 }
 
 type StudentFilter struct {
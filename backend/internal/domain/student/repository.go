@@ -10,6 +10,7 @@ type Repository interface {
 	Create(ctx context.Context, student *Student) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Student, error)
 	GetByEmail(ctx context.Context, email string) (*Student, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*Student, error)
 	Update(ctx context.Context, student *Student) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, filter StudentFilter) ([]*Student, int64, error)
@@ -0,0 +1,13 @@
+package transaction
+
+import "context"
+
+// Manager runs a unit of work in a single database transaction. Repository
+// calls made with the context passed to fn take part in the transaction, so
+// work spanning several repositories commits or rolls back as a whole.
+type Manager interface {
+	// WithinTransaction commits when fn returns nil and rolls back when it
+	// returns an error or panics. Calls nested inside fn join the outer
+	// transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -8,16 +8,17 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Email     string     `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
-	Password  string     `json:"-" gorm:"type:varchar(255);not null"`
-	FirstName string     `json:"first_name" gorm:"type:varchar(100);not null"`
-	LastName  string     `json:"last_name" gorm:"type:varchar(100);not null"`
-	Role      string     `json:"role" gorm:"type:varchar(50);not null;default:'user'"`
-	Status    string     `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
-	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email       string     `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Password    string     `json:"-" gorm:"type:varchar(255);not null"`
+	FirstName   string     `json:"first_name" gorm:"type:varchar(100);not null"`
+	LastName    string     `json:"last_name" gorm:"type:varchar(100);not null"`
+	Role        string     `json:"role" gorm:"type:varchar(50);not null;default:'user'"`
+	InstituteID *uuid.UUID `json:"institute_id,omitempty" gorm:"type:uuid;index"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
 }
 
 func (User) TableName() string {
@@ -43,7 +44,13 @@ type RegisterRequest struct {
 	Password  string `json:"password" validate:"required,min=8"`
 	FirstName string `json:"first_name" validate:"required,min=2,max=100"`
 	LastName  string `json:"last_name" validate:"required,min=2,max=100"`
-	Role      string `json:"role" validate:"omitempty,oneof=admin instructor student"`
+}
+
+// AssignRequest places a registered user in an institute with a role. Only
+// platform admins may name the institute; institute admins assign to their own.
+type AssignRequest struct {
+	Role        string     `json:"role" validate:"required,oneof=admin instructor student"`
+	InstituteID *uuid.UUID `json:"institute_id,omitempty"`
 }
 
 type LoginRequest struct {
@@ -16,9 +16,10 @@ type Repository interface {
 }
 
 type UserFilter struct {
-	Role   *string
-	Status *string
-	Search *string
-	Limit  int
-	Offset int
+	InstituteID *uuid.UUID
+	Role        *string
+	Status      *string
+	Search      *string
+	Limit       int
+	Offset      int
 }
\ No newline at end of file
@@ -0,0 +1,113 @@
+package vehicle
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Vehicle struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	InstituteID        uuid.UUID  `json:"institute_id" gorm:"type:uuid;not null;index"`
+	RegistrationNumber string     `json:"registration_number" gorm:"type:varchar(50);not null"`
+	Type               string     `json:"type" gorm:"type:varchar(20);not null"`
+	LicenseCategory    string     `json:"license_category" gorm:"type:varchar(10);not null"`
+	Transmission       string     `json:"transmission" gorm:"type:varchar(20);not null"`
+	Make               string     `json:"make" gorm:"type:varchar(100)"`
+	Model              string     `json:"model" gorm:"type:varchar(100)"`
+	Odometer           int        `json:"odometer" gorm:"type:int;not null;default:0"`
+	BluebookExpiry     *time.Time `json:"bluebook_expiry,omitempty" gorm:"type:date"`
+	InsuranceExpiry    *time.Time `json:"insurance_expiry,omitempty" gorm:"type:date"`
+	PollutionExpiry    *time.Time `json:"pollution_expiry,omitempty" gorm:"type:date"`
+	Status             string     `json:"status" gorm:"type:varchar(20);not null;default:'active'"`
+	Notes              string     `json:"notes" gorm:"type:text"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" gorm:"type:timestamp;index"`
+}
+
+func (Vehicle) TableName() string {
+	return "vehicles"
+}
+
+const (
+	TypeCar        = "car"
+	TypeMotorcycle = "motorcycle"
+	TypeScooter    = "scooter"
+	TypeHeavy      = "heavy"
+
+	TransmissionManual    = "manual"
+	TransmissionAutomatic = "automatic"
+
+	StatusActive      = "active"
+	StatusMaintenance = "maintenance"
+	StatusRetired     = "retired"
+
+	DocumentBluebook  = "bluebook"
+	DocumentInsurance = "insurance"
+	DocumentPollution = "pollution"
+)
+
+// Document is one dated paper a vehicle must keep current to be on the road.
+type Document struct {
+	Name      string
+	ExpiresOn time.Time
+}
+
+// Documents returns the vehicle's documents that have an expiry date set.
+func (v *Vehicle) Documents() []Document {
+	var docs []Document
+	for _, d := range []struct {
+		name    string
+		expires *time.Time
+	}{
+		{DocumentBluebook, v.BluebookExpiry},
+		{DocumentInsurance, v.InsuranceExpiry},
+		{DocumentPollution, v.PollutionExpiry},
+	} {
+		if d.expires != nil {
+			docs = append(docs, Document{Name: d.name, ExpiresOn: *d.expires})
+		}
+	}
+	return docs
+}
+
+type CreateVehicleRequest struct {
+	RegistrationNumber string     `json:"registration_number" validate:"required,max=50"`
+	Type               string     `json:"type" validate:"required,oneof=car motorcycle scooter heavy"`
+	LicenseCategory    string     `json:"license_category" validate:"required,max=10"`
+	Transmission       string     `json:"transmission" validate:"required,oneof=manual automatic"`
+	Make               string     `json:"make" validate:"max=100"`
+	Model              string     `json:"model" validate:"max=100"`
+	Odometer           int        `json:"odometer" validate:"gte=0"`
+	BluebookExpiry     *time.Time `json:"bluebook_expiry,omitempty"`
+	InsuranceExpiry    *time.Time `json:"insurance_expiry,omitempty"`
+	PollutionExpiry    *time.Time `json:"pollution_expiry,omitempty"`
+	InstituteID        uuid.UUID  `json:"institute_id"`
+	Notes              string     `json:"notes"`
+}
+
+type UpdateVehicleRequest struct {
+	RegistrationNumber *string    `json:"registration_number,omitempty" validate:"omitempty,max=50"`
+	Type               *string    `json:"type,omitempty" validate:"omitempty,oneof=car motorcycle scooter heavy"`
+	LicenseCategory    *string    `json:"license_category,omitempty" validate:"omitempty,max=10"`
+	Transmission       *string    `json:"transmission,omitempty" validate:"omitempty,oneof=manual automatic"`
+	Make               *string    `json:"make,omitempty" validate:"omitempty,max=100"`
+	Model              *string    `json:"model,omitempty" validate:"omitempty,max=100"`
+	Odometer           *int       `json:"odometer,omitempty" validate:"omitempty,gte=0"`
+	BluebookExpiry     *time.Time `json:"bluebook_expiry,omitempty"`
+	InsuranceExpiry    *time.Time `json:"insurance_expiry,omitempty"`
+	PollutionExpiry    *time.Time `json:"pollution_expiry,omitempty"`
+	Status             *string    `json:"status,omitempty" validate:"omitempty,oneof=active maintenance retired"`
+	Notes              *string    `json:"notes,omitempty"`
+}
+
+type VehicleFilter struct {
+	Type            *string
+	LicenseCategory *string
+	Transmission    *string
+	Status          *string
+	Search          *string
+	Limit           int
+	Offset          int
+}
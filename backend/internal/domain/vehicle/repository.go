@@ -0,0 +1,20 @@
+package vehicle
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, v *Vehicle) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Vehicle, error)
+	FindByRegistrationNumber(ctx context.Context, instituteID uuid.UUID, registrationNumber string) (*Vehicle, error)
+	Update(ctx context.Context, v *Vehicle) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter VehicleFilter) ([]*Vehicle, int64, error)
+	// FindWithDocumentsExpiringBefore returns non-retired vehicles with at
+	// least one document expiring before the given date.
+	FindWithDocumentsExpiringBefore(ctx context.Context, before time.Time) ([]*Vehicle, error)
+}
@@ -2,14 +2,24 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/chalak/backend/internal/domain/attendance"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// uniqueViolation is the SQLSTATE for a unique index violation.
+const uniqueViolation = "23505"
+
 type AttendanceRepository struct {
 	db *gorm.DB
 }
@@ -19,15 +29,113 @@ func NewAttendanceRepository(db *gorm.DB) attendance.Repository {
 }
 
 func (r *AttendanceRepository) Create(ctx context.Context, att *attendance.Attendance) error {
-	if err := r.db.WithContext(ctx).Create(att).Error; err != nil {
-		return fmt.Errorf("failed to create attendance: %w", err)
+	if err := r.checkStudents(ctx, att.StudentID); err != nil {
+		return err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(att).Error; err != nil {
+			return err
+		}
+		return recordStatusChange(tx, att, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create attendance: %w", attendanceError(err))
 	}
 	return nil
 }
 
+func (r *AttendanceRepository) UpsertBatch(ctx context.Context, atts []*attendance.Attendance) error {
+	studentIDs := make([]uuid.UUID, 0, len(atts))
+	for _, att := range atts {
+		studentIDs = append(studentIDs, att.StudentID)
+	}
+	if err := r.checkStudents(ctx, studentIDs...); err != nil {
+		return err
+	}
+
+	// Locks are taken in student order so concurrent roll calls for the
+	// same class cannot deadlock.
+	ordered := slices.Clone(atts)
+	slices.SortFunc(ordered, func(a, b *attendance.Attendance) int {
+		return strings.Compare(a.StudentID.String(), b.StudentID.String())
+	})
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, att := range ordered {
+			if _, err := upsertAttendance(tx, att); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save attendances: %w", attendanceError(err))
+	}
+	return nil
+}
+
+func (r *AttendanceRepository) Upsert(ctx context.Context, att *attendance.Attendance) (bool, error) {
+	if err := r.checkStudents(ctx, att.StudentID); err != nil {
+		return false, err
+	}
+
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		created, err = upsertAttendance(tx, att)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save attendance: %w", attendanceError(err))
+	}
+	return created, nil
+}
+
+// upsertAttendance saves att within tx as described by Upsert, recording
+// every status change in the history.
+func upsertAttendance(tx *gorm.DB, att *attendance.Attendance) (bool, error) {
+	key := fmt.Sprintf("attendance:%s:%s:%s", att.StudentID, att.ClassID, att.Date.Format("2006-01-02"))
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+		return false, fmt.Errorf("failed to lock attendance: %w", err)
+	}
+
+	var existing attendance.Attendance
+	err := tx.Where("student_id = ? AND class_id = ? AND date = ? AND deleted_at IS NULL",
+		att.StudentID, att.ClassID, att.Date).First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		if err := tx.Create(att).Error; err != nil {
+			return false, err
+		}
+		return true, recordStatusChange(tx, att, nil)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	previous := existing.Status
+	existing.Status = att.Status
+	existing.Notes = att.Notes
+	existing.MarkedBy = att.MarkedBy
+	existing.UpdatedAt = att.UpdatedAt
+	if existing.CheckInAt == nil {
+		existing.CheckInAt = att.CheckInAt
+	}
+	*att = existing
+
+	if err := tx.Save(att).Error; err != nil {
+		return false, err
+	}
+	if previous == att.Status {
+		return false, nil
+	}
+	return false, recordStatusChange(tx, att, &previous)
+}
+
 func (r *AttendanceRepository) FindByID(ctx context.Context, id uuid.UUID) (*attendance.Attendance, error) {
 	var att attendance.Attendance
-	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&att).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), studentInInstitute)
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&att).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("attendance not found")
 		}
@@ -36,15 +144,34 @@ func (r *AttendanceRepository) FindByID(ctx context.Context, id uuid.UUID) (*att
 	return &att, nil
 }
 
+// Update saves att, recording a history entry against att.MarkedBy when
+// the status changes.
 func (r *AttendanceRepository) Update(ctx context.Context, att *attendance.Attendance) error {
-	if err := r.db.WithContext(ctx).Save(att).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous []string
+		if err := tx.Model(&attendance.Attendance{}).
+			Where("id = ?", att.ID).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Pluck("status", &previous).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Save(att).Error; err != nil {
+			return err
+		}
+		if len(previous) == 0 || previous[0] == att.Status {
+			return nil
+		}
+		return recordStatusChange(tx, att, &previous[0])
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update attendance: %w", err)
 	}
 	return nil
 }
 
 func (r *AttendanceRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Model(&attendance.Attendance{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&attendance.Attendance{}), studentInInstitute)
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
 		return fmt.Errorf("failed to delete attendance: %w", err)
 	}
 	return nil
@@ -54,6 +181,7 @@ func (r *AttendanceRepository) List(ctx context.Context, filter attendance.Atten
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&attendance.Attendance{}).Where("attendances.deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "attendances."+studentInInstitute)
 
 	if filter.StudentID != nil {
 		query = query.Where("attendances.student_id = ?", *filter.StudentID)
@@ -85,7 +213,7 @@ func (r *AttendanceRepository) List(ctx context.Context, filter attendance.Atten
 	sqlQuery := `
 		SELECT
 			a.id, a.student_id, a.class_id, a.date, a.status,
-			a.check_in_at, a.check_out_at, a.notes, a.marked_by,
+			a.check_in_at, a.check_out_at, a.duration_minutes, a.notes, a.marked_by,
 			a.created_at, a.updated_at, a.deleted_at,
 			s.first_name as student_first_name,
 			s.last_name as student_last_name
@@ -96,6 +224,9 @@ func (r *AttendanceRepository) List(ctx context.Context, filter attendance.Atten
 
 	args := []interface{}{}
 
+	scope, args := instituteSQL(ctx, "a."+studentInInstitute, args)
+	sqlQuery += scope
+
 	if filter.StudentID != nil {
 		sqlQuery += " AND a.student_id = ?"
 		args = append(args, *filter.StudentID)
@@ -162,7 +293,8 @@ func (r *AttendanceRepository) GetStudentAttendanceStats(ctx context.Context, st
 		Count  int
 	}
 
-	if err := r.db.WithContext(ctx).
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), studentInInstitute)
+	if err := query.
 		Model(&attendance.Attendance{}).
 		Select("status, COUNT(*) as count").
 		Where("student_id = ? AND date >= ? AND date <= ? AND deleted_at IS NULL", studentID, dateFrom, dateTo).
@@ -177,4 +309,184 @@ func (r *AttendanceRepository) GetStudentAttendanceStats(ctx context.Context, st
 	}
 
 	return stats, nil
-}
\ No newline at end of file
+}
+
+func (r *AttendanceRepository) ListHistory(ctx context.Context, attendanceID uuid.UUID) ([]*attendance.StatusChange, error) {
+	var history []*attendance.StatusChange
+
+	query := r.db.WithContext(ctx).Where("attendance_id = ?", attendanceID)
+	query = scopeToInstitute(ctx, query, "attendance_id IN (SELECT id FROM attendances WHERE "+studentInInstitute+")")
+
+	if err := query.Order("changed_at, id").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to list attendance history: %w", err)
+	}
+	return history, nil
+}
+
+func (r *AttendanceRepository) SaveTrack(ctx context.Context, track *attendance.Track) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "attendance_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"file_key", "file_name", "format", "size", "distance_meters", "duration_seconds",
+			"max_speed_kph", "points", "started_at", "ended_at", "uploaded_by", "updated_at",
+		}),
+	}).Create(track).Error
+	if err != nil {
+		return fmt.Errorf("failed to save attendance track: %w", err)
+	}
+	return nil
+}
+
+func (r *AttendanceRepository) FindTrack(ctx context.Context, attendanceID uuid.UUID) (*attendance.Track, error) {
+	var track attendance.Track
+
+	query := r.db.WithContext(ctx).Where("attendance_id = ?", attendanceID)
+	query = scopeToInstitute(ctx, query, "attendance_id IN (SELECT id FROM attendances WHERE "+studentInInstitute+")")
+
+	if err := query.First(&track).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("attendance track not found")
+		}
+		return nil, fmt.Errorf("failed to find attendance track: %w", err)
+	}
+	return &track, nil
+}
+
+// checkStudents makes sure every student belongs to the caller's institute.
+func (r *AttendanceRepository) checkStudents(ctx context.Context, studentIDs ...uuid.UUID) error {
+	instituteID, ok := tenant.InstituteID(ctx)
+	if !ok {
+		return nil
+	}
+
+	unique := make(map[uuid.UUID]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		unique[id] = true
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Table("students").
+		Where("id IN ? AND institute_id = ? AND deleted_at IS NULL", studentIDs, instituteID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check student: %w", err)
+	}
+	if int(count) != len(unique) {
+		return apperrors.NotFound("student not found")
+	}
+	return nil
+}
+
+// recordStatusChange appends att's current status to its history.
+func recordStatusChange(tx *gorm.DB, att *attendance.Attendance, from *string) error {
+	return tx.Create(&attendance.StatusChange{
+		ID:           uuid.New(),
+		AttendanceID: att.ID,
+		FromStatus:   from,
+		ToStatus:     att.Status,
+		ChangedBy:    att.MarkedBy,
+		ChangedAt:    time.Now().UTC(),
+	}).Error
+}
+
+// attendanceError reports a second mark for the same student, class and date
+// as a conflict.
+func attendanceError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return apperrors.Conflict("attendance already marked for this student, class and date")
+	}
+	return err
+}
+
+// attendedHours is the hours one attended lesson counts for: the logged
+// check-in to check-out time, or the scheduled lesson length when the
+// student was never checked out. It expects attendances as a and
+// lesson_sessions as s.
+const attendedHours = "COALESCE(a.duration_minutes / 60.0, EXTRACT(EPOCH FROM (s.end_time - s.start_time)) / 3600)"
+
+// attendedLesson restricts a join of attendances a and lesson_sessions s to
+// lessons that count towards a course.
+const attendedLesson = `a.status IN ('present', 'late')
+			AND s.status <> 'cancelled'
+			AND a.deleted_at IS NULL AND s.deleted_at IS NULL`
+
+// GetCourseProgress sums the hours the student attended on the course.
+func (r *AttendanceRepository) GetCourseProgress(ctx context.Context, studentID, courseID uuid.UUID) (*attendance.CourseProgress, error) {
+	var progress attendance.CourseProgress
+
+	scope, args := instituteSQL(ctx, "a."+studentInInstitute, []interface{}{studentID, courseID})
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) as lessons,
+			COALESCE(SUM(`+attendedHours+`), 0) as hours,
+			MIN(a.date) as first_date,
+			MAX(a.date) as last_date
+		FROM attendances a
+		INNER JOIN lesson_sessions s ON s.id = a.class_id
+		WHERE a.student_id = ? AND s.course_id = ?
+			AND `+attendedLesson+scope, args...).Scan(&progress).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course progress: %w", err)
+	}
+
+	return &progress, nil
+}
+
+func (r *AttendanceRepository) ListCourseHours(ctx context.Context, filter attendance.HoursFilter) ([]*attendance.CourseHours, error) {
+	var hours []*attendance.CourseHours
+
+	where := "st.deleted_at IS NULL"
+	var args []interface{}
+	if filter.StudentID != nil {
+		where += " AND x.student_id = ?"
+		args = append(args, *filter.StudentID)
+	}
+	if filter.CourseID != nil {
+		where += " AND x.course_id = ?"
+		args = append(args, *filter.CourseID)
+	}
+	scope, args := instituteSQL(ctx, "st.institute_id = ?", args)
+
+	err := r.db.WithContext(ctx).Raw(`
+		WITH taken AS (
+			SELECT e.student_id, e.course_id
+			FROM enrollments e
+			WHERE e.course_id IS NOT NULL AND e.status <> 'cancelled' AND e.deleted_at IS NULL
+			UNION
+			SELECT e.student_id, pc.course_id
+			FROM enrollments e
+			INNER JOIN package_courses pc ON pc.package_id = e.package_id
+			WHERE e.status <> 'cancelled' AND e.deleted_at IS NULL
+			UNION
+			SELECT a.student_id, s.course_id
+			FROM attendances a
+			INNER JOIN lesson_sessions s ON s.id = a.class_id
+			WHERE `+attendedLesson+`
+		), logged AS (
+			SELECT a.student_id, s.course_id, COUNT(*) as lessons, SUM(`+attendedHours+`) as hours
+			FROM attendances a
+			INNER JOIN lesson_sessions s ON s.id = a.class_id
+			WHERE `+attendedLesson+`
+			GROUP BY a.student_id, s.course_id
+		)
+		SELECT
+			st.id as student_id,
+			st.first_name || ' ' || st.last_name as student_name,
+			c.id as course_id,
+			c.name as course_name,
+			COALESCE(l.lessons, 0) as lessons,
+			COALESCE(l.hours, 0) as logged_hours,
+			c.duration as required_hours
+		FROM taken x
+		INNER JOIN students st ON st.id = x.student_id
+		INNER JOIN courses c ON c.id = x.course_id
+		LEFT JOIN logged l ON l.student_id = x.student_id AND l.course_id = x.course_id
+		WHERE `+where+scope+`
+		ORDER BY student_name, course_name
+	`, args...).Scan(&hours).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list course hours: %w", err)
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,339 @@
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// attendanceTables is the part of the schema from before the migrations
+// that the attendance queries read.
+const attendanceTables = `
+CREATE TABLE students (
+    id UUID PRIMARY KEY,
+    institute_id UUID NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE courses (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    duration INT NOT NULL
+);
+
+CREATE TABLE package_courses (
+    package_id UUID NOT NULL,
+    course_id UUID NOT NULL
+);
+
+CREATE TABLE enrollments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL,
+    package_id UUID,
+    course_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE lesson_sessions (
+    id UUID PRIMARY KEY,
+    course_id UUID NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE attendances (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL,
+    class_id UUID NOT NULL,
+    date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    check_in_at TIMESTAMP,
+    check_out_at TIMESTAMP,
+    notes TEXT,
+    marked_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+`
+
+// attendanceDB is a test database with the attendance tables fully
+// migrated.
+func attendanceDB(t *testing.T) *gorm.DB {
+	return testDB(t,
+		attendanceTables,
+		migration(t, "000012_add_attendance_duration.up.sql"),
+		migration(t, "000013_attendance_uniqueness_and_history.up.sql"),
+	)
+}
+
+type attendanceFixture struct {
+	db        *gorm.DB
+	repo      attendance.Repository
+	studentID uuid.UUID
+	courseID  uuid.UUID
+}
+
+func newAttendanceFixture(t *testing.T, db *gorm.DB) *attendanceFixture {
+	f := &attendanceFixture{
+		db:        db,
+		repo:      NewAttendanceRepository(db),
+		studentID: uuid.New(),
+		courseID:  uuid.New(),
+	}
+	require.NoError(t, db.Exec("INSERT INTO students (id, institute_id, first_name, last_name) VALUES (?, ?, 'Sita', 'Sharma')",
+		f.studentID, uuid.New()).Error)
+	require.NoError(t, db.Exec("INSERT INTO courses (id, name, duration) VALUES (?, 'Car Driving', 10)", f.courseID).Error)
+	return f
+}
+
+// session schedules a lesson on the fixture's course.
+func (f *attendanceFixture) session(t *testing.T, start time.Time, length time.Duration, status string) uuid.UUID {
+	id := uuid.New()
+	require.NoError(t, f.db.Exec("INSERT INTO lesson_sessions (id, course_id, start_time, end_time, status) VALUES (?, ?, ?, ?, ?)",
+		id, f.courseID, start, start.Add(length), status).Error)
+	return id
+}
+
+// mark records the fixture's student in a lesson, checked out after
+// minutes when minutes is positive.
+func (f *attendanceFixture) mark(t *testing.T, sessionID uuid.UUID, start time.Time, status string, minutes int) *attendance.Attendance {
+	att := &attendance.Attendance{
+		ID:        uuid.New(),
+		StudentID: f.studentID,
+		ClassID:   sessionID,
+		Date:      start.Truncate(24 * time.Hour),
+		Status:    status,
+		CheckInAt: &start,
+		MarkedBy:  uuid.New(),
+		CreatedAt: start,
+		UpdatedAt: start,
+	}
+	require.NoError(t, f.repo.Create(context.Background(), att))
+
+	if minutes > 0 {
+		checkOut := start.Add(time.Duration(minutes) * time.Minute)
+		att.CheckOutAt = &checkOut
+		att.DurationMinutes = &minutes
+		require.NoError(t, f.repo.Update(context.Background(), att))
+	}
+	return att
+}
+
+func TestAttendanceListIncludesDuration(t *testing.T) {
+	f := newAttendanceFixture(t, attendanceDB(t))
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	checkedOut := f.mark(t, f.session(t, start, time.Hour, "scheduled"), start, attendance.StatusPresent, 45)
+	open := f.mark(t, f.session(t, start.AddDate(0, 0, 1), time.Hour, "scheduled"), start.AddDate(0, 0, 1), attendance.StatusPresent, 0)
+
+	list, total, err := f.repo.List(context.Background(), attendance.AttendanceFilter{StudentID: &f.studentID})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+
+	byID := map[uuid.UUID]*attendance.Attendance{}
+	for _, att := range list {
+		byID[att.ID] = att
+	}
+	require.NotNil(t, byID[checkedOut.ID].DurationMinutes)
+	assert.Equal(t, 45, *byID[checkedOut.ID].DurationMinutes)
+	assert.NotNil(t, byID[checkedOut.ID].CheckOutAt)
+	assert.Nil(t, byID[open.ID].DurationMinutes)
+	assert.Equal(t, "Sita", byID[open.ID].StudentFirstName)
+}
+
+func TestCourseHoursFallBackToScheduledLength(t *testing.T) {
+	f := newAttendanceFixture(t, attendanceDB(t))
+	ctx := context.Background()
+	day := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	// Checked out after 45 of 60 scheduled minutes: counts 0.75h.
+	f.mark(t, f.session(t, day, time.Hour, "completed"), day, attendance.StatusPresent, 45)
+	// Never checked out of a 90 minute lesson: counts the scheduled 1.5h.
+	f.mark(t, f.session(t, day.AddDate(0, 0, 2), 90*time.Minute, "completed"), day.AddDate(0, 0, 2), attendance.StatusLate, 0)
+	// Absences and cancelled lessons do not count.
+	f.mark(t, f.session(t, day.AddDate(0, 0, 3), time.Hour, "completed"), day.AddDate(0, 0, 3), attendance.StatusAbsent, 0)
+	f.mark(t, f.session(t, day.AddDate(0, 0, 4), time.Hour, "cancelled"), day.AddDate(0, 0, 4), attendance.StatusPresent, 0)
+
+	progress, err := f.repo.GetCourseProgress(ctx, f.studentID, f.courseID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, progress.Lessons)
+	assert.InDelta(t, 2.25, progress.Hours, 0.001)
+	require.NotNil(t, progress.FirstDate)
+	require.NotNil(t, progress.LastDate)
+	assert.Equal(t, "2024-05-01", progress.FirstDate.Format("2006-01-02"))
+	assert.Equal(t, "2024-05-03", progress.LastDate.Format("2006-01-02"))
+
+	hours, err := f.repo.ListCourseHours(ctx, attendance.HoursFilter{StudentID: &f.studentID})
+	require.NoError(t, err)
+	require.Len(t, hours, 1)
+	assert.Equal(t, "Sita Sharma", hours[0].StudentName)
+	assert.Equal(t, f.courseID, hours[0].CourseID)
+	assert.Equal(t, 2, hours[0].Lessons)
+	assert.InDelta(t, 2.25, hours[0].LoggedHours, 0.001)
+	assert.Equal(t, float64(10), hours[0].RequiredHours)
+}
+
+func TestCourseHoursListEnrolledCoursesWithoutLessons(t *testing.T) {
+	db := attendanceDB(t)
+	f := newAttendanceFixture(t, db)
+	packageID := uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO package_courses (package_id, course_id) VALUES (?, ?)", packageID, f.courseID).Error)
+	require.NoError(t, db.Exec("INSERT INTO enrollments (student_id, package_id) VALUES (?, ?)", f.studentID, packageID).Error)
+
+	hours, err := f.repo.ListCourseHours(context.Background(), attendance.HoursFilter{StudentID: &f.studentID})
+	require.NoError(t, err)
+	require.Len(t, hours, 1)
+	assert.Equal(t, 0, hours[0].Lessons)
+	assert.Equal(t, float64(0), hours[0].LoggedHours)
+}
+
+func TestAttendanceUpsertRecordsHistory(t *testing.T) {
+	f := newAttendanceFixture(t, attendanceDB(t))
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	sessionID := f.session(t, start, time.Hour, "scheduled")
+	teacher, admin := uuid.New(), uuid.New()
+
+	upsert := func(status string, by uuid.UUID) (*attendance.Attendance, bool) {
+		att := &attendance.Attendance{
+			ID:        uuid.New(),
+			StudentID: f.studentID,
+			ClassID:   sessionID,
+			Date:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			Status:    status,
+			MarkedBy:  by,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+		created, err := f.repo.Upsert(ctx, att)
+		require.NoError(t, err)
+		return att, created
+	}
+
+	first, created := upsert(attendance.StatusAbsent, teacher)
+	assert.True(t, created)
+
+	second, created := upsert(attendance.StatusLate, admin)
+	assert.False(t, created)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, attendance.StatusLate, second.Status)
+
+	// Marking the same status again changes nothing in the history.
+	_, created = upsert(attendance.StatusLate, admin)
+	assert.False(t, created)
+
+	history, err := f.repo.ListHistory(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Nil(t, history[0].FromStatus)
+	assert.Equal(t, attendance.StatusAbsent, history[0].ToStatus)
+	assert.Equal(t, teacher, history[0].ChangedBy)
+	require.NotNil(t, history[1].FromStatus)
+	assert.Equal(t, attendance.StatusAbsent, *history[1].FromStatus)
+	assert.Equal(t, attendance.StatusLate, history[1].ToStatus)
+	assert.Equal(t, admin, history[1].ChangedBy)
+
+	list, total, err := f.repo.List(ctx, attendance.AttendanceFilter{ClassID: &sessionID})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Len(t, list, 1)
+}
+
+func TestAttendanceUpsertBatchUpdatesMarkedStudents(t *testing.T) {
+	f := newAttendanceFixture(t, attendanceDB(t))
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	sessionID := f.session(t, start, time.Hour, "scheduled")
+	marked := f.mark(t, sessionID, start, attendance.StatusAbsent, 0)
+
+	other := uuid.New()
+	require.NoError(t, f.db.Exec("INSERT INTO students (id, institute_id, first_name, last_name) VALUES (?, ?, 'Ram', 'Thapa')",
+		other, uuid.New()).Error)
+
+	rollCall := func(studentID uuid.UUID, status string) *attendance.Attendance {
+		return &attendance.Attendance{
+			ID:        uuid.New(),
+			StudentID: studentID,
+			ClassID:   sessionID,
+			Date:      marked.Date,
+			Status:    status,
+			MarkedBy:  uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+	}
+	atts := []*attendance.Attendance{rollCall(f.studentID, attendance.StatusLate), rollCall(other, attendance.StatusPresent)}
+
+	require.NoError(t, f.repo.UpsertBatch(ctx, atts))
+	assert.Equal(t, marked.ID, atts[0].ID)
+
+	list, total, err := f.repo.List(ctx, attendance.AttendanceFilter{ClassID: &sessionID})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Len(t, list, 2)
+
+	history, err := f.repo.ListHistory(ctx, marked.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, attendance.StatusLate, history[1].ToStatus)
+}
+
+func TestAttendanceUniquenessMigrationKeepsLatestMark(t *testing.T) {
+	db := testDB(t, attendanceTables, migration(t, "000012_add_attendance_duration.up.sql"))
+	studentID, classID, markedBy := uuid.New(), uuid.New(), uuid.New()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	insert := func(studentID uuid.UUID, status string, updatedAt time.Time) uuid.UUID {
+		id := uuid.New()
+		require.NoError(t, db.Exec(`INSERT INTO attendances (id, student_id, class_id, date, status, marked_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, id, studentID, classID, day, status, markedBy, updatedAt, updatedAt).Error)
+		return id
+	}
+
+	oldest := insert(studentID, attendance.StatusAbsent, day.Add(8*time.Hour))
+	latest := insert(studentID, attendance.StatusPresent, day.Add(10*time.Hour))
+	middle := insert(studentID, attendance.StatusLate, day.Add(9*time.Hour))
+	other := insert(uuid.New(), attendance.StatusPresent, day.Add(8*time.Hour))
+
+	require.NoError(t, db.Exec(migration(t, "000013_attendance_uniqueness_and_history.up.sql")).Error)
+
+	var live []uuid.UUID
+	require.NoError(t, db.Raw("SELECT id FROM attendances WHERE deleted_at IS NULL").Scan(&live).Error)
+	assert.ElementsMatch(t, []uuid.UUID{latest, other}, live)
+
+	var deleted []uuid.UUID
+	require.NoError(t, db.Raw("SELECT id FROM attendances WHERE deleted_at IS NOT NULL").Scan(&deleted).Error)
+	assert.ElementsMatch(t, []uuid.UUID{oldest, middle}, deleted)
+
+	var seeded []uuid.UUID
+	require.NoError(t, db.Raw("SELECT attendance_id FROM attendance_history").Scan(&seeded).Error)
+	assert.ElementsMatch(t, []uuid.UUID{latest, other}, seeded)
+
+	// A second live mark for the same student, class and date is refused.
+	err := NewAttendanceRepository(db).Create(context.Background(), &attendance.Attendance{
+		ID:        uuid.New(),
+		StudentID: studentID,
+		ClassID:   classID,
+		Date:      day,
+		Status:    attendance.StatusAbsent,
+		MarkedBy:  markedBy,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+}
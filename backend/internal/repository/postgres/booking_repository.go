@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/booking"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BookingRepository struct {
+	db *gorm.DB
+}
+
+func NewBookingRepository(db *gorm.DB) booking.Repository {
+	return &BookingRepository{db: db}
+}
+
+func (r *BookingRepository) ReplaceWorkingHours(ctx context.Context, instructorID uuid.UUID, hours []*booking.WorkingHours) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("instructor_id = ?", instructorID).Delete(&booking.WorkingHours{}).Error; err != nil {
+			return fmt.Errorf("failed to clear working hours: %w", err)
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		if err := tx.Create(&hours).Error; err != nil {
+			return fmt.Errorf("failed to save working hours: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *BookingRepository) ListWorkingHours(ctx context.Context, filter booking.WorkingHoursFilter) ([]*booking.WorkingHours, error) {
+	var hours []*booking.WorkingHours
+
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&booking.WorkingHours{}), instructorInInstitute)
+
+	if filter.InstructorID != nil {
+		query = query.Where("instructor_id = ?", *filter.InstructorID)
+	}
+
+	if filter.Weekday != nil {
+		query = query.Where("weekday = ?", *filter.Weekday)
+	}
+
+	if err := query.Order("instructor_id, weekday, start_time").Find(&hours).Error; err != nil {
+		return nil, fmt.Errorf("failed to list working hours: %w", err)
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/certificate"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type CertificateRepository struct {
+	db *gorm.DB
+}
+
+func NewCertificateRepository(db *gorm.DB) certificate.Repository {
+	return &CertificateRepository{db: db}
+}
+
+func (r *CertificateRepository) NextSerial(ctx context.Context) (int64, error) {
+	var serial int64
+	if err := r.db.WithContext(ctx).Raw("SELECT nextval('certificate_serial_seq')").Scan(&serial).Error; err != nil {
+		return 0, fmt.Errorf("failed to allocate certificate serial: %w", err)
+	}
+	return serial, nil
+}
+
+func (r *CertificateRepository) Create(ctx context.Context, c *certificate.Certificate) error {
+	if err := requireActiveInstitute(ctx, r.db, &c.InstituteID); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(c).Error; err != nil {
+		return fmt.Errorf("failed to create certificate: %w", certificateError(err))
+	}
+	return nil
+}
+
+func (r *CertificateRepository) FindByID(ctx context.Context, id uuid.UUID) (*certificate.Certificate, error) {
+	var c certificate.Certificate
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&c).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("certificate not found")
+		}
+		return nil, fmt.Errorf("failed to find certificate: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *CertificateRepository) FindByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) (*certificate.Certificate, error) {
+	var c certificate.Certificate
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("student_id = ? AND course_id = ? AND deleted_at IS NULL", studentID, courseID).First(&c).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("certificate not found")
+		}
+		return nil, fmt.Errorf("failed to find certificate: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *CertificateRepository) ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*certificate.Certificate, error) {
+	var certificates []*certificate.Certificate
+
+	query := r.db.WithContext(ctx).Where("student_id = ? AND deleted_at IS NULL", studentID)
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if err := query.Order("issued_at DESC").Find(&certificates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+	return certificates, nil
+}
+
+// certificateError reports a certificate issued concurrently for the same
+// student and course as a conflict.
+func certificateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "idx_certificates_student_course" {
+		return apperrors.Conflict("a certificate has already been issued for this course")
+	}
+	return err
+}
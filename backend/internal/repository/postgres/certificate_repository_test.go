@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/certificate"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCertificateReportsADuplicateAsAConflict(t *testing.T) {
+	db := testDB(t, instituteTable, attendanceTables, migration(t, "000011_create_certificates.up.sql"))
+	repo := NewCertificateRepository(db)
+	instituteID, studentID, courseID := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO institutes (id, name, code) VALUES (?, 'Himalayan Driving School', 'HDS')", instituteID).Error)
+	require.NoError(t, db.Exec("INSERT INTO students (id, institute_id, first_name, last_name) VALUES (?, ?, 'Sita', 'Sharma')", studentID, instituteID).Error)
+	require.NoError(t, db.Exec("INSERT INTO courses (id, name, duration) VALUES (?, 'Car Driving', 30)", courseID).Error)
+
+	ctx := context.Background()
+	issue := func(serial string) error {
+		now := time.Now().UTC()
+		return repo.Create(ctx, &certificate.Certificate{
+			ID:           uuid.New(),
+			InstituteID:  instituteID,
+			StudentID:    studentID,
+			CourseID:     courseID,
+			SerialNumber: serial,
+			StartDate:    now,
+			CompletedOn:  now,
+			FileKey:      "certificates/" + serial + ".pdf",
+			IssuedBy:     uuid.New(),
+			IssuedAt:     now,
+		})
+	}
+
+	require.NoError(t, issue("HDS-2024-000001"))
+
+	err := issue("HDS-2024-000002")
+	assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+}
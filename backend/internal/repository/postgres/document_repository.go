@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/document"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) document.Repository {
+	return &DocumentRepository{db: db}
+}
+
+func (r *DocumentRepository) Create(ctx context.Context, d *document.Document) error {
+	if err := requireActiveInstitute(ctx, r.db, &d.InstituteID); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(d).Error; err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) FindByID(ctx context.Context, id uuid.UUID) (*document.Document, error) {
+	var d document.Document
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&d).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("document not found")
+		}
+		return nil, fmt.Errorf("failed to find document: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *DocumentRepository) FindByStudentAndType(ctx context.Context, studentID uuid.UUID, docType string) (*document.Document, error) {
+	var d document.Document
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("student_id = ? AND type = ? AND deleted_at IS NULL", studentID, docType).First(&d).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("document not found")
+		}
+		return nil, fmt.Errorf("failed to find document: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *DocumentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&document.Document{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentRepository) ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*document.Document, error) {
+	var documents []*document.Document
+
+	query := r.db.WithContext(ctx).Where("student_id = ? AND deleted_at IS NULL", studentID)
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if err := query.Order("type, created_at DESC").Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	return documents, nil
+}
@@ -19,6 +19,10 @@ func NewEmployeeRepository(db *gorm.DB) employee.Repository {
 }
 
 func (r *EmployeeRepository) Create(ctx context.Context, emp *employee.Employee) error {
+	if err := requireActiveInstitute(ctx, r.db, &emp.InstituteID); err != nil {
+		return err
+	}
+
 	if err := r.db.WithContext(ctx).Create(emp).Error; err != nil {
 		return fmt.Errorf("failed to create employee: %w", err)
 	}
@@ -27,7 +31,8 @@ func (r *EmployeeRepository) Create(ctx context.Context, emp *employee.Employee)
 
 func (r *EmployeeRepository) FindByID(ctx context.Context, id uuid.UUID) (*employee.Employee, error) {
 	var emp employee.Employee
-	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&emp).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&emp).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("employee not found")
 		}
@@ -55,7 +60,8 @@ func (r *EmployeeRepository) Update(ctx context.Context, emp *employee.Employee)
 }
 
 func (r *EmployeeRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Model(&employee.Employee{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&employee.Employee{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
 		return fmt.Errorf("failed to delete employee: %w", err)
 	}
 	return nil
@@ -66,6 +72,7 @@ func (r *EmployeeRepository) List(ctx context.Context, filter employee.EmployeeF
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&employee.Employee{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
 
 	if filter.InstituteID != nil {
 		query = query.Where("institute_id = ?", *filter.InstituteID)
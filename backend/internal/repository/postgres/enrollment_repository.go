@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/enrollment"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EnrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentRepository(db *gorm.DB) enrollment.Repository {
+	return &EnrollmentRepository{db: db}
+}
+
+func (r *EnrollmentRepository) Create(ctx context.Context, e *enrollment.Enrollment) error {
+	if err := requireActiveInstitute(ctx, r.db, &e.InstituteID); err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Create(e).Error; err != nil {
+		return fmt.Errorf("failed to create enrollment: %w", err)
+	}
+	return nil
+}
+
+func (r *EnrollmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*enrollment.Enrollment, error) {
+	var e enrollment.Enrollment
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&e).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("enrollment not found")
+		}
+		return nil, fmt.Errorf("failed to find enrollment: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *EnrollmentRepository) Update(ctx context.Context, e *enrollment.Enrollment) error {
+	if err := r.db.WithContext(ctx).Save(e).Error; err != nil {
+		return fmt.Errorf("failed to update enrollment: %w", err)
+	}
+	return nil
+}
+
+func (r *EnrollmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&enrollment.Enrollment{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete enrollment: %w", err)
+	}
+	return nil
+}
+
+func (r *EnrollmentRepository) List(ctx context.Context, filter enrollment.EnrollmentFilter) ([]*enrollment.Enrollment, int64, error) {
+	var enrollments []*enrollment.Enrollment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&enrollment.Enrollment{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if filter.StudentID != nil {
+		query = query.Where("student_id = ?", *filter.StudentID)
+	}
+
+	if filter.PackageID != nil {
+		query = query.Where("package_id = ?", *filter.PackageID)
+	}
+
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count enrollments: %w", err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("start_date DESC, created_at DESC").Find(&enrollments).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list enrollments: %w", err)
+	}
+
+	return enrollments, total, nil
+}
+
+func (r *EnrollmentRepository) IsEnrolledInCourse(ctx context.Context, studentID, courseID uuid.UUID) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&enrollment.Enrollment{}).
+		Where("student_id = ? AND status <> ? AND deleted_at IS NULL", studentID, enrollment.StatusCancelled).
+		Where("course_id = ? OR package_id IN (SELECT package_id FROM package_courses WHERE course_id = ?)", courseID, courseID)
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check enrollment: %w", err)
+	}
+	return count > 0, nil
+}
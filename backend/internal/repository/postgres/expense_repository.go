@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/chalak/backend/internal/domain/expense"
+	"github.com/chalak/backend/pkg/money"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -19,6 +20,10 @@ func NewExpenseRepository(db *gorm.DB) expense.Repository {
 }
 
 func (r *ExpenseRepository) Create(ctx context.Context, exp *expense.Expense) error {
+	if err := requireActiveInstitute(ctx, r.db, &exp.InstituteID); err != nil {
+		return err
+	}
+
 	if err := r.db.WithContext(ctx).Create(exp).Error; err != nil {
 		return fmt.Errorf("failed to create expense: %w", err)
 	}
@@ -27,7 +32,8 @@ func (r *ExpenseRepository) Create(ctx context.Context, exp *expense.Expense) er
 
 func (r *ExpenseRepository) FindByID(ctx context.Context, id uuid.UUID) (*expense.Expense, error) {
 	var exp expense.Expense
-	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&exp).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&exp).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("expense not found")
 		}
@@ -44,7 +50,8 @@ func (r *ExpenseRepository) Update(ctx context.Context, exp *expense.Expense) er
 }
 
 func (r *ExpenseRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Model(&expense.Expense{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&expense.Expense{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
 		return fmt.Errorf("failed to delete expense: %w", err)
 	}
 	return nil
@@ -55,6 +62,7 @@ func (r *ExpenseRepository) List(ctx context.Context, filter expense.ExpenseFilt
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&expense.Expense{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
 
 	if filter.InstituteID != nil {
 		query = query.Where("institute_id = ?", *filter.InstituteID)
@@ -118,8 +126,8 @@ func (r *ExpenseRepository) Reject(ctx context.Context, id uuid.UUID, rejectedBy
 	return nil
 }
 
-func (r *ExpenseRepository) GetTotalExpenses(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (float64, error) {
-	var total float64
+func (r *ExpenseRepository) GetTotalExpenses(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (money.Amount, error) {
+	var total money.Amount
 	if err := r.db.WithContext(ctx).Model(&expense.Expense{}).
 		Where("institute_id = ? AND status = ? AND date >= ? AND date <= ? AND deleted_at IS NULL", instituteID, expense.StatusApproved, dateFrom, dateTo).
 		Select("COALESCE(SUM(amount), 0)").
@@ -129,10 +137,10 @@ func (r *ExpenseRepository) GetTotalExpenses(ctx context.Context, instituteID uu
 	return total, nil
 }
 
-func (r *ExpenseRepository) GetExpensesByCategory(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (map[string]float64, error) {
+func (r *ExpenseRepository) GetExpensesByCategory(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (map[string]money.Amount, error) {
 	var results []struct {
 		Category string
-		Total    float64
+		Total    money.Amount
 	}
 
 	if err := r.db.WithContext(ctx).
@@ -144,7 +152,7 @@ func (r *ExpenseRepository) GetExpensesByCategory(ctx context.Context, institute
 		return nil, fmt.Errorf("failed to get expenses by category: %w", err)
 	}
 
-	expenses := make(map[string]float64)
+	expenses := make(map[string]money.Amount)
 	for _, result := range results {
 		expenses[result.Category] = result.Total
 	}
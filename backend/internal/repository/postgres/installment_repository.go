@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/installment"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InstallmentRepository struct {
+	db *gorm.DB
+}
+
+func NewInstallmentRepository(db *gorm.DB) installment.Repository {
+	return &InstallmentRepository{db: db}
+}
+
+func (r *InstallmentRepository) Create(ctx context.Context, plan *installment.Plan) error {
+	if err := requireActiveInstitute(ctx, r.db, &plan.InstituteID); err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Create(plan).Error; err != nil {
+		return fmt.Errorf("failed to create installment plan: %w", err)
+	}
+	return nil
+}
+
+func (r *InstallmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*installment.Plan, error) {
+	var plan installment.Plan
+	query := scopeToInstitute(ctx, r.withInstallments(ctx), "institute_id = ?")
+	if err := query.Where("id = ?", id).First(&plan).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("installment plan not found")
+		}
+		return nil, fmt.Errorf("failed to find installment plan: %w", err)
+	}
+	return &plan, nil
+}
+
+func (r *InstallmentRepository) ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*installment.Plan, error) {
+	var plans []*installment.Plan
+	query := scopeToInstitute(ctx, r.withInstallments(ctx), "institute_id = ?")
+	if err := query.Where("student_id = ?", studentID).Order("created_at DESC").Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("failed to list installment plans: %w", err)
+	}
+	return plans, nil
+}
+
+func (r *InstallmentRepository) Update(ctx context.Context, plan *installment.Plan) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(plan).Error; err != nil {
+		return fmt.Errorf("failed to update installment plan: %w", err)
+	}
+	return nil
+}
+
+func (r *InstallmentRepository) FindInstallmentForUpdate(ctx context.Context, id uuid.UUID) (*installment.Installment, error) {
+	var inst installment.Installment
+	if err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).First(&inst).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("installment not found")
+		}
+		return nil, fmt.Errorf("failed to find installment: %w", err)
+	}
+	return &inst, nil
+}
+
+func (r *InstallmentRepository) UpdateInstallment(ctx context.Context, inst *installment.Installment) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(inst).Error; err != nil {
+		return fmt.Errorf("failed to update installment: %w", err)
+	}
+	return nil
+}
+
+func (r *InstallmentRepository) FindUninvoiced(ctx context.Context, dueBefore time.Time) ([]*installment.Plan, error) {
+	var plans []*installment.Plan
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.
+		Preload("Installments", func(db *gorm.DB) *gorm.DB {
+			return db.Where("invoice_id IS NULL AND due_date < ?", dueBefore).Order("sequence")
+		}).
+		Where("status = ?", installment.StatusActive).
+		Where(`EXISTS (
+			SELECT 1 FROM installments
+			WHERE installments.plan_id = installment_plans.id
+				AND installments.invoice_id IS NULL
+				AND installments.due_date < ?
+		)`, dueBefore).
+		Find(&plans).Error; err != nil {
+		return nil, fmt.Errorf("failed to find uninvoiced installments: %w", err)
+	}
+	return plans, nil
+}
+
+func (r *InstallmentRepository) withInstallments(ctx context.Context) *gorm.DB {
+	return conn(ctx, r.db).
+		Preload("Installments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence")
+		}).
+		Preload("Installments.Invoice", "deleted_at IS NULL")
+}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InstituteRepository struct {
+	db *gorm.DB
+}
+
+func NewInstituteRepository(db *gorm.DB) institute.Repository {
+	return &InstituteRepository{db: db}
+}
+
+func (r *InstituteRepository) Create(ctx context.Context, inst *institute.Institute) error {
+	if err := r.db.WithContext(ctx).Create(inst).Error; err != nil {
+		return fmt.Errorf("failed to create institute: %w", err)
+	}
+	return nil
+}
+
+func (r *InstituteRepository) FindByID(ctx context.Context, id uuid.UUID) (*institute.Institute, error) {
+	var inst institute.Institute
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "id = ?")
+	if err := query.Preload("TaxExemptions").Where("id = ? AND deleted_at IS NULL", id).First(&inst).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("institute not found")
+		}
+		return nil, fmt.Errorf("failed to find institute: %w", err)
+	}
+	return &inst, nil
+}
+
+func (r *InstituteRepository) FindByCode(ctx context.Context, code string) (*institute.Institute, error) {
+	var inst institute.Institute
+	if err := r.db.WithContext(ctx).Preload("TaxExemptions").Where("code = ? AND deleted_at IS NULL", code).First(&inst).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("institute not found")
+		}
+		return nil, fmt.Errorf("failed to find institute: %w", err)
+	}
+	return &inst, nil
+}
+
+// Update saves the institute and replaces its tax exemptions with the ones
+// it carries.
+func (r *InstituteRepository) Update(ctx context.Context, inst *institute.Institute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(inst).Error; err != nil {
+			return fmt.Errorf("failed to update institute: %w", err)
+		}
+		if err := tx.Where("institute_id = ?", inst.ID).Delete(&institute.TaxExemption{}).Error; err != nil {
+			return fmt.Errorf("failed to update tax exemptions: %w", err)
+		}
+		if len(inst.TaxExemptions) == 0 {
+			return nil
+		}
+		if err := tx.Create(&inst.TaxExemptions).Error; err != nil {
+			return fmt.Errorf("failed to update tax exemptions: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *InstituteRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Model(&institute.Institute{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete institute: %w", err)
+	}
+	return nil
+}
+
+func (r *InstituteRepository) List(ctx context.Context, filter institute.InstituteFilter) ([]*institute.Institute, int64, error) {
+	var institutes []*institute.Institute
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&institute.Institute{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "id = ?")
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if filter.Search != nil && *filter.Search != "" {
+		search := "%" + *filter.Search + "%"
+		query = query.Where("name ILIKE ? OR code ILIKE ?", search, search)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count institutes: %w", err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Preload("TaxExemptions").Order("name ASC").Find(&institutes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list institutes: %w", err)
+	}
+
+	return institutes, total, nil
+}
+
+func (r *InstituteRepository) IsActive(ctx context.Context, id uuid.UUID) (bool, error) {
+	return isActiveInstitute(ctx, r.db, id)
+}
+
+func isActiveInstitute(ctx context.Context, db *gorm.DB, id uuid.UUID) (bool, error) {
+	var count int64
+	if err := db.WithContext(ctx).Model(&institute.Institute{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, institute.StatusActive).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check institute: %w", err)
+	}
+	return count > 0, nil
+}
+
+// requireActiveInstitute rejects writes that would attach a record to an
+// unknown, deleted or deactivated institute. For tenant-scoped callers an
+// empty id is filled in from the context and a foreign one is refused.
+func requireActiveInstitute(ctx context.Context, db *gorm.DB, id *uuid.UUID) error {
+	if tenantID, ok := tenant.InstituteID(ctx); ok {
+		if *id == uuid.Nil {
+			*id = tenantID
+		} else if *id != tenantID {
+			return apperrors.Forbidden("cannot create records for another institute")
+		}
+	}
+
+	if *id == uuid.Nil {
+		return apperrors.BadRequest("institute_id is required")
+	}
+
+	active, err := isActiveInstitute(ctx, db, *id)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return apperrors.BadRequest("institute not found or inactive")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// instituteTable is the part of 000001_create_institutes_table that does not
+// depend on the rest of the schema.
+const instituteTable = `
+CREATE TABLE institutes (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    deleted_at TIMESTAMP
+);
+`
+
+func TestRequireActiveInstitute(t *testing.T) {
+	db := testDB(t, instituteTable)
+	active, inactive, deleted := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Exec(`INSERT INTO institutes (id, name, code, status, deleted_at) VALUES
+		(?, 'Active', 'ACT', 'active', NULL),
+		(?, 'Inactive', 'INA', 'inactive', NULL),
+		(?, 'Deleted', 'DEL', 'active', CURRENT_TIMESTAMP)`, active, inactive, deleted).Error)
+
+	platform := context.Background()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		id       uuid.UUID
+		wantCode int
+		wantID   uuid.UUID
+	}{
+		{name: "active institute", ctx: platform, id: active, wantID: active},
+		{name: "inactive institute", ctx: platform, id: inactive, wantCode: http.StatusBadRequest},
+		{name: "deleted institute", ctx: platform, id: deleted, wantCode: http.StatusBadRequest},
+		{name: "unknown institute", ctx: platform, id: uuid.New(), wantCode: http.StatusBadRequest},
+		{name: "missing institute", ctx: platform, wantCode: http.StatusBadRequest},
+		{name: "filled in from the tenant", ctx: tenant.WithInstituteID(platform, active), wantID: active},
+		{name: "another tenant's institute", ctx: tenant.WithInstituteID(platform, active), id: inactive, wantCode: http.StatusForbidden},
+		{name: "own institute deactivated", ctx: tenant.WithInstituteID(platform, inactive), wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.id
+			err := requireActiveInstitute(tt.ctx, db, &id)
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+}
+
+func TestInstituteRepositoryHidesOtherTenants(t *testing.T) {
+	db := testDB(t, instituteTable)
+	repo := NewInstituteRepository(db)
+	own, other := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO institutes (id, name, code) VALUES (?, 'Own', 'OWN'), (?, 'Other', 'OTH')", own, other).Error)
+	ctx := tenant.WithInstituteID(context.Background(), own)
+
+	_, err := repo.FindByID(ctx, other)
+	assert.Error(t, err)
+
+	list, total, err := repo.List(ctx, institute.InstituteFilter{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	require.Len(t, list, 1)
+	assert.Equal(t, own, list[0].ID)
+}
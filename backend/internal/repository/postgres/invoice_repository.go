@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/pkg/money"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InvoiceRepository struct {
@@ -19,15 +21,33 @@ func NewInvoiceRepository(db *gorm.DB) invoice.Repository {
 }
 
 func (r *InvoiceRepository) Create(ctx context.Context, inv *invoice.Invoice) error {
-	if err := r.db.WithContext(ctx).Create(inv).Error; err != nil {
+	if err := requireActiveInstitute(ctx, r.db, &inv.InstituteID); err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Create(inv).Error; err != nil {
 		return fmt.Errorf("failed to create invoice: %w", err)
 	}
 	return nil
 }
 
+func (r *InvoiceRepository) NextNumber(ctx context.Context, instituteID uuid.UUID, fiscalYear string) (int64, error) {
+	var next int64
+	if err := conn(ctx, r.db).Raw(`
+		INSERT INTO invoice_sequences (institute_id, fiscal_year, last_number)
+		VALUES (?, ?, 1)
+		ON CONFLICT (institute_id, fiscal_year)
+		DO UPDATE SET last_number = invoice_sequences.last_number + 1, updated_at = CURRENT_TIMESTAMP
+		RETURNING last_number`, instituteID, fiscalYear).Scan(&next).Error; err != nil {
+		return 0, fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+	return next, nil
+}
+
 func (r *InvoiceRepository) FindByID(ctx context.Context, id uuid.UUID) (*invoice.Invoice, error) {
 	var inv invoice.Invoice
-	if err := r.db.WithContext(ctx).Preload("Items").Where("invoices.id = ? AND invoices.deleted_at IS NULL", id).First(&inv).Error; err != nil {
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "invoices.institute_id = ?")
+	if err := query.Preload("Items").Where("invoices.id = ? AND invoices.deleted_at IS NULL", id).First(&inv).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("invoice not found")
 		}
@@ -36,9 +56,11 @@ func (r *InvoiceRepository) FindByID(ctx context.Context, id uuid.UUID) (*invoic
 	return &inv, nil
 }
 
-func (r *InvoiceRepository) FindByInvoiceNumber(ctx context.Context, invoiceNumber string) (*invoice.Invoice, error) {
+func (r *InvoiceRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*invoice.Invoice, error) {
 	var inv invoice.Invoice
-	if err := r.db.WithContext(ctx).Preload("Items").Where("invoice_number = ? AND deleted_at IS NULL", invoiceNumber).First(&inv).Error; err != nil {
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", id).First(&inv).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("invoice not found")
 		}
@@ -47,16 +69,21 @@ func (r *InvoiceRepository) FindByInvoiceNumber(ctx context.Context, invoiceNumb
 	return &inv, nil
 }
 
-func (r *InvoiceRepository) Update(ctx context.Context, inv *invoice.Invoice) error {
-	if err := r.db.WithContext(ctx).Save(inv).Error; err != nil {
-		return fmt.Errorf("failed to update invoice: %w", err)
+func (r *InvoiceRepository) FindByInvoiceNumber(ctx context.Context, invoiceNumber string) (*invoice.Invoice, error) {
+	var inv invoice.Invoice
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Preload("Items").Where("invoice_number = ? AND deleted_at IS NULL", invoiceNumber).First(&inv).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
 	}
-	return nil
+	return &inv, nil
 }
 
-func (r *InvoiceRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Model(&invoice.Invoice{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
-		return fmt.Errorf("failed to delete invoice: %w", err)
+func (r *InvoiceRepository) Update(ctx context.Context, inv *invoice.Invoice) error {
+	if err := conn(ctx, r.db).Save(inv).Error; err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
 	}
 	return nil
 }
@@ -65,7 +92,8 @@ func (r *InvoiceRepository) List(ctx context.Context, filter invoice.InvoiceFilt
 	var invoices []*invoice.Invoice
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&invoice.Invoice{}).Where("deleted_at IS NULL")
+	query := conn(ctx, r.db).Model(&invoice.Invoice{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
 
 	if filter.StudentID != nil {
 		query = query.Where("student_id = ?", *filter.StudentID)
@@ -105,22 +133,11 @@ func (r *InvoiceRepository) List(ctx context.Context, filter invoice.InvoiceFilt
 	return invoices, total, nil
 }
 
-func (r *InvoiceRepository) MarkAsPaid(ctx context.Context, id uuid.UUID) error {
-	now := time.Now().UTC()
-	if err := r.db.WithContext(ctx).Model(&invoice.Invoice{}).Where("id = ?", id).Updates(map[string]interface{}{
-		"status":  invoice.StatusPaid,
-		"paid_at": now,
-	}).Error; err != nil {
-		return fmt.Errorf("failed to mark invoice as paid: %w", err)
-	}
-	return nil
-}
-
-func (r *InvoiceRepository) GetTotalRevenue(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (float64, error) {
-	var total float64
-	if err := r.db.WithContext(ctx).Model(&invoice.Invoice{}).
+func (r *InvoiceRepository) GetTotalRevenue(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (money.Amount, error) {
+	var total money.Amount
+	if err := conn(ctx, r.db).Model(&invoice.Invoice{}).
 		Where("institute_id = ? AND status = ? AND created_at >= ? AND created_at <= ? AND deleted_at IS NULL", instituteID, invoice.StatusPaid, dateFrom, dateTo).
-		Select("COALESCE(SUM(total_amount), 0)").
+		Select("COALESCE(SUM(total_amount - credited_amount), 0)").
 		Scan(&total).Error; err != nil {
 		return 0, fmt.Errorf("failed to get total revenue: %w", err)
 	}
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// exclusionViolation is the SQLSTATE raised by the lesson_sessions exclusion
+// constraints, which back up checkBookings for instructors and vehicles.
+const exclusionViolation = "23P01"
+
+// checkBookings serialises bookings of the session's instructor, vehicle and
+// the given students for the rest of tx, then returns a *lesson.ConflictError
+// if any of them is already booked in another session over an overlapping
+// range. Cancelled sessions book nothing.
+func checkBookings(tx *gorm.DB, session *lesson.Session, studentIDs []uuid.UUID) error {
+	if session.Status == lesson.StatusCancelled {
+		return nil
+	}
+
+	resources := append([]uuid.UUID{session.InstructorID}, studentIDs...)
+	if session.VehicleID != nil {
+		resources = append(resources, *session.VehicleID)
+	}
+	if err := lockResources(tx, resources); err != nil {
+		return err
+	}
+
+	var conflicts []lesson.Conflict
+
+	var sessions []struct {
+		ID           uuid.UUID
+		InstructorID uuid.UUID
+		VehicleID    *uuid.UUID
+		StartTime    time.Time
+		EndTime      time.Time
+	}
+	query := tx.Model(&lesson.Session{}).
+		Select("id, instructor_id, vehicle_id, start_time, end_time").
+		Where("deleted_at IS NULL AND status <> ? AND id <> ?", lesson.StatusCancelled, session.ID).
+		Where("start_time < ? AND end_time > ?", session.EndTime, session.StartTime)
+	if session.VehicleID != nil {
+		query = query.Where("instructor_id = ? OR vehicle_id = ?", session.InstructorID, *session.VehicleID)
+	} else {
+		query = query.Where("instructor_id = ?", session.InstructorID)
+	}
+	if err := query.Order("start_time ASC").Scan(&sessions).Error; err != nil {
+		return fmt.Errorf("failed to check lesson bookings: %w", err)
+	}
+
+	for _, s := range sessions {
+		if s.InstructorID == session.InstructorID {
+			conflicts = append(conflicts, lesson.Conflict{
+				Resource:   lesson.ResourceInstructor,
+				ResourceID: s.InstructorID,
+				SessionID:  s.ID,
+				StartTime:  s.StartTime,
+				EndTime:    s.EndTime,
+			})
+		}
+		if session.VehicleID != nil && s.VehicleID != nil && *s.VehicleID == *session.VehicleID {
+			conflicts = append(conflicts, lesson.Conflict{
+				Resource:   lesson.ResourceVehicle,
+				ResourceID: *s.VehicleID,
+				SessionID:  s.ID,
+				StartTime:  s.StartTime,
+				EndTime:    s.EndTime,
+			})
+		}
+	}
+
+	if len(studentIDs) > 0 {
+		var bookings []struct {
+			StudentID uuid.UUID
+			SessionID uuid.UUID
+			StartTime time.Time
+			EndTime   time.Time
+		}
+		if err := tx.Table("lesson_session_students ss").
+			Select("ss.student_id, s.id AS session_id, s.start_time, s.end_time").
+			Joins("JOIN lesson_sessions s ON s.id = ss.session_id").
+			Where("s.deleted_at IS NULL AND s.status <> ? AND s.id <> ?", lesson.StatusCancelled, session.ID).
+			Where("s.start_time < ? AND s.end_time > ?", session.EndTime, session.StartTime).
+			Where("ss.student_id IN ?", studentIDs).
+			Order("s.start_time ASC").
+			Scan(&bookings).Error; err != nil {
+			return fmt.Errorf("failed to check student bookings: %w", err)
+		}
+
+		for _, b := range bookings {
+			conflicts = append(conflicts, lesson.Conflict{
+				Resource:   lesson.ResourceStudent,
+				ResourceID: b.StudentID,
+				SessionID:  b.SessionID,
+				StartTime:  b.StartTime,
+				EndTime:    b.EndTime,
+			})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return &lesson.ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// lockResources takes a transaction-scoped advisory lock per resource, in a
+// fixed order so that two bookings sharing resources cannot deadlock.
+func lockResources(tx *gorm.DB, ids []uuid.UUID) error {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, "lesson:"+id.String())
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		if i > 0 && key == keys[i-1] {
+			continue
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error; err != nil {
+			return fmt.Errorf("failed to lock booking resource: %w", err)
+		}
+	}
+	return nil
+}
+
+// bookingError reports an exclusion constraint violation as a conflict. The
+// violation means another booking committed after checkBookings ran, so the
+// check is repeated in a fresh transaction to list what the session clashes
+// with.
+func bookingError(db *gorm.DB, session *lesson.Session, studentIDs []uuid.UUID, err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != exclusionViolation {
+		return err
+	}
+
+	recheck := db.Transaction(func(tx *gorm.DB) error {
+		return checkBookings(tx, session, studentIDs)
+	})
+	var conflictErr *lesson.ConflictError
+	if errors.As(recheck, &conflictErr) {
+		return conflictErr
+	}
+	return &lesson.ConflictError{}
+}
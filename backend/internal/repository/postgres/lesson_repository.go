@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/lesson"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LessonRepository struct {
+	db *gorm.DB
+}
+
+func NewLessonRepository(db *gorm.DB) lesson.Repository {
+	return &LessonRepository{db: db}
+}
+
+func (r *LessonRepository) Create(ctx context.Context, session *lesson.Session) error {
+	if err := requireActiveInstitute(ctx, r.db, &session.InstituteID); err != nil {
+		return err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkBookings(tx, session, session.StudentIDs); err != nil {
+			return err
+		}
+
+		if err := tx.Create(session).Error; err != nil {
+			return fmt.Errorf("failed to create lesson session: %w", err)
+		}
+
+		for _, studentID := range session.StudentIDs {
+			if err := tx.Create(&lesson.SessionStudent{SessionID: session.ID, StudentID: studentID}).Error; err != nil {
+				return fmt.Errorf("failed to add student to lesson session: %w", err)
+			}
+		}
+		return nil
+	})
+	return bookingError(r.db.WithContext(ctx), session, session.StudentIDs, err)
+}
+
+func (r *LessonRepository) FindByID(ctx context.Context, id uuid.UUID) (*lesson.Session, error) {
+	var session lesson.Session
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("lesson session not found")
+		}
+		return nil, fmt.Errorf("failed to find lesson session: %w", err)
+	}
+
+	studentIDs, err := r.GetStudents(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	session.StudentIDs = studentIDs
+
+	return &session, nil
+}
+
+func (r *LessonRepository) Update(ctx context.Context, session *lesson.Session) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkBookings(tx, session, session.StudentIDs); err != nil {
+			return err
+		}
+
+		if err := tx.Save(session).Error; err != nil {
+			return fmt.Errorf("failed to update lesson session: %w", err)
+		}
+		return nil
+	})
+	return bookingError(r.db.WithContext(ctx), session, session.StudentIDs, err)
+}
+
+func (r *LessonRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&lesson.Session{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete lesson session: %w", err)
+	}
+	return nil
+}
+
+func (r *LessonRepository) List(ctx context.Context, filter lesson.SessionFilter) ([]*lesson.Session, int64, error) {
+	var sessions []*lesson.Session
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&lesson.Session{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if filter.CourseID != nil {
+		query = query.Where("course_id = ?", *filter.CourseID)
+	}
+
+	if filter.InstructorID != nil {
+		query = query.Where("instructor_id = ?", *filter.InstructorID)
+	}
+
+	if filter.StudentID != nil {
+		query = query.Where("id IN (SELECT session_id FROM lesson_session_students WHERE student_id = ?)", *filter.StudentID)
+	}
+
+	if filter.Type != nil {
+		query = query.Where("type = ?", *filter.Type)
+	}
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if filter.DateFrom != nil {
+		query = query.Where("start_time >= ?", *filter.DateFrom)
+	}
+
+	if filter.DateTo != nil {
+		query = query.Where("start_time < ?", *filter.DateTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count lesson sessions: %w", err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("start_time ASC").Find(&sessions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list lesson sessions: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		return sessions, total, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(sessions))
+	byID := make(map[uuid.UUID]*lesson.Session, len(sessions))
+	for _, s := range sessions {
+		s.StudentIDs = make([]uuid.UUID, 0)
+		ids = append(ids, s.ID)
+		byID[s.ID] = s
+	}
+
+	var roster []lesson.SessionStudent
+	if err := r.db.WithContext(ctx).Where("session_id IN ?", ids).Order("created_at ASC").Find(&roster).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load lesson session students: %w", err)
+	}
+	for _, entry := range roster {
+		byID[entry.SessionID].StudentIDs = append(byID[entry.SessionID].StudentIDs, entry.StudentID)
+	}
+
+	return sessions, total, nil
+}
+
+func (r *LessonRepository) AddStudents(ctx context.Context, session *lesson.Session, studentIDs []uuid.UUID) error {
+	if len(studentIDs) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the session so concurrent additions can't overfill it.
+		var locked lesson.Session
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", session.ID).
+			First(&locked).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("lesson session not found")
+			}
+			return fmt.Errorf("failed to lock lesson session: %w", err)
+		}
+
+		var booked int64
+		if err := tx.Model(&lesson.SessionStudent{}).
+			Where("session_id = ? AND student_id NOT IN ?", session.ID, studentIDs).
+			Count(&booked).Error; err != nil {
+			return fmt.Errorf("failed to count lesson session students: %w", err)
+		}
+		if int(booked)+len(studentIDs) > locked.Capacity {
+			return apperrors.BadRequest("session is full")
+		}
+
+		if err := checkBookings(tx, &locked, studentIDs); err != nil {
+			return err
+		}
+
+		for _, studentID := range studentIDs {
+			entry := &lesson.SessionStudent{SessionID: session.ID, StudentID: studentID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(entry).Error; err != nil {
+				return fmt.Errorf("failed to add student to lesson session: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *LessonRepository) RemoveStudent(ctx context.Context, sessionID, studentID uuid.UUID) error {
+	if err := r.db.WithContext(ctx).
+		Where("session_id = ? AND student_id = ?", sessionID, studentID).
+		Delete(&lesson.SessionStudent{}).Error; err != nil {
+		return fmt.Errorf("failed to remove student from lesson session: %w", err)
+	}
+	return nil
+}
+
+func (r *LessonRepository) GetStudents(ctx context.Context, sessionID uuid.UUID) ([]uuid.UUID, error) {
+	studentIDs := make([]uuid.UUID, 0)
+	if err := r.db.WithContext(ctx).Model(&lesson.SessionStudent{}).
+		Where("session_id = ?", sessionID).
+		Order("created_at ASC").
+		Pluck("student_id", &studentIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get lesson session students: %w", err)
+	}
+	return studentIDs, nil
+}
+
+func (r *LessonRepository) HasStudent(ctx context.Context, sessionID, studentID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&lesson.SessionStudent{}).
+		Where("session_id = ? AND student_id = ?", sessionID, studentID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check lesson session student: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// lessonTables is the part of the schema from before the migrations that
+// the lesson queries read.
+const lessonTables = `
+CREATE TABLE vehicles (
+    id UUID PRIMARY KEY
+);
+
+CREATE TABLE lesson_sessions (
+    id UUID PRIMARY KEY,
+    institute_id UUID NOT NULL,
+    course_id UUID NOT NULL,
+    instructor_id UUID NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    capacity INT NOT NULL,
+    location VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE lesson_session_students (
+    session_id UUID NOT NULL,
+    student_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, student_id)
+);
+`
+
+type lessonFixture struct {
+	db           *gorm.DB
+	repo         lesson.Repository
+	instituteID  uuid.UUID
+	instructorID uuid.UUID
+	vehicleID    uuid.UUID
+}
+
+func newLessonFixture(t *testing.T) *lessonFixture {
+	db := testDB(t, instituteTable, lessonTables, migration(t, "000006_add_lesson_booking_constraints.up.sql"))
+	f := &lessonFixture{
+		db:           db,
+		repo:         NewLessonRepository(db),
+		instituteID:  uuid.New(),
+		instructorID: uuid.New(),
+		vehicleID:    uuid.New(),
+	}
+	require.NoError(t, db.Exec("INSERT INTO institutes (id, name, code) VALUES (?, 'Himalayan Driving School', 'HDS')", f.instituteID).Error)
+	require.NoError(t, db.Exec("INSERT INTO vehicles (id) VALUES (?)", f.vehicleID).Error)
+	return f
+}
+
+// session returns an unsaved practical lesson with the fixture's instructor
+// and vehicle.
+func (f *lessonFixture) session(start time.Time, length time.Duration) *lesson.Session {
+	return &lesson.Session{
+		ID:           uuid.New(),
+		InstituteID:  f.instituteID,
+		CourseID:     uuid.New(),
+		InstructorID: f.instructorID,
+		VehicleID:    &f.vehicleID,
+		StartTime:    start,
+		EndTime:      start.Add(length),
+		Type:         lesson.TypePractical,
+		Capacity:     1,
+		Status:       lesson.StatusScheduled,
+	}
+}
+
+func TestCreateSessionListsDoubleBookings(t *testing.T) {
+	f := newLessonFixture(t)
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	booked := f.session(start, time.Hour)
+	require.NoError(t, f.repo.Create(ctx, booked))
+
+	err := f.repo.Create(ctx, f.session(start.Add(30*time.Minute), time.Hour))
+
+	var conflictErr *lesson.ConflictError
+	require.True(t, errors.As(err, &conflictErr), "expected a ConflictError, got %v", err)
+	require.Len(t, conflictErr.Conflicts, 2)
+	assert.Equal(t, lesson.ResourceInstructor, conflictErr.Conflicts[0].Resource)
+	assert.Equal(t, lesson.ResourceVehicle, conflictErr.Conflicts[1].Resource)
+	for _, c := range conflictErr.Conflicts {
+		assert.Equal(t, booked.ID, c.SessionID)
+	}
+
+	require.NoError(t, f.repo.Create(ctx, f.session(start.Add(time.Hour), time.Hour)), "back-to-back lessons do not overlap")
+}
+
+func TestBookingErrorListsConflictsCaughtByTheConstraint(t *testing.T) {
+	f := newLessonFixture(t)
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	// A booking that committed after the new session's own check ran.
+	booked := f.session(start, time.Hour)
+	require.NoError(t, f.db.Create(booked).Error)
+
+	late := f.session(start.Add(30*time.Minute), time.Hour)
+	err := f.db.Create(late).Error
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr), "expected the exclusion constraint to fire, got %v", err)
+
+	err = bookingError(f.db, late, nil, err)
+
+	var conflictErr *lesson.ConflictError
+	require.True(t, errors.As(err, &conflictErr), "expected a ConflictError, got %v", err)
+	require.Len(t, conflictErr.Conflicts, 2)
+	for _, c := range conflictErr.Conflicts {
+		assert.Equal(t, booked.ID, c.SessionID)
+	}
+
+	other := errors.New("connection reset")
+	assert.Same(t, other, bookingError(f.db, late, nil, other))
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/licensetest"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LicenseTestRepository struct {
+	db *gorm.DB
+}
+
+func NewLicenseTestRepository(db *gorm.DB) licensetest.Repository {
+	return &LicenseTestRepository{db: db}
+}
+
+func (r *LicenseTestRepository) Create(ctx context.Context, t *licensetest.LicenseTest) error {
+	if err := requireActiveInstitute(ctx, r.db, &t.InstituteID); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(t).Error; err != nil {
+		return fmt.Errorf("failed to create license test: %w", err)
+	}
+	return nil
+}
+
+func (r *LicenseTestRepository) FindByID(ctx context.Context, id uuid.UUID) (*licensetest.LicenseTest, error) {
+	var t licensetest.LicenseTest
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&t).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("license test not found")
+		}
+		return nil, fmt.Errorf("failed to find license test: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *LicenseTestRepository) Update(ctx context.Context, t *licensetest.LicenseTest) error {
+	if err := r.db.WithContext(ctx).Save(t).Error; err != nil {
+		return fmt.Errorf("failed to update license test: %w", err)
+	}
+	return nil
+}
+
+func (r *LicenseTestRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&licensetest.LicenseTest{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete license test: %w", err)
+	}
+	return nil
+}
+
+func (r *LicenseTestRepository) List(ctx context.Context, filter licensetest.LicenseTestFilter) ([]*licensetest.LicenseTest, int64, error) {
+	var tests []*licensetest.LicenseTest
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&licensetest.LicenseTest{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if filter.StudentID != nil {
+		query = query.Where("student_id = ?", *filter.StudentID)
+	}
+
+	if filter.Type != nil {
+		query = query.Where("type = ?", *filter.Type)
+	}
+
+	if filter.Result != nil {
+		query = query.Where("result = ?", *filter.Result)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count license tests: %w", err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("scheduled_date DESC, attempt_number DESC").Find(&tests).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list license tests: %w", err)
+	}
+
+	return tests, total, nil
+}
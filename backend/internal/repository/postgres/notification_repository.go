@@ -27,7 +27,8 @@ func (r *NotificationRepository) Create(ctx context.Context, notif *notification
 
 func (r *NotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*notification.Notification, error) {
 	var notif notification.Notification
-	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&notif).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), userInInstitute)
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&notif).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("notification not found")
 		}
@@ -44,7 +45,8 @@ func (r *NotificationRepository) Update(ctx context.Context, notif *notification
 }
 
 func (r *NotificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := r.db.WithContext(ctx).Model(&notification.Notification{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&notification.Notification{}), userInInstitute)
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
 		return fmt.Errorf("failed to delete notification: %w", err)
 	}
 	return nil
@@ -55,6 +57,7 @@ func (r *NotificationRepository) List(ctx context.Context, filter notification.N
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&notification.Notification{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, userInInstitute)
 
 	if filter.UserID != nil {
 		query = query.Where("user_id = ?", *filter.UserID)
@@ -116,4 +119,14 @@ func (r *NotificationRepository) GetUnreadCount(ctx context.Context, userID uuid
 		return 0, fmt.Errorf("failed to get unread notification count: %w", err)
 	}
 	return count, nil
-}
\ No newline at end of file
+}
+
+func (r *NotificationRepository) ExistsWithData(ctx context.Context, userID uuid.UUID, notifType string, data string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&notification.Notification{}).
+		Where("user_id = ? AND type = ? AND data @> ?::jsonb AND deleted_at IS NULL", userID, notifType, data).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check notification: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chalak/backend/internal/domain/promo"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromoRepository struct {
+	db *gorm.DB
+}
+
+func NewPromoRepository(db *gorm.DB) promo.Repository {
+	return &PromoRepository{db: db}
+}
+
+func (r *PromoRepository) Create(ctx context.Context, code *promo.PromoCode) error {
+	if err := requireActiveInstitute(ctx, r.db, &code.InstituteID); err != nil {
+		return err
+	}
+
+	if err := conn(ctx, r.db).Create(code).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return apperrors.Conflict("promo code already exists")
+		}
+		return fmt.Errorf("failed to create promo code: %w", err)
+	}
+	return nil
+}
+
+func (r *PromoRepository) FindByID(ctx context.Context, id uuid.UUID) (*promo.PromoCode, error) {
+	var code promo.PromoCode
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Preload("Targets").Where("id = ? AND deleted_at IS NULL", id).First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("promo code not found")
+		}
+		return nil, fmt.Errorf("failed to find promo code: %w", err)
+	}
+	return &code, nil
+}
+
+func (r *PromoRepository) FindByCodeForUpdate(ctx context.Context, instituteID uuid.UUID, code string) (*promo.PromoCode, error) {
+	var pc promo.PromoCode
+	if err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("institute_id = ? AND UPPER(code) = ? AND deleted_at IS NULL", instituteID, strings.ToUpper(code)).
+		First(&pc).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("promo code not found")
+		}
+		return nil, fmt.Errorf("failed to find promo code: %w", err)
+	}
+
+	if err := conn(ctx, r.db).Where("promo_code_id = ?", pc.ID).Find(&pc.Targets).Error; err != nil {
+		return nil, fmt.Errorf("failed to load promo code targets: %w", err)
+	}
+	return &pc, nil
+}
+
+func (r *PromoRepository) Update(ctx context.Context, code *promo.PromoCode) error {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(code).Error; err != nil {
+		return fmt.Errorf("failed to update promo code: %w", err)
+	}
+	return nil
+}
+
+func (r *PromoRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, conn(ctx, r.db).Model(&promo.PromoCode{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete promo code: %w", err)
+	}
+	return nil
+}
+
+func (r *PromoRepository) List(ctx context.Context, filter promo.PromoCodeFilter) ([]*promo.PromoCode, int64, error) {
+	var codes []*promo.PromoCode
+	var total int64
+
+	query := conn(ctx, r.db).Model(&promo.PromoCode{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count promo codes: %w", err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Preload("Targets").Order("created_at DESC").Find(&codes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list promo codes: %w", err)
+	}
+
+	return codes, total, nil
+}
+
+func (r *PromoRepository) CreateRedemption(ctx context.Context, redemption *promo.Redemption) error {
+	if err := conn(ctx, r.db).Create(redemption).Error; err != nil {
+		return fmt.Errorf("failed to create promo redemption: %w", err)
+	}
+	return nil
+}
+
+func (r *PromoRepository) CountRedemptions(ctx context.Context, promoCodeID uuid.UUID, studentID *uuid.UUID) (int, error) {
+	var count int64
+	query := conn(ctx, r.db).Model(&promo.Redemption{}).
+		Joins("JOIN invoices ON invoices.id = promo_redemptions.invoice_id AND invoices.deleted_at IS NULL AND invoices.status <> 'canceled'").
+		Where("promo_redemptions.promo_code_id = ?", promoCodeID)
+	if studentID != nil {
+		query = query.Where("promo_redemptions.student_id = ?", *studentID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count promo redemptions: %w", err)
+	}
+	return int(count), nil
+}
+
+func (r *PromoRepository) Usage(ctx context.Context, promoCodeID uuid.UUID) (*promo.Usage, error) {
+	var usage promo.Usage
+	if err := conn(ctx, r.db).Raw(`
+		SELECT COUNT(*) as uses, COALESCE(SUM(promo_redemptions.amount), 0) as discount
+		FROM promo_redemptions
+		JOIN invoices ON invoices.id = promo_redemptions.invoice_id AND invoices.deleted_at IS NULL AND invoices.status <> 'canceled'
+		WHERE promo_redemptions.promo_code_id = ?
+	`, promoCodeID).Scan(&usage).Error; err != nil {
+		return nil, fmt.Errorf("failed to get promo code usage: %w", err)
+	}
+	return &usage, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/refund"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) refund.Repository {
+	return &RefundRepository{db: db}
+}
+
+func (r *RefundRepository) CreateCreditNote(ctx context.Context, note *refund.CreditNote) error {
+	if err := conn(ctx, r.db).Create(note).Error; err != nil {
+		return fmt.Errorf("failed to create credit note: %w", err)
+	}
+	return nil
+}
+
+func (r *RefundRepository) FindCreditNote(ctx context.Context, id uuid.UUID) (*refund.CreditNote, error) {
+	var note refund.CreditNote
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Where("id = ?", id).First(&note).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("credit note not found")
+		}
+		return nil, fmt.Errorf("failed to find credit note: %w", err)
+	}
+	return &note, nil
+}
+
+func (r *RefundRepository) ListCreditNotes(ctx context.Context, invoiceID uuid.UUID) ([]*refund.CreditNote, error) {
+	var notes []*refund.CreditNote
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Where("invoice_id = ?", invoiceID).Order("created_at DESC").Find(&notes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list credit notes: %w", err)
+	}
+	return notes, nil
+}
+
+func (r *RefundRepository) UpdateCreditNote(ctx context.Context, note *refund.CreditNote) error {
+	if err := conn(ctx, r.db).Save(note).Error; err != nil {
+		return fmt.Errorf("failed to update credit note: %w", err)
+	}
+	return nil
+}
+
+func (r *RefundRepository) CreateReversal(ctx context.Context, reversal *refund.Reversal) error {
+	if err := conn(ctx, r.db).Create(reversal).Error; err != nil {
+		return fmt.Errorf("failed to create payment reversal: %w", err)
+	}
+	return nil
+}
+
+func (r *RefundRepository) FindReversal(ctx context.Context, id uuid.UUID) (*refund.Reversal, error) {
+	var reversal refund.Reversal
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Where("id = ?", id).First(&reversal).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment reversal not found")
+		}
+		return nil, fmt.Errorf("failed to find payment reversal: %w", err)
+	}
+	return &reversal, nil
+}
+
+func (r *RefundRepository) ListReversals(ctx context.Context, paymentID uuid.UUID) ([]*refund.Reversal, error) {
+	var reversals []*refund.Reversal
+	query := scopeToInstitute(ctx, conn(ctx, r.db), "institute_id = ?")
+	if err := query.Where("payment_id = ?", paymentID).Order("created_at DESC").Find(&reversals).Error; err != nil {
+		return nil, fmt.Errorf("failed to list payment reversals: %w", err)
+	}
+	return reversals, nil
+}
+
+func (r *RefundRepository) UpdateReversal(ctx context.Context, reversal *refund.Reversal) error {
+	if err := conn(ctx, r.db).Save(reversal).Error; err != nil {
+		return fmt.Errorf("failed to update payment reversal: %w", err)
+	}
+	return nil
+}
+
+func (r *RefundRepository) ReversedAmount(ctx context.Context, paymentID uuid.UUID) (money.Amount, error) {
+	var total money.Amount
+	if err := conn(ctx, r.db).Model(&refund.Reversal{}).
+		Where("payment_id = ? AND status IN ?", paymentID, []string{refund.StatusPending, refund.StatusApproved}).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to get reversed amount: %w", err)
+	}
+	return total, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/chalak/backend/pkg/bs"
+	"github.com/chalak/backend/pkg/money"
+	"gorm.io/gorm"
+)
+
+// dailyTotal is one day's sum and row count, the unit monthly report figures
+// are built from. Months are assembled in Go rather than SQL because BS
+// months do not line up with anything Postgres can truncate to.
+type dailyTotal struct {
+	Day    time.Time
+	Amount money.Amount
+	Count  int
+}
+
+// dailyTotals runs a query selecting day, amount and count columns.
+func dailyTotals(ctx context.Context, db *gorm.DB, query string, args ...interface{}) ([]dailyTotal, error) {
+	var totals []dailyTotal
+	if err := db.WithContext(ctx).Raw(query, args...).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+type monthKey struct {
+	year  int
+	month int
+}
+
+// reportMonth is a month of a report period, in AD or BS.
+type reportMonth struct {
+	Year  int
+	Month int
+	Name  string
+}
+
+// monthRange lists every month a report period touches, quiet months
+// included, so charts get an unbroken axis. It counts in BS when the request
+// asked for it and the whole period falls within the BS calendar table.
+type monthRange struct {
+	bs     bool
+	months []reportMonth
+	index  map[monthKey]int
+}
+
+func newMonthRange(ctx context.Context, start, end time.Time) *monthRange {
+	mr := &monthRange{index: make(map[monthKey]int)}
+
+	first, last := adMonth(start), adMonth(end)
+	if bs.Requested(ctx) {
+		from, errFrom := bs.FromAD(start)
+		to, errTo := bs.FromAD(end)
+		if errFrom == nil && errTo == nil {
+			mr.bs = true
+			first = monthKey{year: from.Year, month: from.Month}
+			last = monthKey{year: to.Year, month: to.Month}
+		}
+	}
+
+	for m := first; !after(m, last); m = nextMonth(m) {
+		name := time.Month(m.month).String()
+		if mr.bs {
+			name = bs.MonthName(m.month)
+		}
+		mr.index[m] = len(mr.months)
+		mr.months = append(mr.months, reportMonth{Year: m.year, Month: m.month, Name: name})
+	}
+	return mr
+}
+
+// find returns the position of the month day falls in.
+func (mr *monthRange) find(day time.Time) (int, bool) {
+	key := adMonth(day)
+	if mr.bs {
+		d, err := bs.FromAD(day)
+		if err != nil {
+			return 0, false
+		}
+		key = monthKey{year: d.Year, month: d.Month}
+	}
+	i, ok := mr.index[key]
+	return i, ok
+}
+
+func adMonth(t time.Time) monthKey {
+	return monthKey{year: t.Year(), month: int(t.Month())}
+}
+
+func nextMonth(m monthKey) monthKey {
+	if m.month == 12 {
+		return monthKey{year: m.year + 1, month: 1}
+	}
+	return monthKey{year: m.year, month: m.month + 1}
+}
+
+func after(a, b monthKey) bool {
+	return a.year > b.year || (a.year == b.year && a.month > b.month)
+}
@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/chalak/backend/internal/domain/report"
+	"github.com/chalak/backend/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +37,7 @@ func (r *reportRepository) GetAttendanceReport(ctx context.Context, startDate, e
 	}
 
 	var stats AttendanceStats
+	scope, args := instituteSQL(ctx, studentInInstitute, []interface{}{startDate, endDate})
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
 			COUNT(DISTINCT student_id) as total_students,
@@ -45,7 +48,7 @@ func (r *reportRepository) GetAttendanceReport(ctx context.Context, startDate, e
 			SUM(CASE WHEN status = 'excused' THEN 1 ELSE 0 END) as excused_count
 		FROM attendances
 		WHERE date >= ? AND date <= ? AND deleted_at IS NULL
-	`, startDate, endDate).Scan(&stats).Error
+	`+scope, args...).Scan(&stats).Error
 
 	if err != nil {
 		return nil, err
@@ -72,6 +75,7 @@ func (r *reportRepository) GetAttendanceReport(ctx context.Context, startDate, e
 func (r *reportRepository) GetStudentAttendanceReport(ctx context.Context, studentID string, startDate, endDate time.Time) (*report.StudentAttendanceStat, error) {
 	var stat report.StudentAttendanceStat
 
+	scope, args := instituteSQL(ctx, "s.institute_id = ?", []interface{}{startDate, endDate, studentID})
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
 			s.id as student_id,
@@ -86,9 +90,9 @@ func (r *reportRepository) GetStudentAttendanceReport(ctx context.Context, stude
 		LEFT JOIN attendances a ON s.id = a.student_id
 			AND a.date >= ? AND a.date <= ?
 			AND a.deleted_at IS NULL
-		WHERE s.id = ? AND s.deleted_at IS NULL
+		WHERE s.id = ? AND s.deleted_at IS NULL`+scope+`
 		GROUP BY s.id, s.name, s.phone
-	`, startDate, endDate, studentID).Scan(&stat).Error
+	`, args...).Scan(&stat).Error
 
 	if err != nil {
 		return nil, err
@@ -109,18 +113,59 @@ func (r *reportRepository) GetFinancialReport(ctx context.Context, startDate, en
 	}
 
 	// Get revenue from payments
+	scope, args := instituteSQL(ctx, invoiceInInstitute, []interface{}{startDate, endDate})
 	r.db.WithContext(ctx).Raw(`
 		SELECT COALESCE(SUM(amount), 0)
 		FROM payments
 		WHERE payment_date >= ? AND payment_date <= ? AND deleted_at IS NULL
-	`, startDate, endDate).Scan(&rep.TotalRevenue)
+	`+scope, args...).Scan(&rep.TotalRevenue)
+
+	// Approved reversals count against revenue in the period they were
+	// approved; the payments themselves are never edited.
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(amount), 0)
+		FROM payment_reversals
+		WHERE status = 'approved' AND approved_at >= ? AND approved_at <= ?
+	`+scope, args...).Scan(&rep.Refunds)
+	rep.TotalRevenue -= rep.Refunds
+
+	// Credit notes lower what is owed rather than what was received, so they
+	// are reported alongside revenue instead of out of it.
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(amount), 0)
+		FROM credit_notes
+		WHERE status = 'approved' AND approved_at >= ? AND approved_at <= ?
+	`+scope, args...).Scan(&rep.CreditNotes)
+
+	// Promo code discounts given on invoices raised in the period
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(discount_amount), 0)
+		FROM invoices
+		WHERE created_at >= ? AND created_at <= ? AND deleted_at IS NULL AND status <> 'canceled'
+	`+scope, args...).Scan(&rep.Discounts)
+
+	rep.DiscountCodes = make([]report.DiscountCodeStat, 0)
+	scope, args = instituteSQL(ctx, "pr.institute_id = ?", []interface{}{startDate, endDate})
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT pc.code, COUNT(*) as uses, COALESCE(SUM(pr.amount), 0) as amount
+		FROM promo_redemptions pr
+		JOIN promo_codes pc ON pc.id = pr.promo_code_id
+		JOIN invoices i ON i.id = pr.invoice_id AND i.deleted_at IS NULL AND i.status <> 'canceled'
+		WHERE pr.created_at >= ? AND pr.created_at <= ?
+	`+scope+` GROUP BY pc.code ORDER BY amount DESC`, args...).Scan(&rep.DiscountCodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get discount codes: %w", err)
+	}
 
 	// Get expenses
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
 	r.db.WithContext(ctx).Raw(`
 		SELECT COALESCE(SUM(amount), 0)
 		FROM expenses
 		WHERE date >= ? AND date <= ? AND deleted_at IS NULL
-	`, startDate, endDate).Scan(&rep.TotalExpenses)
+	`+scope, args...).Scan(&rep.TotalExpenses)
 
 	rep.NetProfit = rep.TotalRevenue - rep.TotalExpenses
 
@@ -133,6 +178,7 @@ func (r *reportRepository) GetFinancialReport(ctx context.Context, startDate, en
 	}
 
 	var invoiceStats InvoiceStats
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
 	r.db.WithContext(ctx).Raw(`
 		SELECT
 			SUM(CASE WHEN status = 'paid' THEN 1 ELSE 0 END) as paid_invoices,
@@ -141,16 +187,21 @@ func (r *reportRepository) GetFinancialReport(ctx context.Context, startDate, en
 			COUNT(*) as total_invoices
 		FROM invoices
 		WHERE created_at >= ? AND created_at <= ? AND deleted_at IS NULL
-	`, startDate, endDate).Scan(&invoiceStats)
+	`+scope, args...).Scan(&invoiceStats)
 
 	rep.PaidInvoices = invoiceStats.PaidInvoices
 	rep.PendingInvoices = invoiceStats.PendingInvoices
 	rep.OverdueInvoices = invoiceStats.OverdueInvoices
 	rep.TotalInvoices = invoiceStats.TotalInvoices
 
+	monthly, err := r.monthlyRevenue(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	rep.MonthlyRevenue = monthly
+
 	// Initialize empty slices
 	rep.PaymentMethodStats = make([]report.PaymentMethodStat, 0)
-	rep.MonthlyRevenue = make([]report.MonthlyRevenueStat, 0)
 	rep.ExpenseCategories = make([]report.ExpenseCategoryStat, 0)
 
 	return rep, nil
@@ -172,6 +223,7 @@ func (r *reportRepository) GetStudentReport(ctx context.Context, startDate, endD
 	}
 
 	var counts StudentCounts
+	scope, args := instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
 	r.db.WithContext(ctx).Raw(`
 		SELECT
 			COUNT(*) as total_students,
@@ -180,7 +232,7 @@ func (r *reportRepository) GetStudentReport(ctx context.Context, startDate, endD
 			SUM(CASE WHEN enrollment_date >= ? AND enrollment_date <= ? THEN 1 ELSE 0 END) as new_enrollments
 		FROM students
 		WHERE deleted_at IS NULL
-	`, startDate, endDate).Scan(&counts)
+	`+scope, args...).Scan(&counts)
 
 	rep.TotalStudents = counts.TotalStudents
 	rep.ActiveStudents = counts.ActiveStudents
@@ -192,6 +244,91 @@ func (r *reportRepository) GetStudentReport(ctx context.Context, startDate, endD
 	rep.PackageDistribution = make([]report.PackageDistStat, 0)
 	rep.GenderDistribution = make([]report.GenderDistStat, 0)
 
+	// Course and package distribution come from enrollments started in the
+	// period. Package enrollments count towards each course in the package.
+	enrolled := "e.deleted_at IS NULL AND e.status <> 'cancelled' AND e.start_date >= ? AND e.start_date <= ?"
+	scope, args = instituteSQL(ctx, "e.institute_id = ?", []interface{}{startDate, endDate})
+	enrolled += scope
+
+	var enrolledStudents int
+	r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(DISTINCT e.student_id)
+		FROM enrollments e
+		WHERE `+enrolled, args...).Scan(&enrolledStudents)
+
+	r.db.WithContext(ctx).Raw(`
+		SELECT c.id::text as course_id, c.name as course_name, COUNT(DISTINCT ec.student_id) as count
+		FROM (
+			SELECT e.student_id, e.course_id
+			FROM enrollments e
+			WHERE e.course_id IS NOT NULL AND `+enrolled+`
+			UNION
+			SELECT e.student_id, pc.course_id
+			FROM enrollments e
+			INNER JOIN package_courses pc ON pc.package_id = e.package_id
+			WHERE `+enrolled+`
+		) ec
+		INNER JOIN courses c ON c.id = ec.course_id
+		GROUP BY c.id, c.name
+		ORDER BY count DESC, c.name
+	`, append(append([]interface{}{}, args...), args...)...).Scan(&rep.CourseDistribution)
+
+	r.db.WithContext(ctx).Raw(`
+		SELECT p.id::text as package_id, p.name as package_name, COUNT(DISTINCT e.student_id) as count
+		FROM enrollments e
+		INNER JOIN packages p ON p.id = e.package_id
+		WHERE `+enrolled+`
+		GROUP BY p.id, p.name
+		ORDER BY count DESC, p.name
+	`, args...).Scan(&rep.PackageDistribution)
+
+	if enrolledStudents > 0 {
+		for i := range rep.CourseDistribution {
+			rep.CourseDistribution[i].Percentage = float64(rep.CourseDistribution[i].Count) / float64(enrolledStudents) * 100
+		}
+		for i := range rep.PackageDistribution {
+			rep.PackageDistribution[i].Percentage = float64(rep.PackageDistribution[i].Count) / float64(enrolledStudents) * 100
+		}
+	}
+
+	// License test pass rates over tests taken in the period.
+	rep.CoursePassRates = make([]report.PassRateStat, 0)
+	rep.InstructorPassRates = make([]report.PassRateStat, 0)
+
+	decided := "t.deleted_at IS NULL AND t.result <> 'pending' AND t.scheduled_date >= ? AND t.scheduled_date <= ?"
+	scope, args = instituteSQL(ctx, "t.institute_id = ?", []interface{}{startDate, endDate})
+	decided += scope
+
+	r.db.WithContext(ctx).Raw(`
+		SELECT c.id::text as id, c.name as name,
+			COUNT(*) as attempts,
+			SUM(CASE WHEN t.result = 'passed' THEN 1 ELSE 0 END) as passed
+		FROM license_tests t
+		INNER JOIN courses c ON c.id = t.course_id
+		WHERE `+decided+`
+		GROUP BY c.id, c.name
+		ORDER BY c.name
+	`, args...).Scan(&rep.CoursePassRates)
+
+	r.db.WithContext(ctx).Raw(`
+		SELECT e.id::text as id, e.first_name || ' ' || e.last_name as name,
+			COUNT(*) as attempts,
+			SUM(CASE WHEN t.result = 'passed' THEN 1 ELSE 0 END) as passed
+		FROM license_tests t
+		INNER JOIN employees e ON e.id = t.instructor_id
+		WHERE `+decided+`
+		GROUP BY e.id, e.first_name, e.last_name
+		ORDER BY name
+	`, args...).Scan(&rep.InstructorPassRates)
+
+	for _, stats := range [][]report.PassRateStat{rep.CoursePassRates, rep.InstructorPassRates} {
+		for i := range stats {
+			if stats[i].Attempts > 0 {
+				stats[i].PassRate = float64(stats[i].Passed) / float64(stats[i].Attempts) * 100
+			}
+		}
+	}
+
 	return rep, nil
 }
 
@@ -204,24 +341,25 @@ func (r *reportRepository) GetRevenueReport(ctx context.Context, startDate, endD
 
 	// Get total revenue
 	type RevenueStats struct {
-		TotalRevenue  float64
+		TotalRevenue  money.Amount
 		TotalPayments int
 	}
 
 	var stats RevenueStats
+	scope, args := instituteSQL(ctx, invoiceInInstitute, []interface{}{startDate, endDate})
 	r.db.WithContext(ctx).Raw(`
 		SELECT
 			COALESCE(SUM(amount), 0) as total_revenue,
 			COUNT(*) as total_payments
 		FROM payments
 		WHERE payment_date >= ? AND payment_date <= ? AND deleted_at IS NULL
-	`, startDate, endDate).Scan(&stats)
+	`+scope, args...).Scan(&stats)
 
 	rep.TotalRevenue = stats.TotalRevenue
 	rep.TotalPayments = stats.TotalPayments
 
 	if rep.TotalPayments > 0 {
-		rep.AveragePayment = rep.TotalRevenue / float64(rep.TotalPayments)
+		rep.AveragePayment = rep.TotalRevenue.MulDiv(1, int64(rep.TotalPayments))
 	}
 
 	// Initialize empty slices
@@ -241,34 +379,142 @@ func (r *reportRepository) GetExpenseReport(ctx context.Context, startDate, endD
 
 	// Get total expenses
 	type ExpenseStats struct {
-		TotalExpenses      float64
-		TotalTransactions  int
+		TotalExpenses     money.Amount
+		TotalTransactions int
 	}
 
 	var stats ExpenseStats
+	scope, args := instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
 	r.db.WithContext(ctx).Raw(`
 		SELECT
 			COALESCE(SUM(amount), 0) as total_expenses,
 			COUNT(*) as total_transactions
 		FROM expenses
 		WHERE date >= ? AND date <= ? AND deleted_at IS NULL
-	`, startDate, endDate).Scan(&stats)
+	`+scope, args...).Scan(&stats)
 
 	rep.TotalExpenses = stats.TotalExpenses
 	rep.TotalTransactions = stats.TotalTransactions
 
 	if rep.TotalTransactions > 0 {
-		rep.AverageExpense = rep.TotalExpenses / float64(rep.TotalTransactions)
+		rep.AverageExpense = rep.TotalExpenses.MulDiv(1, int64(rep.TotalTransactions))
 	}
 
+	monthly, err := r.monthlyExpenses(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	rep.MonthlyExpenses = monthly
+
 	// Initialize empty slices
 	rep.CategoryBreakdown = make([]report.ExpenseCategoryStat, 0)
-	rep.MonthlyExpenses = make([]report.MonthlyExpenseStat, 0)
 	rep.TopExpenses = make([]report.TopExpenseStat, 0)
 
 	return rep, nil
 }
 
+// monthlyRevenue breaks the period down by month, in BS when the request
+// asked for it.
+func (r *reportRepository) monthlyRevenue(ctx context.Context, startDate, endDate time.Time) ([]report.MonthlyRevenueStat, error) {
+	months := newMonthRange(ctx, startDate, endDate)
+	stats := make([]report.MonthlyRevenueStat, len(months.months))
+	for i, m := range months.months {
+		stats[i] = report.MonthlyRevenueStat{Month: m.Name, Year: m.Year}
+	}
+
+	scope, args := instituteSQL(ctx, invoiceInInstitute, []interface{}{startDate, endDate})
+	payments, err := dailyTotals(ctx, r.db, `
+		SELECT DATE(payment_date) as day, COALESCE(SUM(amount), 0) as amount, COUNT(*) as count
+		FROM payments
+		WHERE payment_date >= ? AND payment_date <= ? AND deleted_at IS NULL
+	`+scope+` GROUP BY DATE(payment_date)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily revenue: %w", err)
+	}
+	for _, d := range payments {
+		if i, ok := months.find(d.Day); ok {
+			stats[i].Revenue += d.Amount
+		}
+	}
+
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	reversals, err := dailyTotals(ctx, r.db, `
+		SELECT DATE(approved_at) as day, COALESCE(SUM(amount), 0) as amount, COUNT(*) as count
+		FROM payment_reversals
+		WHERE status = 'approved' AND approved_at >= ? AND approved_at <= ?
+	`+scope+` GROUP BY DATE(approved_at)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily reversals: %w", err)
+	}
+	for _, d := range reversals {
+		if i, ok := months.find(d.Day); ok {
+			stats[i].Revenue -= d.Amount
+		}
+	}
+
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	expenses, err := dailyTotals(ctx, r.db, `
+		SELECT DATE(date) as day, COALESCE(SUM(amount), 0) as amount, COUNT(*) as count
+		FROM expenses
+		WHERE date >= ? AND date <= ? AND deleted_at IS NULL
+	`+scope+` GROUP BY DATE(date)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily expenses: %w", err)
+	}
+	for _, d := range expenses {
+		if i, ok := months.find(d.Day); ok {
+			stats[i].Expenses += d.Amount
+		}
+	}
+
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	invoices, err := dailyTotals(ctx, r.db, `
+		SELECT DATE(created_at) as day, 0 as amount, COUNT(*) as count
+		FROM invoices
+		WHERE created_at >= ? AND created_at <= ? AND deleted_at IS NULL
+	`+scope+` GROUP BY DATE(created_at)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily invoices: %w", err)
+	}
+	for _, d := range invoices {
+		if i, ok := months.find(d.Day); ok {
+			stats[i].Invoices += d.Count
+		}
+	}
+
+	for i := range stats {
+		stats[i].NetProfit = stats[i].Revenue - stats[i].Expenses
+	}
+	return stats, nil
+}
+
+// monthlyExpenses breaks the period's expenses down by month, in BS when the
+// request asked for it.
+func (r *reportRepository) monthlyExpenses(ctx context.Context, startDate, endDate time.Time) ([]report.MonthlyExpenseStat, error) {
+	months := newMonthRange(ctx, startDate, endDate)
+	stats := make([]report.MonthlyExpenseStat, len(months.months))
+	for i, m := range months.months {
+		stats[i] = report.MonthlyExpenseStat{Month: m.Name, Year: m.Year}
+	}
+
+	scope, args := instituteSQL(ctx, "institute_id = ?", []interface{}{startDate, endDate})
+	expenses, err := dailyTotals(ctx, r.db, `
+		SELECT DATE(date) as day, COALESCE(SUM(amount), 0) as amount, COUNT(*) as count
+		FROM expenses
+		WHERE date >= ? AND date <= ? AND deleted_at IS NULL
+	`+scope+` GROUP BY DATE(date)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily expenses: %w", err)
+	}
+	for _, d := range expenses {
+		if i, ok := months.find(d.Day); ok {
+			stats[i].Expenses += d.Amount
+			stats[i].Count += d.Count
+		}
+	}
+	return stats, nil
+}
+
 // GetDashboardStats retrieves dashboard statistics including attendance by vehicle type
 func (r *reportRepository) GetDashboardStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
@@ -284,6 +530,7 @@ func (r *reportRepository) GetDashboardStats(ctx context.Context) (map[string]in
 	}
 
 	var vehicleAttendance []VehicleAttendance
+	scope, args := instituteSQL(ctx, "s.institute_id = ?", []interface{}{today})
 	err := r.db.WithContext(ctx).Raw(`
 		SELECT
 			c.name as course_name,
@@ -295,10 +542,10 @@ func (r *reportRepository) GetDashboardStats(ctx context.Context) (map[string]in
 		INNER JOIN students s ON a.student_id = s.id
 		INNER JOIN student_courses sc ON s.id = sc.student_id
 		INNER JOIN courses c ON sc.course_id = c.id
-		WHERE a.date = ? AND a.deleted_at IS NULL
+		WHERE a.date = ? AND a.deleted_at IS NULL`+scope+`
 		GROUP BY c.name
 		ORDER BY c.name
-	`, today).Scan(&vehicleAttendance).Error
+	`, args...).Scan(&vehicleAttendance).Error
 
 	if err == nil {
 		stats["vehicle_attendance"] = vehicleAttendance
@@ -306,20 +553,22 @@ func (r *reportRepository) GetDashboardStats(ctx context.Context) (map[string]in
 
 	// Get today's new students
 	var newStudentsToday int
+	scope, args = instituteSQL(ctx, "institute_id = ?", []interface{}{today})
 	r.db.WithContext(ctx).Raw(`
 		SELECT COUNT(*)
 		FROM students
 		WHERE DATE(enrolled_at) = ? AND deleted_at IS NULL
-	`, today).Scan(&newStudentsToday)
+	`+scope, args...).Scan(&newStudentsToday)
 	stats["new_students_today"] = newStudentsToday
 
 	// Get today's money collection
-	var moneyCollectionToday float64
+	var moneyCollectionToday money.Amount
+	scope, args = instituteSQL(ctx, invoiceInInstitute, []interface{}{today})
 	r.db.WithContext(ctx).Raw(`
 		SELECT COALESCE(SUM(amount), 0)
 		FROM payments
 		WHERE DATE(payment_date) = ? AND deleted_at IS NULL
-	`, today).Scan(&moneyCollectionToday)
+	`+scope, args...).Scan(&moneyCollectionToday)
 	stats["money_collection_today"] = moneyCollectionToday
 
 	return stats, nil
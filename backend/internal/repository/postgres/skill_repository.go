@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chalak/backend/internal/domain/skill"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SkillRepository struct {
+	db *gorm.DB
+}
+
+func NewSkillRepository(db *gorm.DB) skill.Repository {
+	return &SkillRepository{db: db}
+}
+
+func (r *SkillRepository) Create(ctx context.Context, s *skill.Skill) error {
+	if err := requireActiveInstitute(ctx, r.db, &s.InstituteID); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(s).Error; err != nil {
+		return fmt.Errorf("failed to create skill: %w", err)
+	}
+	return nil
+}
+
+func (r *SkillRepository) FindByID(ctx context.Context, id uuid.UUID) (*skill.Skill, error) {
+	var s skill.Skill
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&s).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("skill not found")
+		}
+		return nil, fmt.Errorf("failed to find skill: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SkillRepository) Update(ctx context.Context, s *skill.Skill) error {
+	if err := r.db.WithContext(ctx).Save(s).Error; err != nil {
+		return fmt.Errorf("failed to update skill: %w", err)
+	}
+	return nil
+}
+
+func (r *SkillRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&skill.Skill{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).
+		Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete skill: %w", err)
+	}
+	return nil
+}
+
+func (r *SkillRepository) ListByCourse(ctx context.Context, courseID uuid.UUID, instituteID *uuid.UUID, activeOnly bool) ([]*skill.Skill, error) {
+	var skills []*skill.Skill
+
+	query := r.db.WithContext(ctx).Where("course_id = ? AND deleted_at IS NULL", courseID)
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+	if instituteID != nil {
+		query = query.Where("institute_id = ?", *instituteID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = true")
+	}
+
+	if err := query.Order("sort_order ASC, name ASC").Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+	return skills, nil
+}
+
+func (r *SkillRepository) SaveScores(ctx context.Context, scores []*skill.Score) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "attendance_id"}, {Name: "skill_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "comment", "graded_by", "updated_at"}),
+	}).Create(&scores).Error; err != nil {
+		return fmt.Errorf("failed to save skill scores: %w", err)
+	}
+	return nil
+}
+
+func (r *SkillRepository) ListScoresByAttendance(ctx context.Context, attendanceID uuid.UUID) ([]*skill.Score, error) {
+	var scores []*skill.Score
+
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), studentInInstitute)
+	if err := query.Where("attendance_id = ?", attendanceID).Order("created_at ASC").Find(&scores).Error; err != nil {
+		return nil, fmt.Errorf("failed to list skill scores: %w", err)
+	}
+	return scores, nil
+}
+
+func (r *SkillRepository) ListScoreHistory(ctx context.Context, studentID, courseID uuid.UUID) ([]skill.ScoreEntry, error) {
+	var entries []skill.ScoreEntry
+
+	query := r.db.WithContext(ctx).Table("skill_scores ss").
+		Select("ss.skill_id, ss.attendance_id, a.date, ss.score").
+		Joins("JOIN attendances a ON a.id = ss.attendance_id AND a.deleted_at IS NULL").
+		Joins("JOIN course_skills cs ON cs.id = ss.skill_id").
+		Where("ss.student_id = ? AND cs.course_id = ?", studentID, courseID)
+	query = scopeToInstitute(ctx, query, "ss."+studentInInstitute)
+
+	if err := query.Order("a.date ASC, ss.created_at ASC").Scan(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list skill score history: %w", err)
+	}
+	return entries, nil
+}
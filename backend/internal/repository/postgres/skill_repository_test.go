@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/chalak/backend/internal/domain/skill"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillRepositoryKeepsChecklistsPerInstitute(t *testing.T) {
+	db := testDB(t, instituteTable, attendanceTables, migration(t, "000009_create_skill_tables.up.sql"))
+	repo := NewSkillRepository(db)
+	own, other, courseID := uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO institutes (id, name, code) VALUES (?, 'Own', 'OWN'), (?, 'Other', 'OTH')", own, other).Error)
+	require.NoError(t, db.Exec("INSERT INTO courses (id, name, duration) VALUES (?, 'Car Driving', 30)", courseID).Error)
+
+	platform := context.Background()
+	ctx := tenant.WithInstituteID(platform, own)
+	newSkill := func(instituteID uuid.UUID, name string) *skill.Skill {
+		return &skill.Skill{ID: uuid.New(), InstituteID: instituteID, CourseID: courseID, Name: name, MaxScore: 5, PassScore: 4, IsActive: true}
+	}
+
+	mine := newSkill(uuid.Nil, "Hill start")
+	require.NoError(t, repo.Create(ctx, mine))
+	assert.Equal(t, own, mine.InstituteID, "filled in from the tenant")
+
+	theirs := newSkill(other, "Reverse parking")
+	err := repo.Create(ctx, theirs)
+	assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+	require.NoError(t, repo.Create(platform, theirs))
+
+	_, err = repo.FindByID(ctx, theirs.ID)
+	assert.Error(t, err)
+
+	require.NoError(t, repo.Delete(ctx, theirs.ID))
+	_, err = repo.FindByID(platform, theirs.ID)
+	assert.NoError(t, err, "deleting another institute's skill is a no-op")
+
+	list, err := repo.ListByCourse(ctx, courseID, nil, false)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, mine.ID, list[0].ID)
+
+	list, err = repo.ListByCourse(platform, courseID, &other, true)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, theirs.ID, list[0].ID)
+}
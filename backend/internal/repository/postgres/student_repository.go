@@ -18,6 +18,10 @@ func NewStudentRepository(db *gorm.DB) student.Repository {
 }
 
 func (r *studentRepository) Create(ctx context.Context, s *student.Student) error {
+	if err := requireActiveInstitute(ctx, r.db, &s.InstituteID); err != nil {
+		return err
+	}
+
 	if err := r.db.WithContext(ctx).Create(s).Error; err != nil {
 		return fmt.Errorf("failed to create student: %w", err)
 	}
@@ -26,7 +30,8 @@ func (r *studentRepository) Create(ctx context.Context, s *student.Student) erro
 
 func (r *studentRepository) GetByID(ctx context.Context, id uuid.UUID) (*student.Student, error) {
 	var s student.Student
-	if err := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&s).Error; err != nil {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&s).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("student not found")
 		}
@@ -46,8 +51,21 @@ func (r *studentRepository) GetByEmail(ctx context.Context, email string) (*stud
 	return &s, nil
 }
 
+func (r *studentRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*student.Student, error) {
+	var s student.Student
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("user_id = ? AND deleted_at IS NULL", userID).First(&s).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("student not found")
+		}
+		return nil, fmt.Errorf("failed to get student: %w", err)
+	}
+	return &s, nil
+}
+
 func (r *studentRepository) Update(ctx context.Context, s *student.Student) error {
-	result := r.db.WithContext(ctx).Model(s).Where("id = ? AND deleted_at IS NULL", s.ID).Updates(s)
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(s), "institute_id = ?")
+	result := query.Where("id = ? AND deleted_at IS NULL", s.ID).Updates(s)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update student: %w", result.Error)
 	}
@@ -58,7 +76,8 @@ func (r *studentRepository) Update(ctx context.Context, s *student.Student) erro
 }
 
 func (r *studentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Model(&student.Student{}).Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP"))
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&student.Student{}), "institute_id = ?")
+	result := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP"))
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete student: %w", result.Error)
 	}
@@ -73,6 +92,7 @@ func (r *studentRepository) List(ctx context.Context, filter student.StudentFilt
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&student.Student{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
 
 	if filter.InstituteID != nil {
 		query = query.Where("institute_id = ?", *filter.InstituteID)
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/chalak/backend/pkg/tenant"
+	"gorm.io/gorm"
+)
+
+// Conditions that tie rows without their own institute_id column back to the
+// institute through their parent record. Each takes the institute id as its
+// only argument.
+const (
+	studentInInstitute = "student_id IN (SELECT id FROM students WHERE institute_id = ?)"
+	invoiceInInstitute = "invoice_id IN (SELECT id FROM invoices WHERE institute_id = ?)"
+	userInInstitute    = "user_id IN (SELECT id FROM users WHERE institute_id = ?)"
+
+	instructorInInstitute = "instructor_id IN (SELECT id FROM employees WHERE institute_id = ?)"
+)
+
+// scopeToInstitute adds cond to query when ctx is scoped to an institute.
+// cond must contain exactly one placeholder for the institute id.
+func scopeToInstitute(ctx context.Context, query *gorm.DB, cond string) *gorm.DB {
+	if id, ok := tenant.InstituteID(ctx); ok {
+		return query.Where(cond, id)
+	}
+	return query
+}
+
+// instituteSQL is the raw SQL counterpart of scopeToInstitute. It returns an
+// " AND cond" fragment and args extended with the institute id, or an empty
+// fragment and args unchanged for unscoped callers.
+func instituteSQL(ctx context.Context, cond string, args []interface{}) (string, []interface{}) {
+	if id, ok := tenant.InstituteID(ctx); ok {
+		return " AND " + cond, append(args, id)
+	}
+	return "", args
+}
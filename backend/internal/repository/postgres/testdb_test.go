@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// testDB connects to the database in TEST_DATABASE_URL and gives the test a
+// fresh schema, dropped when the test ends, with statements already run in
+// it. Tests that need a database are skipped when the variable is unset.
+func testDB(t *testing.T, statements ...string) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	admin := openTestDB(t, dsn)
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+	})
+
+	db := openTestDB(t, withSearchPath(dsn, schema))
+	for _, stmt := range statements {
+		require.NoError(t, db.Exec(stmt).Error, stmt)
+	}
+	return db
+}
+
+func openTestDB(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// withSearchPath points every connection made with dsn at schema. It accepts
+// both URL and key=value connection strings.
+func withSearchPath(dsn, schema string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		q := u.Query()
+		q.Set("search_path", schema)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return dsn + " search_path=" + schema
+}
+
+// migration returns the SQL of an up migration, by file name.
+func migration(t *testing.T, name string) string {
+	t.Helper()
+
+	sql, err := os.ReadFile(filepath.Join("..", "..", "..", "migrations", name))
+	require.NoError(t, err)
+	return string(sql)
+}
@@ -18,6 +18,12 @@ func NewUserRepository(db *gorm.DB) user.Repository {
 }
 
 func (r *UserRepository) Create(ctx context.Context, usr *user.User) error {
+	if usr.InstituteID != nil {
+		if err := requireActiveInstitute(ctx, r.db, usr.InstituteID); err != nil {
+			return err
+		}
+	}
+
 	if err := r.db.WithContext(ctx).Create(usr).Error; err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
@@ -47,6 +53,12 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.U
 }
 
 func (r *UserRepository) Update(ctx context.Context, usr *user.User) error {
+	if usr.InstituteID != nil {
+		if err := requireActiveInstitute(ctx, r.db, usr.InstituteID); err != nil {
+			return err
+		}
+	}
+
 	if err := r.db.WithContext(ctx).Save(usr).Error; err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -65,6 +77,11 @@ func (r *UserRepository) List(ctx context.Context, filter user.UserFilter) ([]*u
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&user.User{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if filter.InstituteID != nil {
+		query = query.Where("institute_id = ?", *filter.InstituteID)
+	}
 
 	if filter.Role != nil {
 		query = query.Where("role = ?", *filter.Role)
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/vehicle"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VehicleRepository struct {
+	db *gorm.DB
+}
+
+func NewVehicleRepository(db *gorm.DB) vehicle.Repository {
+	return &VehicleRepository{db: db}
+}
+
+func (r *VehicleRepository) Create(ctx context.Context, v *vehicle.Vehicle) error {
+	if err := requireActiveInstitute(ctx, r.db, &v.InstituteID); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(v).Error; err != nil {
+		return fmt.Errorf("failed to create vehicle: %w", err)
+	}
+	return nil
+}
+
+func (r *VehicleRepository) FindByID(ctx context.Context, id uuid.UUID) (*vehicle.Vehicle, error) {
+	var v vehicle.Vehicle
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.Where("id = ? AND deleted_at IS NULL", id).First(&v).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("vehicle not found")
+		}
+		return nil, fmt.Errorf("failed to find vehicle: %w", err)
+	}
+	return &v, nil
+}
+
+func (r *VehicleRepository) FindByRegistrationNumber(ctx context.Context, instituteID uuid.UUID, registrationNumber string) (*vehicle.Vehicle, error) {
+	var v vehicle.Vehicle
+	if err := r.db.WithContext(ctx).
+		Where("institute_id = ? AND registration_number = ? AND deleted_at IS NULL", instituteID, registrationNumber).
+		First(&v).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("vehicle not found")
+		}
+		return nil, fmt.Errorf("failed to find vehicle: %w", err)
+	}
+	return &v, nil
+}
+
+func (r *VehicleRepository) Update(ctx context.Context, v *vehicle.Vehicle) error {
+	if err := r.db.WithContext(ctx).Save(v).Error; err != nil {
+		return fmt.Errorf("failed to update vehicle: %w", err)
+	}
+	return nil
+}
+
+func (r *VehicleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx).Model(&vehicle.Vehicle{}), "institute_id = ?")
+	if err := query.Where("id = ?", id).Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+		return fmt.Errorf("failed to delete vehicle: %w", err)
+	}
+	return nil
+}
+
+func (r *VehicleRepository) List(ctx context.Context, filter vehicle.VehicleFilter) ([]*vehicle.Vehicle, int64, error) {
+	var vehicles []*vehicle.Vehicle
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&vehicle.Vehicle{}).Where("deleted_at IS NULL")
+	query = scopeToInstitute(ctx, query, "institute_id = ?")
+
+	if filter.Type != nil {
+		query = query.Where("type = ?", *filter.Type)
+	}
+
+	if filter.LicenseCategory != nil {
+		query = query.Where("license_category = ?", *filter.LicenseCategory)
+	}
+
+	if filter.Transmission != nil {
+		query = query.Where("transmission = ?", *filter.Transmission)
+	}
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if filter.Search != nil && *filter.Search != "" {
+		search := "%" + *filter.Search + "%"
+		query = query.Where("registration_number ILIKE ? OR make ILIKE ? OR model ILIKE ?", search, search, search)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count vehicles: %w", err)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("registration_number ASC").Find(&vehicles).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list vehicles: %w", err)
+	}
+
+	return vehicles, total, nil
+}
+
+func (r *VehicleRepository) FindWithDocumentsExpiringBefore(ctx context.Context, before time.Time) ([]*vehicle.Vehicle, error) {
+	var vehicles []*vehicle.Vehicle
+
+	query := scopeToInstitute(ctx, r.db.WithContext(ctx), "institute_id = ?")
+	if err := query.
+		Where("deleted_at IS NULL AND status <> ?", vehicle.StatusRetired).
+		Where("bluebook_expiry < ? OR insurance_expiry < ? OR pollution_expiry < ?", before, before, before).
+		Order("institute_id, registration_number").
+		Find(&vehicles).Error; err != nil {
+		return nil, fmt.Errorf("failed to find vehicles with expiring documents: %w", err)
+	}
+
+	return vehicles, nil
+}
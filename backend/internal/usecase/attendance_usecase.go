@@ -3,32 +3,61 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/pkg/bs"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
 	"github.com/google/uuid"
 )
 
 type AttendanceUseCase struct {
-	repo   attendance.Repository
-	logger logger.Logger
+	repo        attendance.Repository
+	sessionRepo lesson.Repository
+	logger      logger.Logger
 }
 
-func NewAttendanceUseCase(repo attendance.Repository, logger logger.Logger) *AttendanceUseCase {
+func NewAttendanceUseCase(repo attendance.Repository, sessionRepo lesson.Repository, logger logger.Logger) *AttendanceUseCase {
 	return &AttendanceUseCase{
-		repo:   repo,
-		logger: logger,
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		logger:      logger,
 	}
 }
 
-func (uc *AttendanceUseCase) MarkAttendance(ctx context.Context, req *attendance.MarkAttendanceRequest, markedBy uuid.UUID) (*attendance.Attendance, error) {
+// MarkAttendance records the student's attendance for the class, on the
+// day the class takes place. Marking the same student again updates the
+// existing record instead of adding another; the returned bool is true only
+// when a record was created.
+func (uc *AttendanceUseCase) MarkAttendance(ctx context.Context, req *attendance.MarkAttendanceRequest, markedBy uuid.UUID) (*attendance.Attendance, bool, error) {
+	session, err := uc.sessionRepo.FindByID(ctx, req.ClassID)
+	if err != nil {
+		return nil, false, apperrors.NotFound("class not found")
+	}
+	if session.Status == lesson.StatusCancelled {
+		return nil, false, apperrors.BadRequest("class has been cancelled")
+	}
+	date, err := checkClassDate(session, req.Date)
+	if err != nil {
+		return nil, false, err
+	}
+
+	onRoster, err := uc.sessionRepo.HasStudent(ctx, session.ID, req.StudentID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check class roster: %w", err)
+	}
+	if !onRoster {
+		return nil, false, apperrors.BadRequest("student is not on the roster of this class")
+	}
+
 	att := &attendance.Attendance{
 		ID:        uuid.New(),
 		StudentID: req.StudentID,
 		ClassID:   req.ClassID,
-		Date:      req.Date,
+		Date:      date,
 		Status:    req.Status,
 		Notes:     req.Notes,
 		MarkedBy:  markedBy,
@@ -41,21 +70,131 @@ func (uc *AttendanceUseCase) MarkAttendance(ctx context.Context, req *attendance
 		att.CheckInAt = &now
 	}
 
-	if err := uc.repo.Create(ctx, att); err != nil {
+	created, err := uc.repo.Upsert(ctx, att)
+	if err != nil {
 		uc.logger.Error(ctx, "failed to mark attendance", err, map[string]interface{}{
 			"student_id": req.StudentID,
 			"class_id":   req.ClassID,
 		})
-		return nil, fmt.Errorf("failed to mark attendance: %w", err)
+		return nil, false, fmt.Errorf("failed to mark attendance: %w", err)
 	}
 
 	uc.logger.Info(ctx, "attendance marked", map[string]interface{}{
 		"attendance_id": att.ID,
 		"student_id":    att.StudentID,
 		"status":        att.Status,
+		"created":       created,
 	})
 
-	return att, nil
+	return att, created, nil
+}
+
+// MarkBulk records a class roll call in one go, for the day the class takes
+// place. Students already marked are updated as MarkAttendance does, so a
+// roll call can be submitted again. Every entry is checked before anything
+// is saved; if any entry is invalid nothing is written and the per-row
+// results say what is wrong.
+func (uc *AttendanceUseCase) MarkBulk(ctx context.Context, req *attendance.BulkMarkRequest, markedBy uuid.UUID) ([]attendance.BulkResult, error) {
+	session, err := uc.sessionRepo.FindByID(ctx, req.ClassID)
+	if err != nil {
+		return nil, apperrors.NotFound("class not found")
+	}
+	if session.Status == lesson.StatusCancelled {
+		return nil, apperrors.BadRequest("class has been cancelled")
+	}
+	date, err := checkClassDate(session, req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	onRoster := make(map[uuid.UUID]bool, len(session.StudentIDs))
+	for _, id := range session.StudentIDs {
+		onRoster[id] = true
+	}
+
+	now := time.Now().UTC()
+	results := make([]attendance.BulkResult, len(req.Entries))
+	records := make([]*attendance.Attendance, 0, len(req.Entries))
+	seen := make(map[uuid.UUID]bool, len(req.Entries))
+	invalid := 0
+
+	for i, entry := range req.Entries {
+		results[i] = attendance.BulkResult{Row: i, StudentID: entry.StudentID}
+
+		switch {
+		case seen[entry.StudentID]:
+			results[i].Error = "student appears more than once"
+		case !onRoster[entry.StudentID]:
+			results[i].Error = "student is not on the roster of this class"
+		}
+		seen[entry.StudentID] = true
+
+		if results[i].Error != "" {
+			invalid++
+			continue
+		}
+
+		att := &attendance.Attendance{
+			ID:        uuid.New(),
+			StudentID: entry.StudentID,
+			ClassID:   req.ClassID,
+			Date:      date,
+			Status:    entry.Status,
+			Notes:     entry.Notes,
+			MarkedBy:  markedBy,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if entry.Status == attendance.StatusPresent || entry.Status == attendance.StatusLate {
+			checkIn := now
+			att.CheckInAt = &checkIn
+		}
+
+		results[i].Attendance = att
+		records = append(records, att)
+	}
+
+	if invalid > 0 {
+		for i := range results {
+			results[i].Attendance = nil
+		}
+		return nil, apperrors.BadRequest(fmt.Sprintf("%d of %d entries are invalid; no attendance was saved", invalid, len(req.Entries))).
+			WithDetails(map[string]interface{}{"results": results})
+	}
+
+	if err := uc.repo.UpsertBatch(ctx, records); err != nil {
+		uc.logger.Error(ctx, "failed to mark bulk attendance", err, map[string]interface{}{
+			"class_id": req.ClassID,
+			"entries":  len(records),
+		})
+		return nil, fmt.Errorf("failed to mark bulk attendance: %w", err)
+	}
+
+	uc.logger.Info(ctx, "bulk attendance marked", map[string]interface{}{
+		"class_id": req.ClassID,
+		"entries":  len(records),
+	})
+
+	return results, nil
+}
+
+// checkClassDate returns the day the class takes place on, refusing a date
+// that falls on another day.
+func checkClassDate(session *lesson.Session, date time.Time) (time.Time, error) {
+	classDate := sessionDate(session)
+	if date.Format("2006-01-02") != classDate.Format("2006-01-02") {
+		return time.Time{}, apperrors.BadRequest("date does not match the date of the class").WithDetails(map[string]interface{}{
+			"class_date": classDate.Format("2006-01-02"),
+		})
+	}
+	return classDate, nil
+}
+
+// sessionDate is the day in Nepal a lesson takes place on, which its
+// attendance is recorded under.
+func sessionDate(session *lesson.Session) time.Time {
+	start := session.StartTime.In(bs.Kathmandu)
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
 }
 
 func (uc *AttendanceUseCase) GetByID(ctx context.Context, id uuid.UUID) (*attendance.Attendance, error) {
@@ -66,7 +205,7 @@ func (uc *AttendanceUseCase) GetByID(ctx context.Context, id uuid.UUID) (*attend
 	return att, nil
 }
 
-func (uc *AttendanceUseCase) Update(ctx context.Context, id uuid.UUID, req *attendance.MarkAttendanceRequest) (*attendance.Attendance, error) {
+func (uc *AttendanceUseCase) Update(ctx context.Context, id uuid.UUID, req *attendance.MarkAttendanceRequest, updatedBy uuid.UUID) (*attendance.Attendance, error) {
 	att, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, apperrors.NotFound("attendance not found")
@@ -74,6 +213,7 @@ func (uc *AttendanceUseCase) Update(ctx context.Context, id uuid.UUID, req *atte
 
 	att.Status = req.Status
 	att.Notes = req.Notes
+	att.MarkedBy = updatedBy
 	att.UpdatedAt = time.Now().UTC()
 
 	if req.Status == attendance.StatusPresent || req.Status == attendance.StatusLate {
@@ -97,6 +237,96 @@ func (uc *AttendanceUseCase) Update(ctx context.Context, id uuid.UUID, req *atte
 	return att, nil
 }
 
+// CheckOut ends a checked-in student's lesson and records how long it
+// lasted. The duration counts towards the student's logged course hours.
+func (uc *AttendanceUseCase) CheckOut(ctx context.Context, id uuid.UUID, req *attendance.CheckOutRequest) (*attendance.Attendance, error) {
+	att, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("attendance not found")
+	}
+
+	if att.CheckInAt == nil {
+		return nil, apperrors.BadRequest("student has not been checked in")
+	}
+	if att.CheckOutAt != nil {
+		return nil, apperrors.Conflict("student has already been checked out")
+	}
+
+	checkOut := time.Now().UTC()
+	if req.CheckOutAt != nil {
+		checkOut = req.CheckOutAt.UTC()
+	}
+	if !checkOut.After(*att.CheckInAt) {
+		return nil, apperrors.BadRequest("check-out must be after check-in")
+	}
+	if checkOut.After(time.Now().Add(time.Minute)) {
+		return nil, apperrors.BadRequest("check-out cannot be in the future")
+	}
+
+	minutes := int(math.Round(checkOut.Sub(*att.CheckInAt).Minutes()))
+	att.CheckOutAt = &checkOut
+	att.DurationMinutes = &minutes
+	if req.Notes != nil {
+		att.Notes = *req.Notes
+	}
+	att.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, att); err != nil {
+		uc.logger.Error(ctx, "failed to check out attendance", err, map[string]interface{}{
+			"attendance_id": id,
+		})
+		return nil, fmt.Errorf("failed to check out attendance: %w", err)
+	}
+
+	uc.logger.Info(ctx, "attendance checked out", map[string]interface{}{
+		"attendance_id":    att.ID,
+		"duration_minutes": minutes,
+	})
+
+	return att, nil
+}
+
+// ListHours reports each student's logged hours against what their courses
+// require.
+func (uc *AttendanceUseCase) ListHours(ctx context.Context, filter attendance.HoursFilter) ([]*attendance.CourseHours, error) {
+	rows, err := uc.repo.ListCourseHours(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list course hours", err, nil)
+		return nil, fmt.Errorf("failed to list course hours: %w", err)
+	}
+
+	hours := make([]*attendance.CourseHours, 0, len(rows))
+	for _, h := range rows {
+		h.LoggedHours = math.Round(h.LoggedHours*100) / 100
+		h.RemainingHours = math.Max(0, math.Round((h.RequiredHours-h.LoggedHours)*100)/100)
+		h.Completed = h.LoggedHours >= h.RequiredHours
+
+		if filter.Completed != nil && h.Completed != *filter.Completed {
+			continue
+		}
+		hours = append(hours, h)
+	}
+
+	return hours, nil
+}
+
+// GetHistory lists the record's status changes, oldest first.
+func (uc *AttendanceUseCase) GetHistory(ctx context.Context, id uuid.UUID) ([]*attendance.StatusChange, error) {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return nil, apperrors.NotFound("attendance not found")
+	}
+
+	history, err := uc.repo.ListHistory(ctx, id)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list attendance history", err, map[string]interface{}{
+			"attendance_id": id,
+		})
+		return nil, fmt.Errorf("failed to list attendance history: %w", err)
+	}
+
+	return history, nil
+}
+
 func (uc *AttendanceUseCase) Delete(ctx context.Context, id uuid.UUID) error {
 	if err := uc.repo.Delete(ctx, id); err != nil {
 		uc.logger.Error(ctx, "failed to delete attendance", err, map[string]interface{}{
@@ -0,0 +1,414 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/pkg/bs"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memAttendanceRepo struct {
+	attendance.Repository
+	records map[uuid.UUID]*attendance.Attendance
+}
+
+func newMemAttendanceRepo() *memAttendanceRepo {
+	return &memAttendanceRepo{records: map[uuid.UUID]*attendance.Attendance{}}
+}
+
+func (r *memAttendanceRepo) FindByID(ctx context.Context, id uuid.UUID) (*attendance.Attendance, error) {
+	att, ok := r.records[id]
+	if !ok {
+		return nil, errors.New("attendance not found")
+	}
+	copied := *att
+	return &copied, nil
+}
+
+func (r *memAttendanceRepo) List(ctx context.Context, filter attendance.AttendanceFilter) ([]*attendance.Attendance, int64, error) {
+	var list []*attendance.Attendance
+	for _, att := range r.records {
+		if filter.ClassID != nil && att.ClassID != *filter.ClassID {
+			continue
+		}
+		if filter.StudentID != nil && att.StudentID != *filter.StudentID {
+			continue
+		}
+		if filter.DateFrom != nil && att.Date.Before(*filter.DateFrom) {
+			continue
+		}
+		if filter.DateTo != nil && att.Date.After(*filter.DateTo) {
+			continue
+		}
+		list = append(list, att)
+	}
+	return list, int64(len(list)), nil
+}
+
+func (r *memAttendanceRepo) UpsertBatch(ctx context.Context, atts []*attendance.Attendance) error {
+	for _, att := range atts {
+		if _, err := r.Upsert(ctx, att); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memAttendanceRepo) Update(ctx context.Context, att *attendance.Attendance) error {
+	copied := *att
+	r.records[att.ID] = &copied
+	return nil
+}
+
+// Upsert keeps one record per student, class and date, like the database's
+// unique index.
+func (r *memAttendanceRepo) Upsert(ctx context.Context, att *attendance.Attendance) (bool, error) {
+	for _, existing := range r.records {
+		if existing.StudentID == att.StudentID && existing.ClassID == att.ClassID && existing.Date.Equal(att.Date) {
+			existing.Status = att.Status
+			existing.Notes = att.Notes
+			*att = *existing
+			return false, nil
+		}
+	}
+	copied := *att
+	r.records[att.ID] = &copied
+	return true, nil
+}
+
+func TestCheckOutRecordsDuration(t *testing.T) {
+	checkIn := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		checkIn     *time.Time
+		checkedOut  bool
+		checkOut    time.Time
+		wantMinutes int
+		wantCode    int
+	}{
+		{name: "whole minutes", checkIn: &checkIn, checkOut: checkIn.Add(45 * time.Minute), wantMinutes: 45},
+		{name: "rounds to nearest minute", checkIn: &checkIn, checkOut: checkIn.Add(59*time.Minute + 31*time.Second), wantMinutes: 60},
+		{name: "rounds down under half a minute", checkIn: &checkIn, checkOut: checkIn.Add(90*time.Minute + 29*time.Second), wantMinutes: 90},
+		{name: "not checked in", checkOut: checkIn.Add(time.Hour), wantCode: http.StatusBadRequest},
+		{name: "already checked out", checkIn: &checkIn, checkedOut: true, checkOut: checkIn.Add(time.Hour), wantCode: http.StatusConflict},
+		{name: "before check-in", checkIn: &checkIn, checkOut: checkIn.Add(-time.Minute), wantCode: http.StatusBadRequest},
+		{name: "in the future", checkIn: &checkIn, checkOut: time.Now().Add(time.Hour), wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemAttendanceRepo()
+			att := &attendance.Attendance{ID: uuid.New(), Status: attendance.StatusPresent, CheckInAt: tt.checkIn}
+			if tt.checkedOut {
+				out := checkIn.Add(30 * time.Minute)
+				att.CheckOutAt = &out
+			}
+			repo.records[att.ID] = att
+			uc := NewAttendanceUseCase(repo, nil, nopLogger{})
+
+			got, err := uc.CheckOut(context.Background(), att.ID, &attendance.CheckOutRequest{CheckOutAt: &tt.checkOut})
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, got.DurationMinutes)
+			assert.Equal(t, tt.wantMinutes, *got.DurationMinutes)
+			assert.Equal(t, tt.wantMinutes, *repo.records[att.ID].DurationMinutes)
+			assert.Equal(t, tt.checkOut, *repo.records[att.ID].CheckOutAt)
+		})
+	}
+}
+
+func TestListHoursReportsRemaining(t *testing.T) {
+	repo := &hoursRepo{rows: []*attendance.CourseHours{
+		{CourseName: "Car", LoggedHours: 9.333333, RequiredHours: 10},
+		{CourseName: "Bike", LoggedHours: 12.5, RequiredHours: 10},
+	}}
+	uc := NewAttendanceUseCase(repo, nil, nopLogger{})
+
+	hours, err := uc.ListHours(context.Background(), attendance.HoursFilter{})
+	require.NoError(t, err)
+	require.Len(t, hours, 2)
+	assert.Equal(t, 9.33, hours[0].LoggedHours)
+	assert.Equal(t, 0.67, hours[0].RemainingHours)
+	assert.False(t, hours[0].Completed)
+	assert.Equal(t, float64(0), hours[1].RemainingHours)
+	assert.True(t, hours[1].Completed)
+
+	completed := true
+	hours, err = uc.ListHours(context.Background(), attendance.HoursFilter{Completed: &completed})
+	require.NoError(t, err)
+	require.Len(t, hours, 1)
+	assert.Equal(t, "Bike", hours[0].CourseName)
+}
+
+type hoursRepo struct {
+	attendance.Repository
+	rows []*attendance.CourseHours
+}
+
+func (r *hoursRepo) ListCourseHours(ctx context.Context, filter attendance.HoursFilter) ([]*attendance.CourseHours, error) {
+	rows := make([]*attendance.CourseHours, len(r.rows))
+	for i, h := range r.rows {
+		copied := *h
+		rows[i] = &copied
+	}
+	return rows, nil
+}
+
+type memSessionRepo struct {
+	lesson.Repository
+	sessions map[uuid.UUID]*lesson.Session
+}
+
+func (r *memSessionRepo) FindByID(ctx context.Context, id uuid.UUID) (*lesson.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	return session, nil
+}
+
+func (r *memSessionRepo) HasStudent(ctx context.Context, sessionID, studentID uuid.UUID) (bool, error) {
+	for _, id := range r.sessions[sessionID].StudentIDs {
+		if id == studentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestMarkAttendanceChecksTheRoster(t *testing.T) {
+	start := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+	ram, gita := uuid.New(), uuid.New()
+	scheduled := &lesson.Session{ID: uuid.New(), StartTime: start, Status: lesson.StatusScheduled, StudentIDs: []uuid.UUID{ram}}
+	cancelled := &lesson.Session{ID: uuid.New(), StartTime: start, Status: lesson.StatusCancelled, StudentIDs: []uuid.UUID{ram}}
+	repo := newMemAttendanceRepo()
+	sessions := &memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{scheduled.ID: scheduled, cancelled.ID: cancelled}}
+	uc := NewAttendanceUseCase(repo, sessions, nopLogger{})
+
+	tests := []struct {
+		name      string
+		studentID uuid.UUID
+		classID   uuid.UUID
+		wantCode  int
+	}{
+		{name: "student on the roster", studentID: ram, classID: scheduled.ID},
+		{name: "student not on the roster", studentID: gita, classID: scheduled.ID, wantCode: http.StatusBadRequest},
+		{name: "cancelled class", studentID: ram, classID: cancelled.ID, wantCode: http.StatusBadRequest},
+		{name: "unknown class", studentID: ram, classID: uuid.New(), wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(repo.records)
+
+			att, _, err := uc.MarkAttendance(context.Background(), &attendance.MarkAttendanceRequest{
+				StudentID: tt.studentID,
+				ClassID:   tt.classID,
+				Date:      start,
+				Status:    attendance.StatusPresent,
+			}, uuid.New())
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Len(t, repo.records, before)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.studentID, att.StudentID)
+			assert.Len(t, repo.records, before+1)
+		})
+	}
+}
+
+func TestMarkAttendanceRecordsTheClassDate(t *testing.T) {
+	start := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+	ram := uuid.New()
+	session := &lesson.Session{ID: uuid.New(), StartTime: start, Status: lesson.StatusScheduled, StudentIDs: []uuid.UUID{ram}}
+	repo := newMemAttendanceRepo()
+	uc := NewAttendanceUseCase(repo, &memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{session.ID: session}}, nopLogger{})
+	mark := func(date time.Time) (*attendance.Attendance, bool, error) {
+		return uc.MarkAttendance(context.Background(), &attendance.MarkAttendanceRequest{
+			StudentID: ram,
+			ClassID:   session.ID,
+			Date:      date,
+			Status:    attendance.StatusPresent,
+		}, uuid.New())
+	}
+
+	_, _, err := mark(start.AddDate(0, 0, 1))
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Empty(t, repo.records)
+
+	att, created, err := mark(start)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), att.Date)
+
+	// The same day given with another time of day is the same record.
+	again, created, err := mark(start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, att.ID, again.ID)
+}
+
+func TestSessionDateIsTheDayInNepal(t *testing.T) {
+	tests := []struct {
+		start time.Time
+		want  time.Time
+	}{
+		{start: time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC), want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		// 01:45 on 2 May in Kathmandu.
+		{start: time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC), want: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{start: time.Date(2024, 5, 2, 6, 0, 0, 0, bs.Kathmandu), want: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sessionDate(&lesson.Session{StartTime: tt.start}), "lesson starting %s", tt.start)
+	}
+}
+
+func TestMarkBulk(t *testing.T) {
+	start := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+	classDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	ram, sita, hari, gita := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	setup := func(status string) (*AttendanceUseCase, *memAttendanceRepo, *lesson.Session) {
+		session := &lesson.Session{ID: uuid.New(), StartTime: start, Status: status, StudentIDs: []uuid.UUID{ram, sita, hari}}
+		repo := newMemAttendanceRepo()
+		sessions := &memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{session.ID: session}}
+		return NewAttendanceUseCase(repo, sessions, nopLogger{}), repo, session
+	}
+
+	rowErrors := func(t *testing.T, err error) []string {
+		t.Helper()
+		appErr, ok := err.(*apperrors.AppError)
+		require.True(t, ok, "expected an AppError, got %v", err)
+		results := appErr.Details["results"].([]attendance.BulkResult)
+		errs := make([]string, len(results))
+		for i, r := range results {
+			assert.Equal(t, i, r.Row)
+			assert.Nil(t, r.Attendance)
+			errs[i] = r.Error
+		}
+		return errs
+	}
+
+	t.Run("saves every row", func(t *testing.T) {
+		uc, repo, session := setup(lesson.StatusScheduled)
+
+		results, err := uc.MarkBulk(context.Background(), &attendance.BulkMarkRequest{
+			ClassID: session.ID,
+			Date:    classDate,
+			Entries: []attendance.BulkEntry{
+				{StudentID: ram, Status: attendance.StatusPresent},
+				{StudentID: sita, Status: attendance.StatusAbsent, Notes: "sick"},
+				{StudentID: hari, Status: attendance.StatusLate},
+			},
+		}, uuid.New())
+
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Len(t, repo.records, 3)
+		for i, r := range results {
+			assert.Equal(t, i, r.Row)
+			assert.Empty(t, r.Error)
+			require.NotNil(t, r.Attendance)
+			assert.Equal(t, classDate, r.Attendance.Date)
+		}
+		assert.NotNil(t, results[0].Attendance.CheckInAt)
+		assert.Nil(t, results[1].Attendance.CheckInAt)
+		assert.Equal(t, "sick", results[1].Attendance.Notes)
+		assert.NotNil(t, results[2].Attendance.CheckInAt)
+	})
+
+	t.Run("reports every bad row and saves nothing", func(t *testing.T) {
+		uc, repo, session := setup(lesson.StatusScheduled)
+		marked := &attendance.Attendance{ID: uuid.New(), StudentID: hari, ClassID: session.ID, Date: classDate, Status: attendance.StatusPresent}
+		repo.records[marked.ID] = marked
+
+		_, err := uc.MarkBulk(context.Background(), &attendance.BulkMarkRequest{
+			ClassID: session.ID,
+			Date:    classDate,
+			Entries: []attendance.BulkEntry{
+				{StudentID: ram, Status: attendance.StatusPresent},
+				{StudentID: ram, Status: attendance.StatusAbsent},
+				{StudentID: gita, Status: attendance.StatusPresent},
+				{StudentID: hari, Status: attendance.StatusLate},
+				{StudentID: sita, Status: attendance.StatusPresent},
+			},
+		}, uuid.New())
+
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+		assert.Contains(t, err.Error(), "2 of 5 entries are invalid")
+		assert.Equal(t, []string{
+			"",
+			"student appears more than once",
+			"student is not on the roster of this class",
+			"",
+			"",
+		}, rowErrors(t, err))
+		assert.Len(t, repo.records, 1)
+		assert.Equal(t, attendance.StatusPresent, repo.records[marked.ID].Status)
+	})
+
+	t.Run("updates students already marked", func(t *testing.T) {
+		uc, repo, session := setup(lesson.StatusScheduled)
+		marked := &attendance.Attendance{ID: uuid.New(), StudentID: hari, ClassID: session.ID, Date: classDate, Status: attendance.StatusAbsent}
+		repo.records[marked.ID] = marked
+
+		results, err := uc.MarkBulk(context.Background(), &attendance.BulkMarkRequest{
+			ClassID: session.ID,
+			Date:    classDate,
+			Entries: []attendance.BulkEntry{
+				{StudentID: ram, Status: attendance.StatusPresent},
+				{StudentID: hari, Status: attendance.StatusLate},
+			},
+		}, uuid.New())
+
+		require.NoError(t, err)
+		assert.Len(t, repo.records, 2)
+		assert.Equal(t, marked.ID, results[1].Attendance.ID)
+		assert.Equal(t, attendance.StatusLate, repo.records[marked.ID].Status)
+	})
+
+	t.Run("rejects a date other than the class date", func(t *testing.T) {
+		uc, repo, session := setup(lesson.StatusScheduled)
+
+		_, err := uc.MarkBulk(context.Background(), &attendance.BulkMarkRequest{
+			ClassID: session.ID,
+			Date:    classDate.AddDate(0, 0, 1),
+			Entries: []attendance.BulkEntry{{StudentID: ram, Status: attendance.StatusPresent}},
+		}, uuid.New())
+
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+		assert.Contains(t, err.Error(), "date does not match")
+		assert.Empty(t, repo.records)
+	})
+
+	t.Run("rejects a cancelled class", func(t *testing.T) {
+		uc, repo, session := setup(lesson.StatusCancelled)
+
+		_, err := uc.MarkBulk(context.Background(), &attendance.BulkMarkRequest{
+			ClassID: session.ID,
+			Date:    classDate,
+			Entries: []attendance.BulkEntry{{StudentID: ram, Status: attendance.StatusPresent}},
+		}, uuid.New())
+
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+		assert.Empty(t, repo.records)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/chalak/backend/pkg/auth"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/tenant"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -47,21 +48,19 @@ func (uc *AuthUseCase) Register(ctx context.Context, req *user.RegisterRequest)
 		return nil, apperrors.Conflict("email already registered")
 	}
 
+	// Self-service accounts start without a role or institute; an admin
+	// places them with Assign before they can reach any tenant data.
 	usr := &user.User{
 		ID:        uuid.New(),
 		Email:     req.Email,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
-		Role:      req.Role,
+		Role:      "user",
 		Status:    "active",
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
 
-	if usr.Role == "" {
-		usr.Role = "user"
-	}
-
 	if err := usr.HashPassword(req.Password); err != nil {
 		uc.logger.Error(ctx, "failed to hash password", err, map[string]interface{}{})
 		return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -112,7 +111,7 @@ func (uc *AuthUseCase) Login(ctx context.Context, req *user.LoginRequest) (*user
 		return nil, apperrors.Unauthorized("invalid email or password")
 	}
 
-	accessToken, err := uc.jwtService.GenerateToken(usr.ID, usr.Role)
+	accessToken, err := uc.jwtService.GenerateToken(usr.ID, usr.Role, usr.InstituteID)
 	if err != nil {
 		uc.logger.Error(ctx, "failed to generate access token", err, map[string]interface{}{
 			"user_id": usr.ID,
@@ -166,7 +165,7 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshToken string) (*
 		return nil, apperrors.Unauthorized("account is not active")
 	}
 
-	accessToken, err := uc.jwtService.GenerateToken(usr.ID, usr.Role)
+	accessToken, err := uc.jwtService.GenerateToken(usr.ID, usr.Role, usr.InstituteID)
 	if err != nil {
 		uc.logger.Error(ctx, "failed to generate new access token", err, map[string]interface{}{
 			"user_id": usr.ID,
@@ -193,6 +192,54 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshToken string) (*
 	}, nil
 }
 
+// Assign gives a registered user a role in an institute. Institute admins can
+// only claim self-registered accounts that are not yet in any institute and
+// always assign to their own; platform admins name the institute and may move
+// anyone.
+func (uc *AuthUseCase) Assign(ctx context.Context, id uuid.UUID, req *user.AssignRequest) (*user.User, error) {
+	usr, err := uc.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	instituteID := req.InstituteID
+	if scoped, ok := tenant.InstituteID(ctx); ok {
+		if instituteID != nil && *instituteID != scoped {
+			return nil, apperrors.Forbidden("cannot assign users to another institute")
+		}
+		if usr.InstituteID != nil && *usr.InstituteID != scoped {
+			return nil, apperrors.NotFound("user not found")
+		}
+		// Accounts without an institute include the platform admins, which
+		// must never be claimed by a tenant.
+		if usr.InstituteID == nil && usr.Role != "user" {
+			return nil, apperrors.Forbidden("only platform administrators can assign this account")
+		}
+		instituteID = &scoped
+	} else if instituteID == nil {
+		return nil, apperrors.BadRequest("institute_id is required")
+	}
+
+	usr.Role = req.Role
+	usr.InstituteID = instituteID
+	usr.UpdatedAt = time.Now().UTC()
+
+	if err := uc.userRepo.Update(ctx, usr); err != nil {
+		uc.logger.Error(ctx, "failed to assign user", err, map[string]interface{}{
+			"user_id": id,
+		})
+		return nil, fmt.Errorf("failed to assign user: %w", err)
+	}
+
+	uc.logger.Info(ctx, "user assigned to institute", map[string]interface{}{
+		"user_id":      usr.ID,
+		"institute_id": *instituteID,
+		"role":         usr.Role,
+	})
+
+	return usr, nil
+}
+
 func (uc *AuthUseCase) GetUserByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
 	usr, err := uc.userRepo.FindByID(ctx, id)
 	if err != nil {
@@ -2,15 +2,20 @@ package usecase_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/chalak/backend/internal/domain/user"
 	"github.com/chalak/backend/internal/usecase"
 	"github.com/chalak/backend/pkg/auth"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 type MockUserRepository struct {
@@ -75,7 +80,6 @@ func TestAuthUseCase_Register(t *testing.T) {
 			Password:  "password123",
 			FirstName: "John",
 			LastName:  "Doe",
-			Role:      "student",
 		}
 
 		mockRepo.On("FindByEmail", ctx, req.Email).Return(nil, nil).Once()
@@ -90,6 +94,8 @@ func TestAuthUseCase_Register(t *testing.T) {
 		assert.Equal(t, req.LastName, usr.LastName)
 		assert.NotEmpty(t, usr.Password)
 		assert.NotEqual(t, req.Password, usr.Password)
+		assert.Equal(t, "user", usr.Role)
+		assert.Nil(t, usr.InstituteID)
 
 		mockRepo.AssertExpectations(t)
 	})
@@ -100,7 +106,6 @@ func TestAuthUseCase_Register(t *testing.T) {
 			Password:  "password123",
 			FirstName: "Jane",
 			LastName:  "Doe",
-			Role:      "student",
 		}
 
 		existingUser := &user.User{
@@ -120,6 +125,101 @@ func TestAuthUseCase_Register(t *testing.T) {
 	})
 }
 
+func TestAuthUseCase_Assign(t *testing.T) {
+	mockLogger := &MockLogger{}
+	jwtService := auth.NewJWTService("test-secret", time.Hour, time.Hour*24)
+
+	ownInstitute := uuid.New()
+	otherInstitute := uuid.New()
+	adminCtx := tenant.WithInstituteID(context.Background(), ownInstitute)
+
+	t.Run("institute admin assigns to own institute", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc := usecase.NewAuthUseCase(mockRepo, jwtService, mockLogger, time.Hour)
+		usr := &user.User{ID: uuid.New(), Role: "user"}
+
+		mockRepo.On("FindByID", adminCtx, usr.ID).Return(usr, nil).Once()
+		mockRepo.On("Update", adminCtx, usr).Return(nil).Once()
+
+		got, err := uc.Assign(adminCtx, usr.ID, &user.AssignRequest{Role: "instructor"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "instructor", got.Role)
+		assert.Equal(t, ownInstitute, *got.InstituteID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("institute admin cannot name another institute", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc := usecase.NewAuthUseCase(mockRepo, jwtService, mockLogger, time.Hour)
+		usr := &user.User{ID: uuid.New(), Role: "user"}
+
+		mockRepo.On("FindByID", adminCtx, usr.ID).Return(usr, nil).Once()
+
+		_, err := uc.Assign(adminCtx, usr.ID, &user.AssignRequest{Role: "admin", InstituteID: &otherInstitute})
+
+		assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("institute admin cannot take users from another institute", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc := usecase.NewAuthUseCase(mockRepo, jwtService, mockLogger, time.Hour)
+		usr := &user.User{ID: uuid.New(), Role: "instructor", InstituteID: &otherInstitute}
+
+		mockRepo.On("FindByID", adminCtx, usr.ID).Return(usr, nil).Once()
+
+		_, err := uc.Assign(adminCtx, usr.ID, &user.AssignRequest{Role: "instructor"})
+
+		assert.Equal(t, http.StatusNotFound, apperrors.GetStatusCode(err))
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("institute admin cannot claim a platform admin", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc := usecase.NewAuthUseCase(mockRepo, jwtService, mockLogger, time.Hour)
+		usr := &user.User{ID: uuid.New(), Role: "admin"}
+
+		mockRepo.On("FindByID", adminCtx, usr.ID).Return(usr, nil).Once()
+
+		_, err := uc.Assign(adminCtx, usr.ID, &user.AssignRequest{Role: "student"})
+
+		assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+		assert.Equal(t, "admin", usr.Role)
+		assert.Nil(t, usr.InstituteID)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("platform admin can assign a platform admin", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc := usecase.NewAuthUseCase(mockRepo, jwtService, mockLogger, time.Hour)
+		ctx := context.Background()
+		usr := &user.User{ID: uuid.New(), Role: "admin"}
+
+		mockRepo.On("FindByID", ctx, usr.ID).Return(usr, nil).Once()
+		mockRepo.On("Update", ctx, usr).Return(nil).Once()
+
+		got, err := uc.Assign(ctx, usr.ID, &user.AssignRequest{Role: "admin", InstituteID: &ownInstitute})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ownInstitute, *got.InstituteID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("platform admin must name the institute", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc := usecase.NewAuthUseCase(mockRepo, jwtService, mockLogger, time.Hour)
+		ctx := context.Background()
+		usr := &user.User{ID: uuid.New(), Role: "user"}
+
+		mockRepo.On("FindByID", ctx, usr.ID).Return(usr, nil).Once()
+
+		_, err := uc.Assign(ctx, usr.ID, &user.AssignRequest{Role: "student"})
+
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	})
+}
+
 func TestAuthUseCase_Login(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockLogger := &MockLogger{}
@@ -129,16 +229,14 @@ func TestAuthUseCase_Login(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("successful login", func(t *testing.T) {
-		hashedPassword := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
-
 		existingUser := &user.User{
 			ID:        uuid.New(),
 			Email:     "test@example.com",
-			Password:  hashedPassword,
 			FirstName: "John",
 			LastName:  "Doe",
 			Status:    "active",
 		}
+		require.NoError(t, existingUser.HashPassword("secret"))
 
 		req := &user.LoginRequest{
 			Email:    "test@example.com",
@@ -149,8 +247,8 @@ func TestAuthUseCase_Login(t *testing.T) {
 
 		resp, err := uc.Login(ctx, req)
 
-		assert.NoError(t, err)
-		assert.NotNil(t, resp)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
 		assert.Equal(t, existingUser.ID, resp.User.ID)
 		assert.NotEmpty(t, resp.AccessToken)
 		assert.NotEmpty(t, resp.RefreshToken)
@@ -164,7 +262,7 @@ func TestAuthUseCase_Login(t *testing.T) {
 			Password: "password123",
 		}
 
-		mockRepo.On("FindByEmail", ctx, req.Email).Return(nil, assert.AnError).Once()
+		mockRepo.On("FindByEmail", ctx, req.Email).Return(nil, gorm.ErrRecordNotFound).Once()
 
 		resp, err := uc.Login(ctx, req)
 
@@ -176,14 +274,12 @@ func TestAuthUseCase_Login(t *testing.T) {
 	})
 
 	t.Run("inactive user", func(t *testing.T) {
-		hashedPassword := "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
-
 		inactiveUser := &user.User{
-			ID:       uuid.New(),
-			Email:    "inactive@example.com",
-			Password: hashedPassword,
-			Status:   "inactive",
+			ID:     uuid.New(),
+			Email:  "inactive@example.com",
+			Status: "inactive",
 		}
+		require.NoError(t, inactiveUser.HashPassword("secret"))
 
 		req := &user.LoginRequest{
 			Email:    "inactive@example.com",
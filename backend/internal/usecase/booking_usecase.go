@@ -0,0 +1,599 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/booking"
+	"github.com/chalak/backend/internal/domain/employee"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/notification"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/bs"
+	"github.com/chalak/backend/pkg/cache"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const defaultSlotMinutes = 60
+
+// holdLockTTL bounds how long a crashed request can keep an instructor's
+// holds locked; holdLockAttempts and holdLockWait how long a request waits
+// for the lock before giving up.
+const (
+	holdLockTTL      = 5 * time.Second
+	holdLockAttempts = 10
+	holdLockWait     = 50 * time.Millisecond
+)
+
+// BookingUseCase lets students book practical lessons themselves. Available
+// slots come from instructor working hours, in Nepal time, minus existing
+// sessions and holds; a hold reserves the instructor's time in Redis for
+// holdTTL and confirming it creates the lesson session. Holds are advisory:
+// the session itself is still checked for double-booking when it is created.
+type BookingUseCase struct {
+	repo             booking.Repository
+	lessonRepo       lesson.Repository
+	lessonUseCase    *LessonUseCase
+	studentRepo      student.Repository
+	employeeRepo     employee.Repository
+	notificationRepo notification.Repository
+	cache            holdCache
+	holdTTL          time.Duration
+	logger           logger.Logger
+}
+
+// holdCache is the part of the Redis cache that holds are kept in.
+type holdCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// heldSlot is a hold as recorded against its instructor.
+type heldSlot struct {
+	HoldID    uuid.UUID `json:"hold_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewBookingUseCase(
+	repo booking.Repository,
+	lessonRepo lesson.Repository,
+	lessonUseCase *LessonUseCase,
+	studentRepo student.Repository,
+	employeeRepo employee.Repository,
+	notificationRepo notification.Repository,
+	redisCache *cache.RedisCache,
+	holdTTL time.Duration,
+	logger logger.Logger,
+) *BookingUseCase {
+	uc := &BookingUseCase{
+		repo:             repo,
+		lessonRepo:       lessonRepo,
+		lessonUseCase:    lessonUseCase,
+		studentRepo:      studentRepo,
+		employeeRepo:     employeeRepo,
+		notificationRepo: notificationRepo,
+		holdTTL:          holdTTL,
+		logger:           logger,
+	}
+	// Without Redis there are no holds; a nil *RedisCache must not become a
+	// non-nil holdCache.
+	if redisCache != nil {
+		uc.cache = redisCache
+	}
+	return uc
+}
+
+func (uc *BookingUseCase) GetWorkingHours(ctx context.Context, instructorID uuid.UUID) ([]*booking.WorkingHours, error) {
+	if _, err := uc.employeeRepo.FindByID(ctx, instructorID); err != nil {
+		return nil, apperrors.NotFound("instructor not found")
+	}
+
+	hours, err := uc.repo.ListWorkingHours(ctx, booking.WorkingHoursFilter{InstructorID: &instructorID})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list working hours", err, map[string]interface{}{
+			"instructor_id": instructorID,
+		})
+		return nil, fmt.Errorf("failed to list working hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+func (uc *BookingUseCase) SetWorkingHours(ctx context.Context, instructorID uuid.UUID, req *booking.SetWorkingHoursRequest) ([]*booking.WorkingHours, error) {
+	if _, err := uc.employeeRepo.FindByID(ctx, instructorID); err != nil {
+		return nil, apperrors.NotFound("instructor not found")
+	}
+
+	hours := make([]*booking.WorkingHours, 0, len(req.Hours))
+	for _, entry := range req.Hours {
+		if entry.StartTime >= entry.EndTime {
+			return nil, apperrors.BadRequest("working hours must end after they start")
+		}
+		hours = append(hours, &booking.WorkingHours{
+			ID:           uuid.New(),
+			InstructorID: instructorID,
+			Weekday:      entry.Weekday,
+			StartTime:    entry.StartTime,
+			EndTime:      entry.EndTime,
+			CreatedAt:    time.Now().UTC(),
+			UpdatedAt:    time.Now().UTC(),
+		})
+	}
+
+	// "HH:MM" strings order chronologically, so overlaps show up as a window
+	// starting before the previous one on the same day ends.
+	sorted := append([]*booking.WorkingHours(nil), hours...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].StartTime < sorted[j].StartTime
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Weekday == sorted[i-1].Weekday && sorted[i].StartTime < sorted[i-1].EndTime {
+			return nil, apperrors.BadRequest("working hours overlap")
+		}
+	}
+
+	if err := uc.repo.ReplaceWorkingHours(ctx, instructorID, hours); err != nil {
+		uc.logger.Error(ctx, "failed to set working hours", err, map[string]interface{}{
+			"instructor_id": instructorID,
+		})
+		return nil, fmt.Errorf("failed to set working hours: %w", err)
+	}
+
+	uc.logger.Info(ctx, "working hours updated", map[string]interface{}{
+		"instructor_id": instructorID,
+		"windows":       len(hours),
+	})
+
+	return hours, nil
+}
+
+// ListSlots returns the slots the student behind userID can book on
+// filter.Date, with one instructor or all of them.
+func (uc *BookingUseCase) ListSlots(ctx context.Context, userID uuid.UUID, filter booking.SlotFilter) ([]booking.Slot, error) {
+	s, err := uc.studentForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.availableSlots(ctx, s, filter)
+}
+
+func (uc *BookingUseCase) CreateHold(ctx context.Context, userID uuid.UUID, req *booking.CreateHoldRequest) (*booking.Hold, error) {
+	if uc.cache == nil {
+		return nil, apperrors.New(fmt.Errorf("redis unavailable"), "slot holds are unavailable")
+	}
+
+	s, err := uc.studentForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := req.DurationMinutes
+	if duration == 0 {
+		duration = defaultSlotMinutes
+	}
+
+	slots, err := uc.availableSlots(ctx, s, booking.SlotFilter{
+		InstructorID:    &req.InstructorID,
+		Date:            req.StartTime.In(bs.Kathmandu),
+		DurationMinutes: duration,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var slot *booking.Slot
+	for i := range slots {
+		if slots[i].StartTime.Equal(req.StartTime) {
+			slot = &slots[i]
+			break
+		}
+	}
+	if slot == nil {
+		return nil, apperrors.Conflict("slot is not available")
+	}
+
+	hold := &booking.Hold{
+		ID:           uuid.New(),
+		InstituteID:  s.InstituteID,
+		StudentID:    s.ID,
+		InstructorID: slot.InstructorID,
+		CourseID:     req.CourseID,
+		VehicleID:    req.VehicleID,
+		StartTime:    slot.StartTime,
+		EndTime:      slot.EndTime,
+		ExpiresAt:    time.Now().Add(uc.holdTTL).UTC(),
+	}
+
+	err = uc.updateHolds(ctx, hold.InstructorID, func(held []heldSlot) ([]heldSlot, error) {
+		if overlapsHold(held, hold.StartTime, hold.EndTime) {
+			return nil, apperrors.Conflict("slot is already held by someone else")
+		}
+		return append(held, heldSlot{
+			HoldID:    hold.ID,
+			StartTime: hold.StartTime,
+			EndTime:   hold.EndTime,
+			ExpiresAt: hold.ExpiresAt,
+		}), nil
+	})
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		uc.logger.Error(ctx, "failed to hold slot", err, map[string]interface{}{
+			"instructor_id": hold.InstructorID,
+		})
+		return nil, fmt.Errorf("failed to hold slot: %w", err)
+	}
+
+	payload, err := json.Marshal(hold)
+	if err != nil {
+		uc.releaseHold(ctx, hold)
+		return nil, fmt.Errorf("failed to encode hold: %w", err)
+	}
+	if err := uc.cache.Set(ctx, holdKey(hold.ID), payload, uc.holdTTL); err != nil {
+		uc.releaseHold(ctx, hold)
+		uc.logger.Error(ctx, "failed to save hold", err, map[string]interface{}{
+			"hold_id": hold.ID,
+		})
+		return nil, fmt.Errorf("failed to save hold: %w", err)
+	}
+
+	uc.logger.Info(ctx, "slot held", map[string]interface{}{
+		"hold_id":       hold.ID,
+		"student_id":    hold.StudentID,
+		"instructor_id": hold.InstructorID,
+		"start_time":    hold.StartTime,
+	})
+
+	return hold, nil
+}
+
+// ConfirmHold turns the student's hold into a practical lesson session and
+// tells the instructor about it.
+func (uc *BookingUseCase) ConfirmHold(ctx context.Context, userID, holdID uuid.UUID) (*lesson.Session, error) {
+	s, err := uc.studentForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := uc.getHold(ctx, holdID, s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.lessonUseCase.Create(ctx, &lesson.CreateSessionRequest{
+		CourseID:     hold.CourseID,
+		InstructorID: hold.InstructorID,
+		VehicleID:    hold.VehicleID,
+		StartTime:    hold.StartTime,
+		EndTime:      hold.EndTime,
+		Type:         lesson.TypePractical,
+		Capacity:     1,
+		StudentIDs:   []uuid.UUID{s.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.releaseHold(ctx, hold)
+	uc.notifyInstructor(ctx, session, s)
+
+	uc.logger.Info(ctx, "hold confirmed", map[string]interface{}{
+		"hold_id":    hold.ID,
+		"session_id": session.ID,
+	})
+
+	return session, nil
+}
+
+func (uc *BookingUseCase) ReleaseHold(ctx context.Context, userID, holdID uuid.UUID) error {
+	s, err := uc.studentForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	hold, err := uc.getHold(ctx, holdID, s.ID)
+	if err != nil {
+		return err
+	}
+
+	uc.releaseHold(ctx, hold)
+	return nil
+}
+
+func (uc *BookingUseCase) availableSlots(ctx context.Context, s *student.Student, filter booking.SlotFilter) ([]booking.Slot, error) {
+	duration := filter.DurationMinutes
+	if duration == 0 {
+		duration = defaultSlotMinutes
+	}
+
+	date := filter.Date.In(bs.Kathmandu)
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, bs.Kathmandu)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	weekday := int(dayStart.Weekday())
+
+	hours, err := uc.repo.ListWorkingHours(ctx, booking.WorkingHoursFilter{
+		InstructorID: filter.InstructorID,
+		Weekday:      &weekday,
+	})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list working hours", err, nil)
+		return nil, fmt.Errorf("failed to list working hours: %w", err)
+	}
+	if len(hours) == 0 {
+		return []booking.Slot{}, nil
+	}
+
+	// Sessions that day, of any instructor or of the student.
+	sessions, _, err := uc.lessonRepo.List(ctx, lesson.SessionFilter{
+		DateFrom: &dayStart,
+		DateTo:   &dayEnd,
+	})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list lesson sessions", err, nil)
+		return nil, fmt.Errorf("failed to list lesson sessions: %w", err)
+	}
+
+	// Holds of each instructor, loaded on first use. A failure to read them
+	// only loses the hint; CreateHold checks again under the lock.
+	holds := make(map[uuid.UUID][]heldSlot)
+	busy := func(instructorID uuid.UUID, start, end time.Time) bool {
+		for _, session := range sessions {
+			if session.Status == lesson.StatusCancelled {
+				continue
+			}
+			if !session.StartTime.Before(end) || !session.EndTime.After(start) {
+				continue
+			}
+			if session.InstructorID == instructorID {
+				return true
+			}
+			for _, studentID := range session.StudentIDs {
+				if studentID == s.ID {
+					return true
+				}
+			}
+		}
+		if uc.cache == nil {
+			return false
+		}
+		held, ok := holds[instructorID]
+		if !ok {
+			held, _ = uc.loadHolds(ctx, instructorID)
+			holds[instructorID] = held
+		}
+		return overlapsHold(held, start, end)
+	}
+
+	return generateSlots(hours, dayStart, time.Duration(duration)*time.Minute, time.Now(), busy), nil
+}
+
+// generateSlots cuts each working-hours window on day into back-to-back slots
+// of the given length, skipping slots that start before now or that busy
+// reports as taken.
+func generateSlots(
+	hours []*booking.WorkingHours,
+	day time.Time,
+	length time.Duration,
+	now time.Time,
+	busy func(instructorID uuid.UUID, start, end time.Time) bool,
+) []booking.Slot {
+	slots := make([]booking.Slot, 0)
+	for _, h := range hours {
+		windowStart, err1 := clockOn(day, h.StartTime)
+		windowEnd, err2 := clockOn(day, h.EndTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		for start := windowStart; !start.Add(length).After(windowEnd); start = start.Add(length) {
+			end := start.Add(length)
+			if start.Before(now) || busy(h.InstructorID, start, end) {
+				continue
+			}
+			slots = append(slots, booking.Slot{
+				InstructorID: h.InstructorID,
+				StartTime:    start,
+				EndTime:      end,
+			})
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+	return slots
+}
+
+// clockOn returns the "HH:MM" wall-clock time on day, in day's location.
+func clockOn(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+func (uc *BookingUseCase) studentForUser(ctx context.Context, userID uuid.UUID) (*student.Student, error) {
+	s, err := uc.studentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Forbidden("only students can book lessons")
+	}
+	if s.Status != "active" {
+		return nil, apperrors.Forbidden("student account is not active")
+	}
+	return s, nil
+}
+
+func (uc *BookingUseCase) getHold(ctx context.Context, holdID, studentID uuid.UUID) (*booking.Hold, error) {
+	if uc.cache == nil {
+		return nil, apperrors.NotFound("hold not found or expired")
+	}
+
+	payload, err := uc.cache.Get(ctx, holdKey(holdID))
+	if err != nil {
+		return nil, apperrors.NotFound("hold not found or expired")
+	}
+
+	var hold booking.Hold
+	if err := json.Unmarshal([]byte(payload), &hold); err != nil {
+		return nil, fmt.Errorf("failed to decode hold: %w", err)
+	}
+	if hold.StudentID != studentID {
+		return nil, apperrors.NotFound("hold not found or expired")
+	}
+	return &hold, nil
+}
+
+func (uc *BookingUseCase) releaseHold(ctx context.Context, hold *booking.Hold) {
+	err := uc.updateHolds(ctx, hold.InstructorID, func(held []heldSlot) ([]heldSlot, error) {
+		kept := held[:0]
+		for _, h := range held {
+			if h.HoldID != hold.ID {
+				kept = append(kept, h)
+			}
+		}
+		return kept, nil
+	})
+	if err == nil {
+		err = uc.cache.Delete(ctx, holdKey(hold.ID))
+	}
+	if err != nil {
+		uc.logger.Warn(ctx, "failed to release hold", map[string]interface{}{
+			"hold_id": hold.ID,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// loadHolds returns the unexpired holds on the instructor's time.
+func (uc *BookingUseCase) loadHolds(ctx context.Context, instructorID uuid.UUID) ([]heldSlot, error) {
+	payload, err := uc.cache.Get(ctx, instructorHoldsKey(instructorID))
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var held []heldSlot
+	if err := json.Unmarshal([]byte(payload), &held); err != nil {
+		return nil, fmt.Errorf("failed to decode holds: %w", err)
+	}
+
+	now := time.Now()
+	live := held[:0]
+	for _, h := range held {
+		if h.ExpiresAt.After(now) {
+			live = append(live, h)
+		}
+	}
+	return live, nil
+}
+
+// updateHolds replaces the instructor's holds with what update makes of
+// them. The instructor's holds are locked meanwhile, so two students cannot
+// hold overlapping slots of different lengths at the same time.
+func (uc *BookingUseCase) updateHolds(ctx context.Context, instructorID uuid.UUID, update func([]heldSlot) ([]heldSlot, error)) error {
+	lock := instructorHoldsKey(instructorID) + ":lock"
+	for attempt := 0; ; attempt++ {
+		locked, err := uc.cache.SetNX(ctx, lock, "1", holdLockTTL)
+		if err != nil {
+			return err
+		}
+		if locked {
+			break
+		}
+		if attempt == holdLockAttempts {
+			return apperrors.Conflict("instructor is being booked by someone else, try again")
+		}
+		time.Sleep(holdLockWait)
+	}
+	defer uc.cache.Delete(ctx, lock)
+
+	held, err := uc.loadHolds(ctx, instructorID)
+	if err != nil {
+		return err
+	}
+	held, err = update(held)
+	if err != nil {
+		return err
+	}
+	if len(held) == 0 {
+		return uc.cache.Delete(ctx, instructorHoldsKey(instructorID))
+	}
+
+	payload, err := json.Marshal(held)
+	if err != nil {
+		return fmt.Errorf("failed to encode holds: %w", err)
+	}
+	latest := held[0].ExpiresAt
+	for _, h := range held[1:] {
+		if h.ExpiresAt.After(latest) {
+			latest = h.ExpiresAt
+		}
+	}
+	return uc.cache.Set(ctx, instructorHoldsKey(instructorID), payload, time.Until(latest))
+}
+
+// overlapsHold reports whether any of held overlaps start to end.
+func overlapsHold(held []heldSlot, start, end time.Time) bool {
+	for _, h := range held {
+		if h.StartTime.Before(end) && h.EndTime.After(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *BookingUseCase) notifyInstructor(ctx context.Context, session *lesson.Session, s *student.Student) {
+	instructor, err := uc.employeeRepo.FindByID(ctx, session.InstructorID)
+	if err != nil || instructor.UserID == nil {
+		uc.logger.Warn(ctx, "instructor has no user account to notify", map[string]interface{}{
+			"instructor_id": session.InstructorID,
+		})
+		return
+	}
+
+	data, _ := json.Marshal(map[string]string{"session_id": session.ID.String()})
+	notif := &notification.Notification{
+		ID:     uuid.New(),
+		UserID: *instructor.UserID,
+		Type:   notification.TypeLesson,
+		Title:  "New lesson booked",
+		Message: fmt.Sprintf("%s %s booked a lesson on %s.",
+			s.FirstName, s.LastName, session.StartTime.In(bs.Kathmandu).Format("Mon 2 Jan 15:04")),
+		Data:      string(data),
+		SentVia:   notification.SentViaInApp,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := uc.notificationRepo.Create(ctx, notif); err != nil {
+		uc.logger.Error(ctx, "failed to notify instructor of booking", err, map[string]interface{}{
+			"session_id": session.ID,
+		})
+	}
+}
+
+func holdKey(id uuid.UUID) string {
+	return fmt.Sprintf("booking:hold:%s", id)
+}
+
+func instructorHoldsKey(instructorID uuid.UUID) string {
+	return fmt.Sprintf("booking:holds:%s", instructorID)
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/booking"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/bs"
+	"github.com/chalak/backend/pkg/cache"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSlots(t *testing.T) {
+	instructorID := uuid.New()
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	hours := []*booking.WorkingHours{
+		{InstructorID: instructorID, Weekday: 1, StartTime: "13:00", EndTime: "15:30"},
+		{InstructorID: instructorID, Weekday: 1, StartTime: "09:00", EndTime: "11:00"},
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 4, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("cuts windows into whole slots in order", func(t *testing.T) {
+		slots := generateSlots(hours, day, time.Hour, day, func(uuid.UUID, time.Time, time.Time) bool {
+			return false
+		})
+
+		starts := make([]time.Time, 0, len(slots))
+		for _, s := range slots {
+			assert.Equal(t, instructorID, s.InstructorID)
+			assert.Equal(t, time.Hour, s.EndTime.Sub(s.StartTime))
+			starts = append(starts, s.StartTime)
+		}
+		assert.Equal(t, []time.Time{at(9, 0), at(10, 0), at(13, 0), at(14, 0)}, starts)
+	})
+
+	t.Run("skips past and busy slots", func(t *testing.T) {
+		busy := func(_ uuid.UUID, start, end time.Time) bool {
+			return start.Before(at(14, 0)) && end.After(at(13, 30))
+		}
+
+		slots := generateSlots(hours, day, time.Hour, at(9, 30), busy)
+
+		assert.Len(t, slots, 2)
+		assert.Equal(t, at(10, 0), slots[0].StartTime)
+		assert.Equal(t, at(14, 0), slots[1].StartTime)
+	})
+}
+
+// memCache keeps keys in a map, expiring them like Redis does.
+type memCache struct {
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+func newMemCache() *memCache {
+	return &memCache{values: map[string]string{}, expires: map[string]time.Time{}}
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, error) {
+	v, ok := c.values[key]
+	if !ok || (!c.expires[key].IsZero() && !c.expires[key].After(time.Now())) {
+		return "", fmt.Errorf("%w: %s", cache.ErrNotFound, key)
+	}
+	return v, nil
+}
+
+func (c *memCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	switch v := value.(type) {
+	case []byte:
+		c.values[key] = string(v)
+	default:
+		c.values[key] = fmt.Sprint(v)
+	}
+	c.expires[key] = time.Time{}
+	if expiration > 0 {
+		c.expires[key] = time.Now().Add(expiration)
+	}
+	return nil
+}
+
+func (c *memCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if _, err := c.Get(ctx, key); err == nil {
+		return false, nil
+	}
+	return true, c.Set(ctx, key, value, expiration)
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) error {
+	delete(c.values, key)
+	delete(c.expires, key)
+	return nil
+}
+
+type memWorkingHoursRepo struct {
+	booking.Repository
+	hours []*booking.WorkingHours
+}
+
+func (r *memWorkingHoursRepo) ListWorkingHours(ctx context.Context, filter booking.WorkingHoursFilter) ([]*booking.WorkingHours, error) {
+	var list []*booking.WorkingHours
+	for _, h := range r.hours {
+		if filter.InstructorID != nil && h.InstructorID != *filter.InstructorID {
+			continue
+		}
+		if filter.Weekday != nil && h.Weekday != *filter.Weekday {
+			continue
+		}
+		list = append(list, h)
+	}
+	return list, nil
+}
+
+func (r *memSessionRepo) List(ctx context.Context, filter lesson.SessionFilter) ([]*lesson.Session, int64, error) {
+	var list []*lesson.Session
+	for _, s := range r.sessions {
+		list = append(list, s)
+	}
+	return list, int64(len(list)), nil
+}
+
+func (r *lessonStudentRepo) GetByUserID(ctx context.Context, userID uuid.UUID) (*student.Student, error) {
+	for _, s := range r.students {
+		if s.UserID != nil && *s.UserID == userID {
+			return s, nil
+		}
+	}
+	return nil, errors.New("student not found")
+}
+
+type bookingFixture struct {
+	uc           *BookingUseCase
+	cache        *memCache
+	instructorID uuid.UUID
+	day          time.Time
+	ram, sita    uuid.UUID
+}
+
+// newBookingFixture sets up an instructor who works 09:00 to 12:00 Nepal
+// time on a day a week from now, and two students, Ram and Sita, who book
+// through their user accounts.
+func newBookingFixture() *bookingFixture {
+	now := time.Now().In(bs.Kathmandu)
+	f := &bookingFixture{
+		cache:        newMemCache(),
+		instructorID: uuid.New(),
+		day:          time.Date(now.Year(), now.Month(), now.Day()+7, 0, 0, 0, 0, bs.Kathmandu),
+		ram:          uuid.New(),
+		sita:         uuid.New(),
+	}
+	instituteID := uuid.New()
+	students := &lessonStudentRepo{students: map[uuid.UUID]*student.Student{}}
+	for _, userID := range []uuid.UUID{f.ram, f.sita} {
+		userID := userID
+		id := uuid.New()
+		students.students[id] = &student.Student{ID: id, InstituteID: instituteID, UserID: &userID, Status: "active"}
+	}
+	hours := &memWorkingHoursRepo{hours: []*booking.WorkingHours{
+		{InstructorID: f.instructorID, Weekday: int(f.day.Weekday()), StartTime: "09:00", EndTime: "12:00"},
+	}}
+	f.uc = &BookingUseCase{
+		repo:        hours,
+		lessonRepo:  &memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{}},
+		studentRepo: students,
+		cache:       f.cache,
+		holdTTL:     10 * time.Minute,
+		logger:      nopLogger{},
+	}
+	return f
+}
+
+func (f *bookingFixture) at(hour, minute int) time.Time {
+	return f.day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func (f *bookingFixture) hold(userID uuid.UUID, start time.Time, minutes int) (*booking.Hold, error) {
+	return f.uc.CreateHold(context.Background(), userID, &booking.CreateHoldRequest{
+		InstructorID:    f.instructorID,
+		CourseID:        uuid.New(),
+		StartTime:       start,
+		DurationMinutes: minutes,
+	})
+}
+
+func (f *bookingFixture) slotStarts(t *testing.T, userID uuid.UUID, minutes int) []time.Time {
+	t.Helper()
+	slots, err := f.uc.ListSlots(context.Background(), userID, booking.SlotFilter{
+		InstructorID:    &f.instructorID,
+		Date:            f.day,
+		DurationMinutes: minutes,
+	})
+	require.NoError(t, err)
+	starts := make([]time.Time, 0, len(slots))
+	for _, s := range slots {
+		starts = append(starts, s.StartTime)
+	}
+	return starts
+}
+
+func TestSlotsFollowNepalTime(t *testing.T) {
+	f := newBookingFixture()
+
+	// A UTC date still picks the Nepal day and working hours.
+	slots, err := f.uc.ListSlots(context.Background(), f.ram, booking.SlotFilter{
+		InstructorID: &f.instructorID,
+		Date:         f.day.Add(3 * time.Hour).UTC(),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, slots, 3)
+	assert.True(t, f.at(9, 0).Equal(slots[0].StartTime))
+	assert.Equal(t, "03:15", slots[0].StartTime.UTC().Format("15:04"))
+}
+
+func TestHoldsBlockOverlappingSlots(t *testing.T) {
+	f := newBookingFixture()
+	ctx := context.Background()
+
+	held, err := f.hold(f.ram, f.at(10, 0), 60)
+	require.NoError(t, err)
+	assert.True(t, f.at(11, 0).Equal(held.EndTime))
+
+	t.Run("slots of another length that overlap are not offered", func(t *testing.T) {
+		assert.Equal(t, []time.Time{f.at(9, 0), f.at(9, 30), f.at(11, 0), f.at(11, 30)}, f.slotStarts(t, f.sita, 30))
+	})
+
+	t.Run("overlapping holds are refused", func(t *testing.T) {
+		_, err := f.hold(f.sita, f.at(10, 30), 30)
+		assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+
+		_, err = f.hold(f.sita, f.at(10, 0), 60)
+		assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+	})
+
+	t.Run("holds next to each other are fine", func(t *testing.T) {
+		next, err := f.hold(f.sita, f.at(11, 0), 60)
+		require.NoError(t, err)
+		require.NoError(t, f.uc.ReleaseHold(ctx, f.sita, next.ID))
+	})
+
+	t.Run("only the student who holds a slot can release it", func(t *testing.T) {
+		err := f.uc.ReleaseHold(ctx, f.sita, held.ID)
+		assert.Equal(t, http.StatusNotFound, apperrors.GetStatusCode(err))
+	})
+
+	t.Run("releasing a hold frees the time", func(t *testing.T) {
+		require.NoError(t, f.uc.ReleaseHold(ctx, f.ram, held.ID))
+
+		_, err := f.hold(f.sita, f.at(10, 30), 30)
+		require.NoError(t, err)
+		assert.NotContains(t, f.slotStarts(t, f.ram, 60), f.at(10, 0))
+	})
+}
+
+func TestExpiredHoldsFreeTheTime(t *testing.T) {
+	f := newBookingFixture()
+
+	held, err := f.hold(f.ram, f.at(10, 0), 60)
+	require.NoError(t, err)
+
+	// Let the hold run out without touching the instructor's other holds.
+	payload, err := f.cache.Get(context.Background(), instructorHoldsKey(f.instructorID))
+	require.NoError(t, err)
+	var holds []heldSlot
+	require.NoError(t, json.Unmarshal([]byte(payload), &holds))
+	require.Len(t, holds, 1)
+	assert.Equal(t, held.ID, holds[0].HoldID)
+	holds[0].ExpiresAt = time.Now().Add(-time.Second)
+	expired, _ := json.Marshal(holds)
+	f.cache.values[instructorHoldsKey(f.instructorID)] = string(expired)
+
+	_, err = f.hold(f.sita, f.at(10, 30), 30)
+	assert.NoError(t, err)
+}
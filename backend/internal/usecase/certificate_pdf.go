@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// certificateData is what goes on a printed completion certificate.
+type certificateData struct {
+	Institute     string
+	Student       string
+	Course        string
+	CourseCode    string
+	AttendedHours float64
+	StartDate     time.Time
+	CompletedOn   time.Time
+	Serial        string
+	IssuedAt      time.Time
+}
+
+// renderCertificate lays out a single landscape A4 page.
+func renderCertificate(d certificateData) ([]byte, error) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle("Certificate of Completion "+d.Serial, true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	width, height := pdf.GetPageSize()
+
+	pdf.SetLineWidth(1.2)
+	pdf.Rect(10, 10, width-20, height-20, "D")
+	pdf.SetLineWidth(0.3)
+	pdf.Rect(14, 14, width-28, height-28, "D")
+
+	line := func(y float64, size float64, style, text string) {
+		pdf.SetFont("Helvetica", style, size)
+		pdf.SetXY(20, y)
+		pdf.CellFormat(width-40, size*0.6, tr(text), "", 0, "C", false, 0, "")
+	}
+
+	line(32, 18, "B", d.Institute)
+	line(52, 32, "B", "Certificate of Completion")
+	line(76, 13, "", "This is to certify that")
+	line(90, 26, "BI", d.Student)
+	line(112, 13, "", "has successfully completed the course")
+	line(124, 20, "B", fmt.Sprintf("%s (%s)", d.Course, d.CourseCode))
+	line(144, 12, "", fmt.Sprintf("from %s to %s, attending %.1f hours of instruction.",
+		d.StartDate.Format("2 January 2006"), d.CompletedOn.Format("2 January 2006"), d.AttendedHours))
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetXY(24, height-36)
+	pdf.CellFormat(100, 6, tr("Serial No: "+d.Serial), "", 0, "L", false, 0, "")
+	pdf.SetXY(24, height-30)
+	pdf.CellFormat(100, 6, tr("Issued on: "+d.IssuedAt.Format("2 January 2006")), "", 0, "L", false, 0, "")
+
+	pdf.Line(width-104, height-38, width-24, height-38)
+	pdf.SetXY(width-104, height-36)
+	pdf.CellFormat(80, 6, "Authorized Signature", "", 0, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return buf.Bytes(), nil
+}
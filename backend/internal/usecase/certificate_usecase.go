@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/certificate"
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/enrollment"
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/google/uuid"
+)
+
+type CertificateUseCase struct {
+	repo           certificate.Repository
+	studentRepo    student.Repository
+	courseRepo     course.Repository
+	enrollmentRepo enrollment.Repository
+	attendanceRepo attendance.Repository
+	instituteRepo  institute.Repository
+	storage        storage.Storage
+	linkExpiry     time.Duration
+	logger         logger.Logger
+}
+
+func NewCertificateUseCase(
+	repo certificate.Repository,
+	studentRepo student.Repository,
+	courseRepo course.Repository,
+	enrollmentRepo enrollment.Repository,
+	attendanceRepo attendance.Repository,
+	instituteRepo institute.Repository,
+	store storage.Storage,
+	linkExpiry time.Duration,
+	logger logger.Logger,
+) *CertificateUseCase {
+	return &CertificateUseCase{
+		repo:           repo,
+		studentRepo:    studentRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		attendanceRepo: attendanceRepo,
+		instituteRepo:  instituteRepo,
+		storage:        store,
+		linkExpiry:     linkExpiry,
+		logger:         logger,
+	}
+}
+
+// Issue generates a completion certificate once a student enrolled in the
+// course has attended at least its duration in lessons. Each course is
+// certified once.
+func (uc *CertificateUseCase) Issue(ctx context.Context, studentID uuid.UUID, req *certificate.IssueCertificateRequest, issuedBy uuid.UUID) (*certificate.Certificate, error) {
+	stu, err := uc.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	crs, err := uc.courseRepo.GetByID(ctx, req.CourseID)
+	if err != nil {
+		return nil, apperrors.NotFound("course not found")
+	}
+
+	enrolled, err := uc.enrollmentRepo.IsEnrolledInCourse(ctx, studentID, req.CourseID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to check enrollment", err, map[string]interface{}{
+			"student_id": studentID,
+			"course_id":  req.CourseID,
+		})
+		return nil, fmt.Errorf("failed to check enrollment: %w", err)
+	}
+	if !enrolled {
+		return nil, apperrors.BadRequest("student is not enrolled in this course")
+	}
+
+	if existing, _ := uc.repo.FindByStudentAndCourse(ctx, studentID, req.CourseID); existing != nil {
+		return nil, apperrors.Conflict("a certificate has already been issued for this course").WithDetails(map[string]interface{}{
+			"certificate_id": existing.ID,
+			"serial_number":  existing.SerialNumber,
+		})
+	}
+
+	progress, err := uc.attendanceRepo.GetCourseProgress(ctx, studentID, req.CourseID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to get course progress", err, map[string]interface{}{
+			"student_id": studentID,
+			"course_id":  req.CourseID,
+		})
+		return nil, fmt.Errorf("failed to get course progress: %w", err)
+	}
+
+	if progress.LastDate == nil || progress.Hours < float64(crs.Duration) {
+		return nil, apperrors.BadRequest("student has not completed the course hours").WithDetails(map[string]interface{}{
+			"attended_hours": math.Round(progress.Hours*100) / 100,
+			"required_hours": crs.Duration,
+		})
+	}
+
+	inst, err := uc.instituteRepo.FindByID(ctx, stu.InstituteID)
+	if err != nil {
+		return nil, apperrors.NotFound("institute not found")
+	}
+
+	serial, err := uc.repo.NextSerial(ctx)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to allocate certificate serial", err, nil)
+		return nil, fmt.Errorf("failed to allocate certificate serial: %w", err)
+	}
+
+	now := time.Now().UTC()
+	cert := &certificate.Certificate{
+		ID:            uuid.New(),
+		InstituteID:   stu.InstituteID,
+		StudentID:     stu.ID,
+		CourseID:      crs.ID,
+		SerialNumber:  certificateSerial(inst.Code, now, serial),
+		AttendedHours: math.Round(progress.Hours*100) / 100,
+		StartDate:     *progress.FirstDate,
+		CompletedOn:   *progress.LastDate,
+		IssuedBy:      issuedBy,
+		IssuedAt:      now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	cert.FileKey = fmt.Sprintf("certificates/%s/%s.pdf", cert.InstituteID, cert.SerialNumber)
+
+	file, err := renderCertificate(certificateData{
+		Institute:     inst.Name,
+		Student:       stu.FirstName + " " + stu.LastName,
+		Course:        crs.Name,
+		CourseCode:    crs.Code,
+		AttendedHours: cert.AttendedHours,
+		StartDate:     cert.StartDate,
+		CompletedOn:   cert.CompletedOn,
+		Serial:        cert.SerialNumber,
+		IssuedAt:      cert.IssuedAt,
+	})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to render certificate", err, map[string]interface{}{
+			"student_id": studentID,
+		})
+		return nil, err
+	}
+
+	if err := uc.storage.Put(ctx, cert.FileKey, bytes.NewReader(file), int64(len(file)), storage.TypePDF); err != nil {
+		uc.logger.Error(ctx, "failed to store certificate", err, map[string]interface{}{
+			"student_id": studentID,
+		})
+		return nil, fmt.Errorf("failed to store certificate: %w", err)
+	}
+
+	if err := uc.repo.Create(ctx, cert); err != nil {
+		uc.logger.Error(ctx, "failed to create certificate", err, map[string]interface{}{
+			"student_id": studentID,
+			"course_id":  req.CourseID,
+		})
+		uc.storage.Delete(ctx, cert.FileKey)
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	uc.logger.Info(ctx, "certificate issued", map[string]interface{}{
+		"certificate_id": cert.ID,
+		"serial_number":  cert.SerialNumber,
+		"student_id":     cert.StudentID,
+		"course_id":      cert.CourseID,
+	})
+
+	return cert, nil
+}
+
+// certificateSerial formats serials as CODE-YEAR-NNNNNN, e.g. KTM-2024-000042.
+func certificateSerial(instituteCode string, issuedAt time.Time, n int64) string {
+	return fmt.Sprintf("%s-%d-%06d", instituteCode, issuedAt.Year(), n)
+}
+
+func (uc *CertificateUseCase) List(ctx context.Context, studentID uuid.UUID) ([]*certificate.Certificate, error) {
+	if _, err := uc.studentRepo.GetByID(ctx, studentID); err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	certificates, err := uc.repo.ListByStudent(ctx, studentID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list certificates", err, map[string]interface{}{
+			"student_id": studentID,
+		})
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+
+	return certificates, nil
+}
+
+// DownloadURL returns a time-limited link to the certificate PDF.
+func (uc *CertificateUseCase) DownloadURL(ctx context.Context, studentID, id uuid.UUID) (*storage.Link, error) {
+	cert, err := uc.repo.FindByID(ctx, id)
+	if err != nil || cert.StudentID != studentID {
+		return nil, apperrors.NotFound("certificate not found")
+	}
+
+	url, err := uc.storage.SignedURL(ctx, cert.FileKey, uc.linkExpiry)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to sign certificate url", err, map[string]interface{}{
+			"certificate_id": id,
+		})
+		return nil, fmt.Errorf("failed to sign certificate url: %w", err)
+	}
+
+	return &storage.Link{URL: url, ExpiresAt: time.Now().Add(uc.linkExpiry).UTC()}, nil
+}
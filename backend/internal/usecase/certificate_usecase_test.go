@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/certificate"
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/enrollment"
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type certStudentRepo struct {
+	student.Repository
+	stu *student.Student
+}
+
+func (r *certStudentRepo) GetByID(ctx context.Context, id uuid.UUID) (*student.Student, error) {
+	if r.stu == nil || r.stu.ID != id {
+		return nil, errors.New("student not found")
+	}
+	return r.stu, nil
+}
+
+type certCourseRepo struct {
+	course.Repository
+	crs *course.Course
+}
+
+func (r *certCourseRepo) GetByID(ctx context.Context, id uuid.UUID) (*course.Course, error) {
+	if r.crs == nil || r.crs.ID != id {
+		return nil, errors.New("course not found")
+	}
+	return r.crs, nil
+}
+
+type certEnrollmentRepo struct {
+	enrollment.Repository
+	enrolled bool
+}
+
+func (r *certEnrollmentRepo) IsEnrolledInCourse(ctx context.Context, studentID, courseID uuid.UUID) (bool, error) {
+	return r.enrolled, nil
+}
+
+type certAttendanceRepo struct {
+	attendance.Repository
+	progress attendance.CourseProgress
+}
+
+func (r *certAttendanceRepo) GetCourseProgress(ctx context.Context, studentID, courseID uuid.UUID) (*attendance.CourseProgress, error) {
+	p := r.progress
+	return &p, nil
+}
+
+type certInstituteRepo struct {
+	institute.Repository
+	inst *institute.Institute
+}
+
+func (r *certInstituteRepo) FindByID(ctx context.Context, id uuid.UUID) (*institute.Institute, error) {
+	return r.inst, nil
+}
+
+// certRepo fails Create with createErr when set, as the database does when
+// another request issued the certificate first.
+type certRepo struct {
+	certificate.Repository
+	serial    int64
+	issued    []*certificate.Certificate
+	createErr error
+}
+
+func (r *certRepo) NextSerial(ctx context.Context) (int64, error) {
+	r.serial++
+	return r.serial, nil
+}
+
+func (r *certRepo) Create(ctx context.Context, c *certificate.Certificate) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.issued = append(r.issued, c)
+	return nil
+}
+
+func (r *certRepo) FindByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) (*certificate.Certificate, error) {
+	for _, c := range r.issued {
+		if c.StudentID == studentID && c.CourseID == courseID {
+			return c, nil
+		}
+	}
+	return nil, errors.New("certificate not found")
+}
+
+func TestIssueCertificate(t *testing.T) {
+	first := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	completed := attendance.CourseProgress{Lessons: 20, Hours: 20, FirstDate: &first, LastDate: &last}
+
+	tests := []struct {
+		name     string
+		enrolled bool
+		progress attendance.CourseProgress
+		wantCode int
+	}{
+		{name: "completed", enrolled: true, progress: completed},
+		{name: "more hours than required", enrolled: true, progress: attendance.CourseProgress{Lessons: 25, Hours: 24.5, FirstDate: &first, LastDate: &last}},
+		{name: "not enrolled", enrolled: false, progress: completed, wantCode: http.StatusBadRequest},
+		{name: "hours short", enrolled: true, progress: attendance.CourseProgress{Lessons: 19, Hours: 19.75, FirstDate: &first, LastDate: &last}, wantCode: http.StatusBadRequest},
+		{name: "no lessons attended", enrolled: true, progress: attendance.CourseProgress{}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stu := &student.Student{ID: uuid.New(), InstituteID: uuid.New(), FirstName: "Sita", LastName: "Sharma"}
+			crs := &course.Course{ID: uuid.New(), Name: "Car Driving", Code: "CAR", Duration: 20}
+			certs := &certRepo{}
+			store := newMemStorage()
+			uc := NewCertificateUseCase(
+				certs,
+				&certStudentRepo{stu: stu},
+				&certCourseRepo{crs: crs},
+				&certEnrollmentRepo{enrolled: tt.enrolled},
+				&certAttendanceRepo{progress: tt.progress},
+				&certInstituteRepo{inst: &institute.Institute{ID: stu.InstituteID, Name: "Himalayan Driving School", Code: "HDS"}},
+				store,
+				time.Hour,
+				nopLogger{},
+			)
+
+			cert, err := uc.Issue(context.Background(), stu.ID, &certificate.IssueCertificateRequest{CourseID: crs.ID}, uuid.New())
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Empty(t, certs.issued)
+				assert.Empty(t, store.files)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.progress.Hours, cert.AttendedHours)
+			assert.Equal(t, first, cert.StartDate)
+			assert.Equal(t, last, cert.CompletedOn)
+			assert.Regexp(t, `^HDS-\d{4}-000001$`, cert.SerialNumber)
+			assert.True(t, bytes.HasPrefix(store.files[cert.FileKey], []byte("%PDF")))
+
+			_, err = uc.Issue(context.Background(), stu.ID, &certificate.IssueCertificateRequest{CourseID: crs.ID}, uuid.New())
+			assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+		})
+	}
+}
+
+func TestIssueCertificateConflictsWithAConcurrentIssue(t *testing.T) {
+	first := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	stu := &student.Student{ID: uuid.New(), InstituteID: uuid.New(), FirstName: "Sita", LastName: "Sharma"}
+	crs := &course.Course{ID: uuid.New(), Name: "Car Driving", Code: "CAR", Duration: 20}
+	store := newMemStorage()
+	uc := NewCertificateUseCase(
+		&certRepo{createErr: fmt.Errorf("failed to create certificate: %w", apperrors.Conflict("a certificate has already been issued for this course"))},
+		&certStudentRepo{stu: stu},
+		&certCourseRepo{crs: crs},
+		&certEnrollmentRepo{enrolled: true},
+		&certAttendanceRepo{progress: attendance.CourseProgress{Lessons: 20, Hours: 20, FirstDate: &first, LastDate: &first}},
+		&certInstituteRepo{inst: &institute.Institute{ID: stu.InstituteID, Name: "Himalayan Driving School", Code: "HDS"}},
+		store,
+		time.Hour,
+		nopLogger{},
+	)
+
+	_, err := uc.Issue(context.Background(), stu.ID, &certificate.IssueCertificateRequest{CourseID: crs.ID}, uuid.New())
+
+	assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+	assert.Empty(t, store.files, "the rendered file is removed again")
+}
+
+func TestCertificateSerial(t *testing.T) {
+	issued := time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, "HDS-2024-000042", certificateSerial("HDS", issued, 42))
+	assert.Equal(t, "HDS-2024-1234567", certificateSerial("HDS", issued, 1234567))
+}
+
+func TestRenderCertificate(t *testing.T) {
+	data := certificateData{
+		Institute:     "Himalayan Driving School",
+		Student:       "Sita Sharma",
+		Course:        "Car Driving",
+		CourseCode:    "CAR",
+		AttendedHours: 20,
+		StartDate:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		CompletedOn:   time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		Serial:        "HDS-2024-000001",
+		IssuedAt:      time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC),
+	}
+
+	file, err := renderCertificate(data)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(file, []byte("%PDF")))
+
+	data.Student = "सीता शर्मा"
+	file, err = renderCertificate(data)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(file, []byte("%PDF")))
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/auth"
+	"github.com/chalak/backend/pkg/cache"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+// checkInOpensBefore is how early before a lesson starts students can
+// check themselves in.
+const checkInOpensBefore = 30 * time.Minute
+
+// CheckInUseCase runs QR self check-in: the lesson's display shows a
+// rotating signed token, and students scan it to mark themselves in.
+type CheckInUseCase struct {
+	attendanceRepo    attendance.Repository
+	attendanceUseCase *AttendanceUseCase
+	lessonRepo        lesson.Repository
+	studentRepo       student.Repository
+	tokens            *auth.JWTService
+	cache             checkInCache
+	tokenTTL          time.Duration
+	lateAfter         time.Duration
+	logger            logger.Logger
+}
+
+// checkInCache is the part of the Redis cache that used codes are recorded
+// in.
+type checkInCache interface {
+	Exists(ctx context.Context, key string) (bool, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+func NewCheckInUseCase(
+	attendanceRepo attendance.Repository,
+	attendanceUseCase *AttendanceUseCase,
+	lessonRepo lesson.Repository,
+	studentRepo student.Repository,
+	tokens *auth.JWTService,
+	cache *cache.RedisCache,
+	tokenTTL time.Duration,
+	lateAfter time.Duration,
+	logger logger.Logger,
+) *CheckInUseCase {
+	uc := &CheckInUseCase{
+		attendanceRepo:    attendanceRepo,
+		attendanceUseCase: attendanceUseCase,
+		lessonRepo:        lessonRepo,
+		studentRepo:       studentRepo,
+		tokens:            tokens,
+		tokenTTL:          tokenTTL,
+		lateAfter:         lateAfter,
+		logger:            logger,
+	}
+	// Without Redis self check-in is off; a nil *RedisCache must not become
+	// a non-nil checkInCache.
+	if cache != nil {
+		uc.cache = cache
+	}
+	return uc
+}
+
+// GenerateCode issues a fresh check-in token for the session.
+func (uc *CheckInUseCase) GenerateCode(ctx context.Context, sessionID uuid.UUID) (*attendance.CheckInCode, error) {
+	session, err := uc.lessonRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, apperrors.NotFound("class not found")
+	}
+	if err := checkInOpen(session, time.Now()); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := uc.tokens.GenerateCheckInToken(session.ID, uc.tokenTTL)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to generate check-in token", err, map[string]interface{}{
+			"session_id": sessionID,
+		})
+		return nil, fmt.Errorf("failed to generate check-in token: %w", err)
+	}
+
+	return &attendance.CheckInCode{
+		SessionID: session.ID,
+		Token:     token,
+		ExpiresAt: expiresAt.UTC(),
+	}, nil
+}
+
+// GenerateCodePNG issues a fresh check-in token rendered as a QR code.
+func (uc *CheckInUseCase) GenerateCodePNG(ctx context.Context, sessionID uuid.UUID, size int) ([]byte, *attendance.CheckInCode, error) {
+	code, err := uc.GenerateCode(ctx, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	png, err := qrcode.Encode(code.Token, qrcode.Medium, size)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to render check-in code", err, map[string]interface{}{
+			"session_id": sessionID,
+		})
+		return nil, nil, fmt.Errorf("failed to render check-in code: %w", err)
+	}
+
+	return png, code, nil
+}
+
+// CheckIn marks the signed-in student present, or late once the grace
+// period after the lesson start has passed. The whole class can scan the
+// same code, but each student only once. The returned bool is true when a
+// new record was made.
+func (uc *CheckInUseCase) CheckIn(ctx context.Context, userID uuid.UUID, req *attendance.SelfCheckInRequest) (*attendance.Attendance, bool, error) {
+	if uc.cache == nil {
+		return nil, false, apperrors.New(fmt.Errorf("redis unavailable"), "self check-in is unavailable")
+	}
+
+	claims, err := uc.tokens.ValidateCheckInToken(req.Token)
+	if err != nil {
+		return nil, false, apperrors.BadRequest("check-in code is invalid or has expired")
+	}
+
+	stu, err := uc.studentRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, false, apperrors.Forbidden("only students can check themselves in")
+	}
+
+	session, err := uc.lessonRepo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		return nil, false, apperrors.NotFound("class not found")
+	}
+
+	now := time.Now()
+	if err := checkInOpen(session, now); err != nil {
+		return nil, false, err
+	}
+
+	onRoster, err := uc.lessonRepo.HasStudent(ctx, session.ID, stu.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check class roster: %w", err)
+	}
+	if !onRoster {
+		return nil, false, apperrors.Forbidden("student is not on the roster of this class")
+	}
+
+	date := sessionDate(session)
+
+	existing, _, err := uc.attendanceRepo.List(ctx, attendance.AttendanceFilter{
+		StudentID: &stu.ID,
+		ClassID:   &session.ID,
+		DateFrom:  &date,
+		DateTo:    &date,
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check attendance: %w", err)
+	}
+	if len(existing) > 0 && (existing[0].Status == attendance.StatusPresent || existing[0].Status == attendance.StatusLate) {
+		return existing[0], false, nil
+	}
+
+	replayKey := fmt.Sprintf("checkin:used:%s:%s", claims.ID, stu.ID)
+	used, err := uc.cache.Exists(ctx, replayKey)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to check check-in token use", err, map[string]interface{}{
+			"session_id": session.ID,
+		})
+		return nil, false, fmt.Errorf("failed to check check-in token use: %w", err)
+	}
+	if used {
+		return nil, false, apperrors.Conflict("check-in code has already been used")
+	}
+
+	status := checkInStatus(session, now, uc.lateAfter)
+
+	att, created, err := uc.attendanceUseCase.MarkAttendance(ctx, &attendance.MarkAttendanceRequest{
+		StudentID: stu.ID,
+		ClassID:   session.ID,
+		Date:      date,
+		Status:    status,
+		Notes:     "self check-in",
+	}, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Recorded only once the student is marked, so a failed attempt can be
+	// retried with the same code.
+	if err := uc.cache.Set(ctx, replayKey, "1", time.Until(claims.ExpiresAt.Time)+time.Minute); err != nil {
+		uc.logger.Warn(ctx, "failed to record check-in token use", map[string]interface{}{
+			"session_id": session.ID,
+			"error":      err.Error(),
+		})
+	}
+
+	uc.logger.Info(ctx, "student checked in", map[string]interface{}{
+		"session_id": session.ID,
+		"student_id": stu.ID,
+		"status":     status,
+	})
+
+	return att, created, nil
+}
+
+// checkInOpen reports whether students can check in to the session at now.
+func checkInOpen(session *lesson.Session, now time.Time) error {
+	switch {
+	case session.Status == lesson.StatusCancelled:
+		return apperrors.BadRequest("class has been cancelled")
+	case now.Before(session.StartTime.Add(-checkInOpensBefore)):
+		return apperrors.BadRequest("check-in opens 30 minutes before the class starts")
+	case now.After(session.EndTime):
+		return apperrors.BadRequest("class has already ended")
+	}
+	return nil
+}
+
+// checkInStatus is the status a student checking in at now gets: present
+// until lateAfter past the lesson start, late after that.
+func checkInStatus(session *lesson.Session, now time.Time, lateAfter time.Duration) string {
+	if now.After(session.StartTime.Add(lateAfter)) {
+		return attendance.StatusLate
+	}
+	return attendance.StatusPresent
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/auth"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInStatus(t *testing.T) {
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	session := &lesson.Session{StartTime: start, EndTime: start.Add(time.Hour)}
+	lateAfter := 10 * time.Minute
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "before the start", now: start.Add(-20 * time.Minute), want: attendance.StatusPresent},
+		{name: "at the start", now: start, want: attendance.StatusPresent},
+		{name: "at the cutoff", now: start.Add(lateAfter), want: attendance.StatusPresent},
+		{name: "just past the cutoff", now: start.Add(lateAfter + time.Second), want: attendance.StatusLate},
+		{name: "near the end", now: start.Add(55 * time.Minute), want: attendance.StatusLate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, checkInStatus(session, tt.now, lateAfter))
+		})
+	}
+}
+
+func TestCheckInOpen(t *testing.T) {
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		status   string
+		now      time.Time
+		wantCode int
+	}{
+		{name: "when it opens", status: lesson.StatusScheduled, now: start.Add(-checkInOpensBefore)},
+		{name: "during the class", status: lesson.StatusScheduled, now: start.Add(30 * time.Minute)},
+		{name: "at the end", status: lesson.StatusScheduled, now: start.Add(time.Hour)},
+		{name: "too early", status: lesson.StatusScheduled, now: start.Add(-checkInOpensBefore - time.Second), wantCode: http.StatusBadRequest},
+		{name: "after the end", status: lesson.StatusScheduled, now: start.Add(time.Hour + time.Second), wantCode: http.StatusBadRequest},
+		{name: "cancelled", status: lesson.StatusCancelled, now: start, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &lesson.Session{StartTime: start, EndTime: start.Add(time.Hour), Status: tt.status}
+			err := checkInOpen(session, tt.now)
+			if tt.wantCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+		})
+	}
+}
+
+func (c *memCache) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := c.Get(ctx, key)
+	return err == nil, nil
+}
+
+// flakyAttendanceRepo fails the next Upsert when fail is set.
+type flakyAttendanceRepo struct {
+	*memAttendanceRepo
+	fail bool
+}
+
+func (r *flakyAttendanceRepo) Upsert(ctx context.Context, att *attendance.Attendance) (bool, error) {
+	if r.fail {
+		r.fail = false
+		return false, errors.New("connection reset")
+	}
+	return r.memAttendanceRepo.Upsert(ctx, att)
+}
+
+func TestCheckInUsesEachCodeOncePerStudent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	session := &lesson.Session{ID: uuid.New(), StartTime: now.Add(-5 * time.Minute), EndTime: now.Add(time.Hour), Status: lesson.StatusScheduled}
+	students := &lessonStudentRepo{students: map[uuid.UUID]*student.Student{}}
+	enrol := func() uuid.UUID {
+		id, userID := uuid.New(), uuid.New()
+		students.students[id] = &student.Student{ID: id, UserID: &userID}
+		session.StudentIDs = append(session.StudentIDs, id)
+		return userID
+	}
+	ram, sita := enrol(), enrol()
+
+	records := &flakyAttendanceRepo{memAttendanceRepo: newMemAttendanceRepo()}
+	sessions := &memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{session.ID: session}}
+	tokens := auth.NewJWTService("test-secret", time.Hour, 24*time.Hour)
+	uc := NewCheckInUseCase(records, NewAttendanceUseCase(records, sessions, nopLogger{}), sessions, students, tokens, nil, time.Minute, 10*time.Minute, nopLogger{})
+	uc.cache = newMemCache()
+
+	token, _, err := tokens.GenerateCheckInToken(session.ID, time.Minute)
+	require.NoError(t, err)
+	checkIn := func(userID uuid.UUID) (*attendance.Attendance, error) {
+		att, _, err := uc.CheckIn(ctx, userID, &attendance.SelfCheckInRequest{Token: token})
+		return att, err
+	}
+
+	t.Run("a failed check-in can be retried with the same code", func(t *testing.T) {
+		records.fail = true
+		_, err := checkIn(ram)
+		require.Error(t, err)
+
+		att, err := checkIn(ram)
+		require.NoError(t, err)
+		assert.Equal(t, attendance.StatusPresent, att.Status)
+	})
+
+	t.Run("classmates scan the same code", func(t *testing.T) {
+		_, err := checkIn(sita)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a student cannot reuse the code", func(t *testing.T) {
+		// The instructor marked Ram absent after all.
+		for _, att := range records.records {
+			if *students.students[att.StudentID].UserID == ram {
+				att.Status = attendance.StatusAbsent
+			}
+		}
+
+		_, err := checkIn(ram)
+		assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+	})
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/document"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/google/uuid"
+)
+
+type DocumentUseCase struct {
+	repo          document.Repository
+	studentRepo   student.Repository
+	storage       storage.Storage
+	maxUploadSize int64
+	linkExpiry    time.Duration
+	logger        logger.Logger
+}
+
+func NewDocumentUseCase(
+	repo document.Repository,
+	studentRepo student.Repository,
+	store storage.Storage,
+	maxUploadSize int64,
+	linkExpiry time.Duration,
+	logger logger.Logger,
+) *DocumentUseCase {
+	return &DocumentUseCase{
+		repo:          repo,
+		studentRepo:   studentRepo,
+		storage:       store,
+		maxUploadSize: maxUploadSize,
+		linkExpiry:    linkExpiry,
+		logger:        logger,
+	}
+}
+
+// MaxUploadSize is the largest document file accepted, in bytes.
+func (uc *DocumentUseCase) MaxUploadSize() int64 {
+	return uc.maxUploadSize
+}
+
+// Upload stores a student document. A new upload replaces the student's
+// current document of the same type.
+func (uc *DocumentUseCase) Upload(ctx context.Context, studentID uuid.UUID, docType string, upload *storage.Upload, uploadedBy uuid.UUID) (*document.Document, error) {
+	if !document.ValidType(docType) {
+		return nil, apperrors.BadRequest("document type must be one of: citizenship, photo, learner_license")
+	}
+
+	stu, err := uc.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	allowed := []string{storage.TypeJPEG, storage.TypePNG, storage.TypePDF}
+	if docType == document.TypePhoto {
+		allowed = []string{storage.TypeJPEG, storage.TypePNG}
+	}
+
+	contentType, body, err := inspectUpload(upload, uc.maxUploadSize, allowed...)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &document.Document{
+		ID:          uuid.New(),
+		InstituteID: stu.InstituteID,
+		StudentID:   stu.ID,
+		Type:        docType,
+		FileName:    upload.FileName,
+		ContentType: contentType,
+		Size:        upload.Size,
+		UploadedBy:  uploadedBy,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	doc.FileKey = fmt.Sprintf("students/%s/%s/%s%s", stu.ID, docType, doc.ID, storage.Extension(contentType))
+
+	if err := uc.storage.Put(ctx, doc.FileKey, body, upload.Size, contentType); err != nil {
+		uc.logger.Error(ctx, "failed to store document", err, map[string]interface{}{
+			"student_id": studentID,
+			"type":       docType,
+		})
+		return nil, fmt.Errorf("failed to store document: %w", err)
+	}
+
+	previous, _ := uc.repo.FindByStudentAndType(ctx, studentID, docType)
+
+	if err := uc.repo.Create(ctx, doc); err != nil {
+		uc.logger.Error(ctx, "failed to create document", err, map[string]interface{}{
+			"student_id": studentID,
+			"type":       docType,
+		})
+		uc.storage.Delete(ctx, doc.FileKey)
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+
+	if previous != nil {
+		uc.remove(ctx, previous)
+	}
+
+	uc.logger.Info(ctx, "document uploaded", map[string]interface{}{
+		"document_id": doc.ID,
+		"student_id":  doc.StudentID,
+		"type":        doc.Type,
+	})
+
+	return doc, nil
+}
+
+func (uc *DocumentUseCase) List(ctx context.Context, studentID uuid.UUID) ([]*document.Document, error) {
+	if _, err := uc.studentRepo.GetByID(ctx, studentID); err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	documents, err := uc.repo.ListByStudent(ctx, studentID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list documents", err, map[string]interface{}{
+			"student_id": studentID,
+		})
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	return documents, nil
+}
+
+// DownloadURL returns a time-limited link to the document's file.
+func (uc *DocumentUseCase) DownloadURL(ctx context.Context, studentID, id uuid.UUID) (*storage.Link, error) {
+	doc, err := uc.repo.FindByID(ctx, id)
+	if err != nil || doc.StudentID != studentID {
+		return nil, apperrors.NotFound("document not found")
+	}
+
+	url, err := uc.storage.SignedURL(ctx, doc.FileKey, uc.linkExpiry)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to sign document url", err, map[string]interface{}{
+			"document_id": id,
+		})
+		return nil, fmt.Errorf("failed to sign document url: %w", err)
+	}
+
+	return &storage.Link{URL: url, ExpiresAt: time.Now().Add(uc.linkExpiry).UTC()}, nil
+}
+
+func (uc *DocumentUseCase) Delete(ctx context.Context, studentID, id uuid.UUID) error {
+	doc, err := uc.repo.FindByID(ctx, id)
+	if err != nil || doc.StudentID != studentID {
+		return apperrors.NotFound("document not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete document", err, map[string]interface{}{
+			"document_id": id,
+		})
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+
+	if err := uc.storage.Delete(ctx, doc.FileKey); err != nil {
+		uc.logger.Warn(ctx, "failed to delete document file", map[string]interface{}{
+			"document_id": id,
+			"error":       err.Error(),
+		})
+	}
+
+	uc.logger.Info(ctx, "document deleted", map[string]interface{}{
+		"document_id": id,
+	})
+
+	return nil
+}
+
+// remove retires a replaced document. Failures are only logged since the
+// replacement is already in place.
+func (uc *DocumentUseCase) remove(ctx context.Context, doc *document.Document) {
+	if err := uc.repo.Delete(ctx, doc.ID); err != nil {
+		uc.logger.Warn(ctx, "failed to retire replaced document", map[string]interface{}{
+			"document_id": doc.ID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	if err := uc.storage.Delete(ctx, doc.FileKey); err != nil {
+		uc.logger.Warn(ctx, "failed to delete replaced document file", map[string]interface{}{
+			"document_id": doc.ID,
+			"error":       err.Error(),
+		})
+	}
+}
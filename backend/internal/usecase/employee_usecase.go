@@ -38,6 +38,7 @@ func (uc *EmployeeUseCase) Create(ctx context.Context, req *employee.CreateEmplo
 		Position:    req.Position,
 		Department:  req.Department,
 		InstituteID: req.InstituteID,
+		UserID:      req.UserID,
 		Salary:      req.Salary,
 		HireDate:    req.HireDate,
 		Status:      employee.StatusActive,
@@ -107,6 +108,9 @@ func (uc *EmployeeUseCase) Update(ctx context.Context, id uuid.UUID, req *employ
 	if req.Address != nil {
 		emp.Address = *req.Address
 	}
+	if req.UserID != nil {
+		emp.UserID = req.UserID
+	}
 
 	emp.UpdatedAt = time.Now().UTC()
 
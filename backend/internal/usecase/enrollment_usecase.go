@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/enrollment"
+	"github.com/chalak/backend/internal/domain/invoice"
+	pkg "github.com/chalak/backend/internal/domain/package"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/internal/domain/transaction"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+type EnrollmentUseCase struct {
+	repo           enrollment.Repository
+	studentRepo    student.Repository
+	packageRepo    pkg.Repository
+	courseRepo     course.Repository
+	invoiceUseCase *InvoiceUseCase
+	tx             transaction.Manager
+	logger         logger.Logger
+}
+
+func NewEnrollmentUseCase(
+	repo enrollment.Repository,
+	studentRepo student.Repository,
+	packageRepo pkg.Repository,
+	courseRepo course.Repository,
+	invoiceUseCase *InvoiceUseCase,
+	tx transaction.Manager,
+	logger logger.Logger,
+) *EnrollmentUseCase {
+	return &EnrollmentUseCase{
+		repo:           repo,
+		studentRepo:    studentRepo,
+		packageRepo:    packageRepo,
+		courseRepo:     courseRepo,
+		invoiceUseCase: invoiceUseCase,
+		tx:             tx,
+		logger:         logger,
+	}
+}
+
+// Create enrolls the student and bills the enrollment with a new invoice,
+// taking off any promo code the request brings. Both are saved in one
+// transaction, so a failed enrollment takes its invoice, the invoice number
+// and the promo code redemption with it.
+func (uc *EnrollmentUseCase) Create(ctx context.Context, req *enrollment.CreateEnrollmentRequest, createdBy uuid.UUID) (*enrollment.Enrollment, error) {
+	s, err := uc.studentRepo.GetByID(ctx, req.StudentID)
+	if err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	e := &enrollment.Enrollment{
+		ID:          uuid.New(),
+		StudentID:   s.ID,
+		InstituteID: s.InstituteID,
+		StartDate:   req.StartDate,
+		Status:      enrollment.StatusActive,
+		Notes:       req.Notes,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	var description string
+	switch {
+	case req.PackageID != nil && req.CourseID == nil:
+		p, err := uc.packageRepo.GetByID(ctx, *req.PackageID)
+		if err != nil {
+			return nil, apperrors.NotFound("package not found")
+		}
+		if !p.IsActive {
+			return nil, apperrors.BadRequest("package is not active")
+		}
+
+		endDate := req.StartDate.AddDate(0, 0, p.Duration)
+		e.PackageID = &p.ID
+		e.ExpectedEndDate = &endDate
+		e.Price = p.Price
+		e.DiscountPercentage = p.DiscountPercentage
+		description = fmt.Sprintf("Package: %s", p.Name)
+	case req.CourseID != nil && req.PackageID == nil:
+		c, err := uc.courseRepo.GetByID(ctx, *req.CourseID)
+		if err != nil {
+			return nil, apperrors.NotFound("course not found")
+		}
+		if !c.IsActive {
+			return nil, apperrors.BadRequest("course is not active")
+		}
+
+		e.CourseID = &c.ID
+		e.Price = c.Fee
+		description = fmt.Sprintf("Course: %s", c.Name)
+	default:
+		return nil, apperrors.BadRequest("exactly one of package_id or course_id is required")
+	}
+
+	e.Amount = discountedPrice(e.Price, e.DiscountPercentage)
+	if e.DiscountPercentage > 0 {
+		description = fmt.Sprintf("%s (%.2f%% discount)", description, e.DiscountPercentage)
+	}
+
+	dueDate := req.StartDate
+	if req.DueDate != nil {
+		dueDate = *req.DueDate
+	}
+
+	var inv *invoice.Invoice
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		inv, err = uc.invoiceUseCase.Create(ctx, &invoice.CreateInvoiceRequest{
+			StudentID:   s.ID,
+			InstituteID: s.InstituteID,
+			DueDate:     dueDate,
+			Notes:       fmt.Sprintf("Enrollment %s", e.ID),
+			PromoCode:   req.PromoCode,
+			Items: []invoice.CreateInvoiceItem{
+				{
+					Description: description,
+					Quantity:    1,
+					UnitPrice:   e.Amount,
+					PackageID:   e.PackageID,
+					CourseID:    e.CourseID,
+				},
+			},
+		}, createdBy)
+		if err != nil {
+			return err
+		}
+		e.InvoiceID = &inv.ID
+
+		if err := uc.repo.Create(ctx, e); err != nil {
+			uc.logger.Error(ctx, "failed to create enrollment", err, map[string]interface{}{
+				"student_id": s.ID,
+			})
+			return fmt.Errorf("failed to create enrollment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info(ctx, "enrollment created", map[string]interface{}{
+		"enrollment_id": e.ID,
+		"student_id":    e.StudentID,
+		"invoice_id":    inv.ID,
+	})
+
+	return e, nil
+}
+
+func (uc *EnrollmentUseCase) GetByID(ctx context.Context, id uuid.UUID) (*enrollment.Enrollment, error) {
+	e, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("enrollment not found")
+	}
+	return e, nil
+}
+
+func (uc *EnrollmentUseCase) Update(ctx context.Context, id uuid.UUID, req *enrollment.UpdateEnrollmentRequest) (*enrollment.Enrollment, error) {
+	e, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("enrollment not found")
+	}
+
+	if req.Status != nil && *req.Status != e.Status {
+		if e.Status != enrollment.StatusActive {
+			return nil, apperrors.BadRequest("only active enrollments can change status")
+		}
+		e.Status = *req.Status
+	}
+	if req.ExpectedEndDate != nil {
+		if req.ExpectedEndDate.Before(e.StartDate) {
+			return nil, apperrors.BadRequest("expected end date cannot be before start date")
+		}
+		e.ExpectedEndDate = req.ExpectedEndDate
+	}
+	if req.Notes != nil {
+		e.Notes = *req.Notes
+	}
+
+	e.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, e); err != nil {
+		uc.logger.Error(ctx, "failed to update enrollment", err, map[string]interface{}{
+			"enrollment_id": id,
+		})
+		return nil, fmt.Errorf("failed to update enrollment: %w", err)
+	}
+
+	uc.logger.Info(ctx, "enrollment updated", map[string]interface{}{
+		"enrollment_id": e.ID,
+		"status":        e.Status,
+	})
+
+	return e, nil
+}
+
+func (uc *EnrollmentUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return apperrors.NotFound("enrollment not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete enrollment", err, map[string]interface{}{
+			"enrollment_id": id,
+		})
+		return fmt.Errorf("failed to delete enrollment: %w", err)
+	}
+
+	uc.logger.Info(ctx, "enrollment deleted", map[string]interface{}{
+		"enrollment_id": id,
+	})
+
+	return nil
+}
+
+func (uc *EnrollmentUseCase) List(ctx context.Context, filter enrollment.EnrollmentFilter) ([]*enrollment.Enrollment, int64, error) {
+	enrollments, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list enrollments", err, nil)
+		return nil, 0, fmt.Errorf("failed to list enrollments: %w", err)
+	}
+
+	return enrollments, total, nil
+}
+
+// discountedPrice applies a percentage discount and rounds to the nearest
+// paisa.
+func discountedPrice(price money.Amount, discountPercentage float64) money.Amount {
+	return price.Percent(100 - discountPercentage)
+}
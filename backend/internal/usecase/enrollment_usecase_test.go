@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/enrollment"
+	"github.com/chalak/backend/internal/domain/institute"
+	pkg "github.com/chalak/backend/internal/domain/package"
+	"github.com/chalak/backend/internal/domain/promo"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memEnrollmentRepo keeps enrollments in a map.
+type memEnrollmentRepo struct {
+	enrollment.Repository
+	enrollments map[uuid.UUID]enrollment.Enrollment
+	createErr   error
+}
+
+func (r *memEnrollmentRepo) snapshot() func() {
+	saved := make(map[uuid.UUID]enrollment.Enrollment, len(r.enrollments))
+	for id, e := range r.enrollments {
+		saved[id] = e
+	}
+	return func() { r.enrollments = saved }
+}
+
+func (r *memEnrollmentRepo) Create(ctx context.Context, e *enrollment.Enrollment) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.enrollments[e.ID] = *e
+	return nil
+}
+
+type enrollmentPackageRepo struct {
+	pkg.Repository
+	p *pkg.Package
+}
+
+func (r *enrollmentPackageRepo) GetByID(ctx context.Context, id uuid.UUID) (*pkg.Package, error) {
+	if r.p == nil || r.p.ID != id {
+		return nil, errors.New("package not found")
+	}
+	return r.p, nil
+}
+
+// memPromoRepo holds a set of codes and the redemptions made of them.
+type memPromoRepo struct {
+	promo.Repository
+	codes       []*promo.PromoCode
+	redemptions []promo.Redemption
+}
+
+func (r *memPromoRepo) snapshot() func() {
+	saved := append([]promo.Redemption(nil), r.redemptions...)
+	return func() { r.redemptions = saved }
+}
+
+func (r *memPromoRepo) FindByCodeForUpdate(ctx context.Context, instituteID uuid.UUID, code string) (*promo.PromoCode, error) {
+	for _, pc := range r.codes {
+		if pc.InstituteID == instituteID && strings.EqualFold(pc.Code, code) {
+			return pc, nil
+		}
+	}
+	return nil, errors.New("promo code not found")
+}
+
+func (r *memPromoRepo) CountRedemptions(ctx context.Context, promoCodeID uuid.UUID, studentID *uuid.UUID) (int, error) {
+	n := 0
+	for _, red := range r.redemptions {
+		if red.PromoCodeID == promoCodeID && (studentID == nil || red.StudentID == *studentID) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memPromoRepo) CreateRedemption(ctx context.Context, redemption *promo.Redemption) error {
+	r.redemptions = append(r.redemptions, *redemption)
+	return nil
+}
+
+type enrollmentFixture struct {
+	uc          *EnrollmentUseCase
+	tx          *memTx
+	invoices    *memInvoiceRepo
+	enrollments *memEnrollmentRepo
+	student     *student.Student
+}
+
+func newEnrollmentFixture(p *pkg.Package, c *course.Course) *enrollmentFixture {
+	inst := &institute.Institute{ID: uuid.New(), Code: "KTM"}
+	f := &enrollmentFixture{
+		invoices:    newMemInvoiceRepo(),
+		enrollments: &memEnrollmentRepo{enrollments: map[uuid.UUID]enrollment.Enrollment{}},
+		student:     &student.Student{ID: uuid.New(), InstituteID: inst.ID},
+	}
+	f.tx = newMemTx(f.invoices, f.enrollments)
+	invoiceUC := NewInvoiceUseCase(f.invoices, &certInstituteRepo{inst: inst}, nil, nil, nil, nil, f.tx,
+		InvoiceNumbering{Format: "{seq}", StartMonth: 4, StartDay: 1}, nopLogger{})
+	f.uc = NewEnrollmentUseCase(f.enrollments, &certStudentRepo{stu: f.student}, &enrollmentPackageRepo{p: p},
+		&certCourseRepo{crs: c}, invoiceUC, f.tx, nopLogger{})
+	return f
+}
+
+func TestCreateEnrollment(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	bundle := &pkg.Package{ID: uuid.New(), Name: "Car license", Duration: 60, Price: money.FromMinor(2000000), DiscountPercentage: 10, IsActive: true}
+	closed := &pkg.Package{ID: uuid.New(), Name: "Old package", Price: money.FromMinor(2000000)}
+	crs := &course.Course{ID: uuid.New(), Name: "Motorbike", Fee: money.FromMinor(1000000), IsActive: true}
+	other := uuid.New()
+
+	tests := []struct {
+		name      string
+		pkg       *pkg.Package
+		req       enrollment.CreateEnrollmentRequest
+		wantCode  int
+		wantTotal money.Amount
+	}{
+		{name: "package at its discount", pkg: bundle, req: enrollment.CreateEnrollmentRequest{PackageID: &bundle.ID}, wantTotal: money.FromMinor(1800000)},
+		{name: "course", pkg: bundle, req: enrollment.CreateEnrollmentRequest{CourseID: &crs.ID}, wantTotal: money.FromMinor(1000000)},
+		{name: "inactive package", pkg: closed, req: enrollment.CreateEnrollmentRequest{PackageID: &closed.ID}, wantCode: http.StatusBadRequest},
+		{name: "unknown course", pkg: bundle, req: enrollment.CreateEnrollmentRequest{CourseID: &other}, wantCode: http.StatusNotFound},
+		{name: "package and course", pkg: bundle, req: enrollment.CreateEnrollmentRequest{PackageID: &bundle.ID, CourseID: &crs.ID}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEnrollmentFixture(tt.pkg, crs)
+			req := tt.req
+			req.StudentID = f.student.ID
+			req.StartDate = start
+
+			e, err := f.uc.Create(context.Background(), &req, uuid.New())
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Empty(t, f.invoices.invoices)
+				assert.Empty(t, f.enrollments.enrollments)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, e.InvoiceID)
+			assert.Contains(t, f.enrollments.enrollments, e.ID)
+			inv := f.invoices.invoices[*e.InvoiceID]
+			require.NotNil(t, inv)
+			assert.Equal(t, tt.wantTotal, inv.TotalAmount)
+			assert.Equal(t, tt.wantTotal, e.Amount)
+			assert.Equal(t, f.student.ID, inv.StudentID)
+			assert.Equal(t, start, inv.DueDate)
+			if req.PackageID != nil {
+				assert.Equal(t, start.AddDate(0, 0, bundle.Duration), *e.ExpectedEndDate)
+			}
+			assert.Equal(t, 1, f.tx.commits)
+		})
+	}
+}
+
+func TestCreateEnrollmentTakesItsInvoiceWithItWhenItFails(t *testing.T) {
+	crs := &course.Course{ID: uuid.New(), Name: "Motorbike", Fee: money.FromMinor(1000000), IsActive: true}
+	f := newEnrollmentFixture(nil, crs)
+	f.enrollments.createErr = errors.New("connection reset")
+
+	_, err := f.uc.Create(context.Background(), &enrollment.CreateEnrollmentRequest{
+		StudentID: f.student.ID,
+		CourseID:  &crs.ID,
+		StartDate: time.Now(),
+	}, uuid.New())
+
+	require.Error(t, err)
+	assert.Equal(t, 1, f.tx.rollbacks)
+	assert.Empty(t, f.invoices.invoices, "the invoice must not outlive the failed enrollment")
+	assert.Empty(t, f.enrollments.enrollments)
+}
+
+func TestCreateEnrollmentRedeemsPromoCodes(t *testing.T) {
+	inst := &institute.Institute{ID: uuid.New(), Code: "KTM"}
+	stu := &student.Student{ID: uuid.New(), InstituteID: inst.ID}
+	crs := &course.Course{ID: uuid.New(), Name: "Motorbike", Fee: money.FromMinor(1000000), IsActive: true}
+	bundle := &pkg.Package{ID: uuid.New(), Name: "Car license", Duration: 60, Price: money.FromMinor(2000000), IsActive: true}
+	courseCode := &promo.PromoCode{ID: uuid.New(), InstituteID: inst.ID, Code: "BIKE10", DiscountType: promo.DiscountPercentage, Percentage: 10, IsActive: true,
+		Targets: []promo.Target{{CourseID: &crs.ID}}}
+	packageCode := &promo.PromoCode{ID: uuid.New(), InstituteID: inst.ID, Code: "CAR5K", DiscountType: promo.DiscountFixed, Amount: money.FromMinor(500000), IsActive: true,
+		Targets: []promo.Target{{PackageID: &bundle.ID}}}
+
+	tests := []struct {
+		name      string
+		req       enrollment.CreateEnrollmentRequest
+		wantCode  int
+		wantTotal money.Amount
+	}{
+		{name: "course code on a course", req: enrollment.CreateEnrollmentRequest{CourseID: &crs.ID, PromoCode: "bike10"}, wantTotal: money.FromMinor(900000)},
+		{name: "package code on a package", req: enrollment.CreateEnrollmentRequest{PackageID: &bundle.ID, PromoCode: "CAR5K"}, wantTotal: money.FromMinor(1500000)},
+		{name: "no code", req: enrollment.CreateEnrollmentRequest{CourseID: &crs.ID}, wantTotal: money.FromMinor(1000000)},
+		{name: "package code on a course", req: enrollment.CreateEnrollmentRequest{CourseID: &crs.ID, PromoCode: "CAR5K"}, wantCode: http.StatusBadRequest},
+		{name: "unknown code", req: enrollment.CreateEnrollmentRequest{CourseID: &crs.ID, PromoCode: "NOPE"}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices := newMemInvoiceRepo()
+			enrollments := &memEnrollmentRepo{enrollments: map[uuid.UUID]enrollment.Enrollment{}}
+			promos := &memPromoRepo{codes: []*promo.PromoCode{courseCode, packageCode}}
+			tx := newMemTx(invoices, enrollments, promos)
+			invoiceUC := NewInvoiceUseCase(invoices, &certInstituteRepo{inst: inst}, nil, nil, promos, nil, tx,
+				InvoiceNumbering{Format: "{seq}", StartMonth: 4, StartDay: 1}, nopLogger{})
+			uc := NewEnrollmentUseCase(enrollments, &certStudentRepo{stu: stu}, &enrollmentPackageRepo{p: bundle},
+				&certCourseRepo{crs: crs}, invoiceUC, tx, nopLogger{})
+
+			req := tt.req
+			req.StudentID = stu.ID
+			req.StartDate = time.Now()
+			e, err := uc.Create(context.Background(), &req, uuid.New())
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Empty(t, invoices.invoices)
+				assert.Empty(t, enrollments.enrollments)
+				assert.Empty(t, promos.redemptions)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, e.InvoiceID)
+			inv := invoices.invoices[*e.InvoiceID]
+			require.NotNil(t, inv)
+			assert.Equal(t, tt.wantTotal, inv.TotalAmount)
+			assert.Equal(t, req.PackageID, inv.Items[0].PackageID)
+			assert.Equal(t, req.CourseID, inv.Items[0].CourseID)
+			if req.PromoCode == "" {
+				assert.Nil(t, inv.PromoCodeID)
+				assert.Empty(t, promos.redemptions)
+				return
+			}
+			require.Len(t, promos.redemptions, 1)
+			assert.Equal(t, inv.ID, promos.redemptions[0].InvoiceID)
+			assert.Equal(t, inv.DiscountAmount, promos.redemptions[0].Amount)
+		})
+	}
+}
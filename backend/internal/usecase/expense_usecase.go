@@ -3,26 +3,47 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/chalak/backend/internal/domain/expense"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/chalak/backend/pkg/tenant"
 	"github.com/google/uuid"
 )
 
 type ExpenseUseCase struct {
-	repo   expense.Repository
-	logger logger.Logger
+	repo          expense.Repository
+	storage       storage.Storage
+	maxUploadSize int64
+	linkExpiry    time.Duration
+	logger        logger.Logger
 }
 
-func NewExpenseUseCase(repo expense.Repository, logger logger.Logger) *ExpenseUseCase {
+func NewExpenseUseCase(
+	repo expense.Repository,
+	store storage.Storage,
+	maxUploadSize int64,
+	linkExpiry time.Duration,
+	logger logger.Logger,
+) *ExpenseUseCase {
 	return &ExpenseUseCase{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		storage:       store,
+		maxUploadSize: maxUploadSize,
+		linkExpiry:    linkExpiry,
+		logger:        logger,
 	}
 }
 
+// MaxUploadSize is the largest receipt file accepted, in bytes.
+func (uc *ExpenseUseCase) MaxUploadSize() int64 {
+	return uc.maxUploadSize
+}
+
 func (uc *ExpenseUseCase) Create(ctx context.Context, req *expense.CreateExpenseRequest, createdBy uuid.UUID) (*expense.Expense, error) {
 	exp := &expense.Expense{
 		ID:          uuid.New(),
@@ -187,7 +208,98 @@ func (uc *ExpenseUseCase) Reject(ctx context.Context, id uuid.UUID, rejectedBy u
 	return nil
 }
 
-func (uc *ExpenseUseCase) GetTotalExpenses(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (float64, error) {
+// UploadReceipt stores a scanned receipt for a pending expense, replacing
+// any receipt uploaded before.
+func (uc *ExpenseUseCase) UploadReceipt(ctx context.Context, id uuid.UUID, upload *storage.Upload) (*expense.Expense, error) {
+	exp, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("expense not found")
+	}
+
+	if exp.Status != expense.StatusPending {
+		return nil, apperrors.BadRequest("receipts can only be attached to pending expenses")
+	}
+
+	contentType, body, err := inspectUpload(upload, uc.maxUploadSize, storage.TypeJPEG, storage.TypePNG, storage.TypePDF)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%s%s", receiptPrefix(exp), uuid.New(), storage.Extension(contentType))
+	if err := uc.storage.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		uc.logger.Error(ctx, "failed to store receipt", err, map[string]interface{}{
+			"expense_id": id,
+		})
+		return nil, fmt.Errorf("failed to store receipt: %w", err)
+	}
+
+	previous := exp.Receipt
+	exp.Receipt = key
+	exp.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, exp); err != nil {
+		uc.logger.Error(ctx, "failed to update expense", err, map[string]interface{}{
+			"expense_id": id,
+		})
+		uc.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to update expense: %w", err)
+	}
+
+	if strings.HasPrefix(previous, receiptPrefix(exp)) {
+		if err := uc.storage.Delete(ctx, previous); err != nil {
+			uc.logger.Warn(ctx, "failed to delete replaced receipt", map[string]interface{}{
+				"expense_id": id,
+				"error":      err.Error(),
+			})
+		}
+	}
+
+	uc.logger.Info(ctx, "expense receipt uploaded", map[string]interface{}{
+		"expense_id": exp.ID,
+	})
+
+	return exp, nil
+}
+
+// ReceiptURL returns a time-limited link to the expense's receipt. Receipts
+// recorded as plain links before uploads existed are returned unchanged.
+func (uc *ExpenseUseCase) ReceiptURL(ctx context.Context, id uuid.UUID) (*storage.Link, error) {
+	exp, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("expense not found")
+	}
+
+	if exp.Receipt == "" {
+		return nil, apperrors.NotFound("expense has no receipt")
+	}
+
+	if !strings.HasPrefix(exp.Receipt, receiptPrefix(exp)) {
+		return &storage.Link{URL: exp.Receipt}, nil
+	}
+
+	url, err := uc.storage.SignedURL(ctx, exp.Receipt, uc.linkExpiry)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to sign receipt url", err, map[string]interface{}{
+			"expense_id": id,
+		})
+		return nil, fmt.Errorf("failed to sign receipt url: %w", err)
+	}
+
+	return &storage.Link{URL: url, ExpiresAt: time.Now().Add(uc.linkExpiry).UTC()}, nil
+}
+
+// receiptPrefix is where an expense's uploaded receipts are kept. Only keys
+// under it are signed, so a receipt value typed in by a client cannot point
+// at another expense's file.
+func receiptPrefix(exp *expense.Expense) string {
+	return fmt.Sprintf("receipts/%s/%s/", exp.InstituteID, exp.ID)
+}
+
+func (uc *ExpenseUseCase) GetTotalExpenses(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (money.Amount, error) {
+	if !tenant.CanAccess(ctx, instituteID) {
+		return 0, apperrors.NotFound("institute not found")
+	}
+
 	total, err := uc.repo.GetTotalExpenses(ctx, instituteID, dateFrom, dateTo)
 	if err != nil {
 		uc.logger.Error(ctx, "failed to get total expenses", err, map[string]interface{}{
@@ -199,7 +311,11 @@ func (uc *ExpenseUseCase) GetTotalExpenses(ctx context.Context, instituteID uuid
 	return total, nil
 }
 
-func (uc *ExpenseUseCase) GetExpensesByCategory(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (map[string]float64, error) {
+func (uc *ExpenseUseCase) GetExpensesByCategory(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (map[string]money.Amount, error) {
+	if !tenant.CanAccess(ctx, instituteID) {
+		return nil, apperrors.NotFound("institute not found")
+	}
+
 	expenses, err := uc.repo.GetExpensesByCategory(ctx, instituteID, dateFrom, dateTo)
 	if err != nil {
 		uc.logger.Error(ctx, "failed to get expenses by category", err, map[string]interface{}{
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/google/uuid"
+)
+
+// Test doubles shared by the usecase tests. Fakes embed the interface they
+// stand in for, so a test that reaches a method it did not expect panics
+// instead of passing silently.
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(ctx context.Context, msg string, fields map[string]interface{})            {}
+func (nopLogger) Info(ctx context.Context, msg string, fields map[string]interface{})             {}
+func (nopLogger) Warn(ctx context.Context, msg string, fields map[string]interface{})             {}
+func (nopLogger) Error(ctx context.Context, msg string, err error, fields map[string]interface{}) {}
+func (nopLogger) Fatal(ctx context.Context, msg string, err error, fields map[string]interface{}) {}
+
+// memStorage keeps files in a map.
+type memStorage struct {
+	files map[string][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{files: map[string][]byte{}}
+}
+
+func (s *memStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.files[key] = b
+	return nil
+}
+
+func (s *memStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := s.files[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *memStorage) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "https://files.test/" + key, nil
+}
+
+// memTx stands in for transaction.Manager over in-memory repositories. When
+// the outermost transaction fails it puts every store back the way it was,
+// as a database rollback would; nested calls join the outer transaction.
+type memTx struct {
+	stores    []txStore
+	commits   int
+	rollbacks int
+}
+
+// txStore is an in-memory repository memTx can roll back. snapshot returns
+// a function that restores the store to its current state.
+type txStore interface {
+	snapshot() func()
+}
+
+type memTxKey struct{}
+
+func newMemTx(stores ...txStore) *memTx {
+	return &memTx{stores: stores}
+}
+
+func (m *memTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return fn(ctx)
+	}
+
+	restores := make([]func(), len(m.stores))
+	for i, s := range m.stores {
+		restores[i] = s.snapshot()
+	}
+	if err := fn(context.WithValue(ctx, memTxKey{}, m)); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		m.rollbacks++
+		return err
+	}
+	m.commits++
+	return nil
+}
+
+// inTx reports whether ctx belongs to a memTx transaction.
+func inTx(ctx context.Context) bool {
+	return ctx.Value(memTxKey{}) != nil
+}
+
+// memInvoiceRepo keeps invoices in a map and hands out copies, so changes a
+// usecase makes only stick once it calls Update. onLock, when set, runs as
+// an invoice is locked, standing in for whatever committed while the caller
+// waited for the lock.
+type memInvoiceRepo struct {
+	invoice.Repository
+	invoices  map[uuid.UUID]*invoice.Invoice
+	locked    []uuid.UUID
+	onLock    func()
+	updateErr error
+	issued    int64
+}
+
+func newMemInvoiceRepo(invoices ...*invoice.Invoice) *memInvoiceRepo {
+	r := &memInvoiceRepo{invoices: map[uuid.UUID]*invoice.Invoice{}}
+	for _, inv := range invoices {
+		r.invoices[inv.ID] = inv
+	}
+	return r
+}
+
+func (r *memInvoiceRepo) snapshot() func() {
+	saved := make(map[uuid.UUID]invoice.Invoice, len(r.invoices))
+	for id, inv := range r.invoices {
+		saved[id] = *inv
+	}
+	return func() {
+		r.invoices = make(map[uuid.UUID]*invoice.Invoice, len(saved))
+		for id, inv := range saved {
+			inv := inv
+			r.invoices[id] = &inv
+		}
+	}
+}
+
+func (r *memInvoiceRepo) Create(ctx context.Context, inv *invoice.Invoice) error {
+	copied := *inv
+	r.invoices[inv.ID] = &copied
+	return nil
+}
+
+func (r *memInvoiceRepo) NextNumber(ctx context.Context, instituteID uuid.UUID, fiscalYear string) (int64, error) {
+	r.issued++
+	return r.issued, nil
+}
+
+func (r *memInvoiceRepo) FindByID(ctx context.Context, id uuid.UUID) (*invoice.Invoice, error) {
+	inv, ok := r.invoices[id]
+	if !ok {
+		return nil, errors.New("invoice not found")
+	}
+	copied := *inv
+	return &copied, nil
+}
+
+func (r *memInvoiceRepo) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*invoice.Invoice, error) {
+	if !inTx(ctx) {
+		return nil, errors.New("invoice locked outside a transaction")
+	}
+	r.locked = append(r.locked, id)
+	if r.onLock != nil {
+		r.onLock()
+	}
+	return r.FindByID(ctx, id)
+}
+
+func (r *memInvoiceRepo) Update(ctx context.Context, inv *invoice.Invoice) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	copied := *inv
+	r.invoices[inv.ID] = &copied
+	return nil
+}
+
+// memPaymentRepo keeps payments in a map.
+type memPaymentRepo struct {
+	payment.Repository
+	payments map[uuid.UUID]*payment.Payment
+}
+
+func newMemPaymentRepo(payments ...*payment.Payment) *memPaymentRepo {
+	r := &memPaymentRepo{payments: map[uuid.UUID]*payment.Payment{}}
+	for _, p := range payments {
+		r.payments[p.ID] = p
+	}
+	return r
+}
+
+func (r *memPaymentRepo) snapshot() func() {
+	saved := make(map[uuid.UUID]*payment.Payment, len(r.payments))
+	for id, p := range r.payments {
+		saved[id] = p
+	}
+	return func() { r.payments = saved }
+}
+
+func (r *memPaymentRepo) Create(ctx context.Context, p *payment.Payment) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	copied := *p
+	r.payments[p.ID] = &copied
+	return nil
+}
+
+func (r *memPaymentRepo) GetByID(ctx context.Context, id uuid.UUID) (*payment.Payment, error) {
+	p, ok := r.payments[id]
+	if !ok {
+		return nil, errors.New("payment not found")
+	}
+	copied := *p
+	return &copied, nil
+}
@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/installment"
+	"github.com/chalak/backend/internal/domain/invoice"
+	pkg "github.com/chalak/backend/internal/domain/package"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/internal/domain/transaction"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+type InstallmentUseCase struct {
+	repo           installment.Repository
+	invoiceUseCase *InvoiceUseCase
+	studentRepo    student.Repository
+	packageRepo    pkg.Repository
+	tx             transaction.Manager
+	leadTime       time.Duration
+	logger         logger.Logger
+}
+
+// NewInstallmentUseCase issues installment invoices leadTime ahead of their
+// due date, unless the plan asks for all of them up front.
+func NewInstallmentUseCase(
+	repo installment.Repository,
+	invoiceUseCase *InvoiceUseCase,
+	studentRepo student.Repository,
+	packageRepo pkg.Repository,
+	tx transaction.Manager,
+	leadTime time.Duration,
+	logger logger.Logger,
+) *InstallmentUseCase {
+	return &InstallmentUseCase{
+		repo:           repo,
+		invoiceUseCase: invoiceUseCase,
+		studentRepo:    studentRepo,
+		packageRepo:    packageRepo,
+		tx:             tx,
+		leadTime:       leadTime,
+		logger:         logger,
+	}
+}
+
+// Create sets up a plan and issues the invoices that are already due for
+// issuing: every one of them for an upfront plan, otherwise those falling
+// due within the lead time. The rest are left to IssueDueInvoices. The plan
+// total is what the student pays, tax included; a package's price is taxed
+// as it would be on an invoice of its own.
+func (uc *InstallmentUseCase) Create(ctx context.Context, req *installment.CreatePlanRequest, createdBy uuid.UUID) (*installment.Plan, error) {
+	instituteID := req.InstituteID
+	if instituteID == uuid.Nil {
+		instituteID, _ = tenant.InstituteID(ctx)
+	}
+
+	if _, err := uc.studentRepo.GetByID(ctx, req.StudentID); err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	description, total := req.Description, req.TotalAmount
+	if req.PackageID != nil {
+		p, err := uc.packageRepo.GetByID(ctx, *req.PackageID)
+		if err != nil {
+			return nil, apperrors.NotFound("package not found")
+		}
+		if description == "" {
+			description = p.Name
+		}
+		if total == 0 {
+			total, err = uc.invoiceUseCase.withTax(ctx, instituteID, p.Price-p.Price.Percent(p.DiscountPercentage))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if description == "" {
+		return nil, apperrors.BadRequest("description is required without a package")
+	}
+	if total.Minor() < int64(req.Installments) {
+		return nil, apperrors.BadRequest("total_amount is too small to split into installments")
+	}
+
+	dueDates, err := installmentDueDates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	plan := &installment.Plan{
+		ID:               uuid.New(),
+		InstituteID:      instituteID,
+		StudentID:        req.StudentID,
+		PackageID:        req.PackageID,
+		Description:      description,
+		TotalAmount:      total,
+		InstallmentCount: req.Installments,
+		InvoiceUpfront:   req.InvoiceUpfront,
+		Status:           installment.StatusActive,
+		CreatedBy:        createdBy,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	for i, amount := range splitInstallments(total, req.Installments) {
+		plan.Installments = append(plan.Installments, installment.Installment{
+			ID:        uuid.New(),
+			PlanID:    plan.ID,
+			Sequence:  i + 1,
+			Amount:    amount,
+			DueDate:   dueDates[i],
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	issueBefore := uc.issueBefore(now)
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.Create(ctx, plan); err != nil {
+			return err
+		}
+		for i := range plan.Installments {
+			inst := &plan.Installments[i]
+			if plan.InvoiceUpfront || inst.DueDate.Before(issueBefore) {
+				if err := uc.issue(ctx, plan, inst); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to create installment plan", err, map[string]interface{}{
+			"student_id": req.StudentID,
+		})
+		return nil, fmt.Errorf("failed to create installment plan: %w", err)
+	}
+
+	uc.logger.Info(ctx, "installment plan created", map[string]interface{}{
+		"plan_id":      plan.ID,
+		"student_id":   plan.StudentID,
+		"total_amount": plan.TotalAmount,
+		"installments": plan.InstallmentCount,
+	})
+
+	return uc.GetByID(ctx, plan.ID)
+}
+
+func (uc *InstallmentUseCase) GetByID(ctx context.Context, id uuid.UUID) (*installment.Plan, error) {
+	plan, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("installment plan not found")
+	}
+	plan.Progress = planProgress(plan)
+	return plan, nil
+}
+
+func (uc *InstallmentUseCase) ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*installment.Plan, error) {
+	if _, err := uc.studentRepo.GetByID(ctx, studentID); err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	plans, err := uc.repo.ListByStudent(ctx, studentID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list installment plans", err, map[string]interface{}{
+			"student_id": studentID,
+		})
+		return nil, fmt.Errorf("failed to list installment plans: %w", err)
+	}
+
+	for _, plan := range plans {
+		plan.Progress = planProgress(plan)
+	}
+	return plans, nil
+}
+
+// Cancel stops a plan issuing any more invoices. Invoices already issued are
+// left as they are; a credit note takes them off if they are not owed.
+func (uc *InstallmentUseCase) Cancel(ctx context.Context, id uuid.UUID) (*installment.Plan, error) {
+	plan, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("installment plan not found")
+	}
+
+	if plan.Status == installment.StatusCanceled {
+		return nil, apperrors.BadRequest("installment plan already canceled")
+	}
+
+	plan.Status = installment.StatusCanceled
+	plan.UpdatedAt = time.Now().UTC()
+	if err := uc.repo.Update(ctx, plan); err != nil {
+		uc.logger.Error(ctx, "failed to cancel installment plan", err, map[string]interface{}{
+			"plan_id": id,
+		})
+		return nil, fmt.Errorf("failed to cancel installment plan: %w", err)
+	}
+
+	uc.logger.Info(ctx, "installment plan canceled", map[string]interface{}{
+		"plan_id": id,
+	})
+
+	plan.Progress = planProgress(plan)
+	return plan, nil
+}
+
+// IssueDueInvoices issues the invoices of installments falling due within
+// the lead time across all institutes. It is run daily from the queue and
+// returns how many invoices were issued. A failed installment is logged and
+// left for the next run rather than holding up the others.
+func (uc *InstallmentUseCase) IssueDueInvoices(ctx context.Context) (int, error) {
+	plans, err := uc.repo.FindUninvoiced(ctx, uc.issueBefore(time.Now().UTC()))
+	if err != nil {
+		uc.logger.Error(ctx, "failed to find uninvoiced installments", err, nil)
+		return 0, fmt.Errorf("failed to find uninvoiced installments: %w", err)
+	}
+
+	issued := 0
+	for _, plan := range plans {
+		for i := range plan.Installments {
+			inst := &plan.Installments[i]
+			err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				return uc.issue(ctx, plan, inst)
+			})
+			if err != nil {
+				uc.logger.Error(ctx, "failed to issue installment invoice", err, map[string]interface{}{
+					"plan_id":        plan.ID,
+					"installment_id": inst.ID,
+				})
+				continue
+			}
+			issued++
+		}
+	}
+
+	if issued > 0 {
+		uc.logger.Info(ctx, "installment invoices issued", map[string]interface{}{
+			"count": issued,
+		})
+	}
+	return issued, nil
+}
+
+// issue creates the invoice for an installment. It must run inside a
+// transaction; the installment row is locked so a sweep and a request racing
+// on the same installment cannot both invoice it. The installment amount
+// already includes tax, so the invoice works the tax out of it rather than
+// adding more and the plan's invoices add up to its total.
+func (uc *InstallmentUseCase) issue(ctx context.Context, plan *installment.Plan, inst *installment.Installment) error {
+	locked, err := uc.repo.FindInstallmentForUpdate(ctx, inst.ID)
+	if err != nil {
+		return err
+	}
+	if locked.InvoiceID != nil {
+		return nil
+	}
+
+	label := fmt.Sprintf("%s (installment %d of %d)", plan.Description, inst.Sequence, plan.InstallmentCount)
+	inv, err := uc.invoiceUseCase.Create(ctx, &invoice.CreateInvoiceRequest{
+		StudentID:   plan.StudentID,
+		InstituteID: plan.InstituteID,
+		DueDate:     inst.DueDate,
+		Items: []invoice.CreateInvoiceItem{
+			{Description: label, Quantity: 1, UnitPrice: inst.Amount, PackageID: plan.PackageID},
+		},
+		TaxInclusive: true,
+	}, plan.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	locked.InvoiceID = &inv.ID
+	locked.UpdatedAt = time.Now().UTC()
+	if err := uc.repo.UpdateInstallment(ctx, locked); err != nil {
+		return err
+	}
+	inst.InvoiceID = locked.InvoiceID
+	return nil
+}
+
+// issueBefore is the due date from which installments are not yet invoiced.
+func (uc *InstallmentUseCase) issueBefore(now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.Add(uc.leadTime).AddDate(0, 0, 1)
+}
+
+// installmentDueDates returns one due date per installment, taken from the
+// request's explicit schedule or counted on monthly from the first.
+func installmentDueDates(req *installment.CreatePlanRequest) ([]time.Time, error) {
+	if len(req.DueDates) > 0 {
+		if len(req.DueDates) != req.Installments {
+			return nil, apperrors.BadRequest("due_dates must have one date per installment")
+		}
+		for i := 1; i < len(req.DueDates); i++ {
+			if !req.DueDates[i].After(req.DueDates[i-1]) {
+				return nil, apperrors.BadRequest("due_dates must be in order")
+			}
+		}
+		return req.DueDates, nil
+	}
+
+	if req.FirstDueDate.IsZero() {
+		return nil, apperrors.BadRequest("first_due_date or due_dates is required")
+	}
+	interval := req.IntervalMonths
+	if interval == 0 {
+		interval = 1
+	}
+
+	dates := make([]time.Time, req.Installments)
+	for i := range dates {
+		dates[i] = addMonths(req.FirstDueDate, i*interval)
+	}
+	return dates, nil
+}
+
+// addMonths moves t on by n calendar months, keeping to the last day of a
+// shorter month rather than spilling into the next one.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// splitInstallments divides total into n equal parts, with any paisa left
+// over going on the last installment.
+func splitInstallments(total money.Amount, n int) []money.Amount {
+	share := money.FromMinor(total.Minor() / int64(n))
+	parts := make([]money.Amount, n)
+	for i := range parts {
+		parts[i] = share
+	}
+	parts[n-1] = total - share.Mul(n-1)
+	return parts
+}
+
+// planProgress works out what has been paid and what is left from the
+// invoices issued so far, counting installments not yet invoiced at their
+// scheduled amount. Canceled plans have nothing further due.
+func planProgress(plan *installment.Plan) *installment.Progress {
+	p := &installment.Progress{}
+	settled := 0
+	for i := range plan.Installments {
+		inst := &plan.Installments[i]
+
+		var due money.Amount
+		switch inv := inst.Invoice; {
+		case inv != nil:
+			p.PaidAmount += inv.PaidAmount
+			if inv.Status == invoice.StatusPaid {
+				p.InstallmentsPaid++
+			}
+			if inv.Status == invoice.StatusPaid || inv.Status == invoice.StatusCanceled {
+				settled++
+				continue
+			}
+			due = inv.Balance()
+		case plan.Status == installment.StatusCanceled:
+			continue
+		default:
+			due = inst.Amount
+		}
+
+		if due <= 0 {
+			continue
+		}
+		p.RemainingAmount += due
+		if p.NextDueDate == nil {
+			date, amount := inst.DueDate, due
+			p.NextDueDate, p.NextDueAmount = &date, &amount
+		}
+	}
+	p.Completed = settled == len(plan.Installments)
+	return p
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/installment"
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/invoice"
+	pkg "github.com/chalak/backend/internal/domain/package"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallmentSchedule(t *testing.T) {
+	parts := splitInstallments(money.FromMinor(1000001), 3)
+	assert.Equal(t, []money.Amount{333333, 333333, 333335}, parts)
+
+	dates, err := installmentDueDates(&installment.CreatePlanRequest{
+		Installments: 4,
+		FirstDueDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+	}, dates)
+
+	_, err = installmentDueDates(&installment.CreatePlanRequest{
+		Installments: 2,
+		DueDates:     []time.Time{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	assert.Error(t, err)
+}
+
+func TestPlanProgress(t *testing.T) {
+	second := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	plan := &installment.Plan{
+		Status: installment.StatusActive,
+		Installments: []installment.Installment{
+			{Sequence: 1, Amount: 5000, Invoice: &invoice.Invoice{TotalAmount: 5000, PaidAmount: 5000, Status: invoice.StatusPaid}},
+			{Sequence: 2, Amount: 5000, DueDate: second, Invoice: &invoice.Invoice{TotalAmount: 5000, PaidAmount: 2000, Status: invoice.StatusPending}},
+			{Sequence: 3, Amount: 5000, DueDate: second.AddDate(0, 1, 0)},
+		},
+	}
+
+	p := planProgress(plan)
+	assert.Equal(t, money.Amount(7000), p.PaidAmount)
+	assert.Equal(t, money.Amount(8000), p.RemainingAmount)
+	assert.Equal(t, 1, p.InstallmentsPaid)
+	assert.Equal(t, second, *p.NextDueDate)
+	assert.Equal(t, money.Amount(3000), *p.NextDueAmount)
+	assert.False(t, p.Completed)
+
+	plan.Status = installment.StatusCanceled
+	assert.Equal(t, money.Amount(3000), planProgress(plan).RemainingAmount)
+}
+
+// memInstallmentRepo keeps plans in a map and loads their invoices from an
+// invoice fake, as the real FindByID preloads them.
+type memInstallmentRepo struct {
+	installment.Repository
+	plans    map[uuid.UUID]installment.Plan
+	invoices *memInvoiceRepo
+}
+
+func (r *memInstallmentRepo) Create(ctx context.Context, plan *installment.Plan) error {
+	copied := *plan
+	copied.Installments = append([]installment.Installment(nil), plan.Installments...)
+	r.plans[plan.ID] = copied
+	return nil
+}
+
+func (r *memInstallmentRepo) FindByID(ctx context.Context, id uuid.UUID) (*installment.Plan, error) {
+	plan, ok := r.plans[id]
+	if !ok {
+		return nil, errors.New("installment plan not found")
+	}
+	plan.Installments = append([]installment.Installment(nil), plan.Installments...)
+	for i := range plan.Installments {
+		if id := plan.Installments[i].InvoiceID; id != nil {
+			plan.Installments[i].Invoice, _ = r.invoices.FindByID(ctx, *id)
+		}
+	}
+	return &plan, nil
+}
+
+func (r *memInstallmentRepo) FindInstallmentForUpdate(ctx context.Context, id uuid.UUID) (*installment.Installment, error) {
+	for _, plan := range r.plans {
+		for _, inst := range plan.Installments {
+			if inst.ID == id {
+				return &inst, nil
+			}
+		}
+	}
+	return nil, errors.New("installment not found")
+}
+
+func (r *memInstallmentRepo) UpdateInstallment(ctx context.Context, inst *installment.Installment) error {
+	plan := r.plans[inst.PlanID]
+	for i := range plan.Installments {
+		if plan.Installments[i].ID == inst.ID {
+			plan.Installments[i] = *inst
+		}
+	}
+	return nil
+}
+
+func TestInstallmentInvoicesAddUpToThePlanTotal(t *testing.T) {
+	stu := &student.Student{ID: uuid.New()}
+	course := &pkg.Package{ID: uuid.New(), Name: "Car license", Price: money.FromMinor(2500001), DiscountPercentage: 10}
+
+	tests := []struct {
+		name      string
+		tax       institute.TaxConfig
+		packageID *uuid.UUID
+		total     money.Amount
+		wantTotal money.Amount
+	}{
+		// 10% off 25,000.01 is 22,500.01; 13% on top is 2,925.00.
+		{name: "package under exclusive tax", tax: institute.TaxConfig{Name: "VAT", Rate: 13}, packageID: &course.ID, wantTotal: money.FromMinor(2542501)},
+		{name: "package under inclusive tax", tax: institute.TaxConfig{Name: "VAT", Rate: 13, Inclusive: true}, packageID: &course.ID, wantTotal: money.FromMinor(2250001)},
+		{name: "agreed total under exclusive tax", tax: institute.TaxConfig{Name: "VAT", Rate: 13}, total: money.FromMinor(1000000), wantTotal: money.FromMinor(1000000)},
+		{name: "untaxed", total: money.FromMinor(1000000), wantTotal: money.FromMinor(1000000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &institute.Institute{ID: uuid.New(), Code: "KTM", Tax: tt.tax}
+			invoices := newMemInvoiceRepo()
+			tx := newMemTx(invoices)
+			invoiceUC := NewInvoiceUseCase(invoices, &certInstituteRepo{inst: inst}, nil, nil, nil, nil, tx,
+				InvoiceNumbering{Format: "{seq}", StartMonth: 4, StartDay: 1}, nopLogger{})
+			plans := &memInstallmentRepo{plans: map[uuid.UUID]installment.Plan{}, invoices: invoices}
+			uc := NewInstallmentUseCase(plans, invoiceUC, &certStudentRepo{stu: stu}, &enrollmentPackageRepo{p: course}, tx, 0, nopLogger{})
+
+			plan, err := uc.Create(context.Background(), &installment.CreatePlanRequest{
+				StudentID:      stu.ID,
+				InstituteID:    inst.ID,
+				PackageID:      tt.packageID,
+				Description:    "Driving course",
+				TotalAmount:    tt.total,
+				Installments:   3,
+				FirstDueDate:   time.Now().AddDate(0, 1, 0),
+				InvoiceUpfront: true,
+			}, uuid.New())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, plan.TotalAmount)
+
+			var invoiced money.Amount
+			for _, i := range plan.Installments {
+				require.NotNil(t, i.Invoice)
+				assert.Equal(t, i.Amount, i.Invoice.TotalAmount)
+				assert.Equal(t, i.Invoice.Amount+i.Invoice.TaxAmount, i.Invoice.TotalAmount)
+				invoiced += i.Invoice.TotalAmount
+			}
+			assert.Equal(t, plan.TotalAmount, invoiced)
+			assert.Equal(t, plan.TotalAmount, plan.Progress.RemainingAmount)
+		})
+	}
+}
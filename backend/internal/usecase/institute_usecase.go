@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+type InstituteUseCase struct {
+	repo          institute.Repository
+	storage       storage.Storage
+	maxUploadSize int64
+	logger        logger.Logger
+}
+
+func NewInstituteUseCase(repo institute.Repository, store storage.Storage, maxUploadSize int64, logger logger.Logger) *InstituteUseCase {
+	return &InstituteUseCase{
+		repo:          repo,
+		storage:       store,
+		maxUploadSize: maxUploadSize,
+		logger:        logger,
+	}
+}
+
+// MaxUploadSize is the largest logo file accepted, in bytes.
+func (uc *InstituteUseCase) MaxUploadSize() int64 {
+	return uc.maxUploadSize
+}
+
+func (uc *InstituteUseCase) Create(ctx context.Context, req *institute.CreateInstituteRequest) (*institute.Institute, error) {
+	if _, scoped := tenant.InstituteID(ctx); scoped {
+		return nil, apperrors.Forbidden("only platform administrators can create institutes")
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+
+	if existing, _ := uc.repo.FindByCode(ctx, code); existing != nil {
+		return nil, apperrors.Conflict("institute with this code already exists")
+	}
+
+	inst := &institute.Institute{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Code:      code,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Address:   req.Address,
+		Status:    institute.StatusActive,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := uc.repo.Create(ctx, inst); err != nil {
+		uc.logger.Error(ctx, "failed to create institute", err, map[string]interface{}{
+			"code": code,
+		})
+		return nil, fmt.Errorf("failed to create institute: %w", err)
+	}
+
+	uc.logger.Info(ctx, "institute created", map[string]interface{}{
+		"institute_id": inst.ID,
+		"code":         inst.Code,
+	})
+
+	return inst, nil
+}
+
+func (uc *InstituteUseCase) GetByID(ctx context.Context, id uuid.UUID) (*institute.Institute, error) {
+	inst, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("institute not found")
+	}
+	return inst, nil
+}
+
+func (uc *InstituteUseCase) Update(ctx context.Context, id uuid.UUID, req *institute.UpdateInstituteRequest) (*institute.Institute, error) {
+	// An institute's status is the platform's to manage; its own admins
+	// only edit its details.
+	if _, scoped := tenant.InstituteID(ctx); scoped && req.Status != nil {
+		return nil, apperrors.Forbidden("only platform administrators can change an institute's status")
+	}
+
+	inst, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("institute not found")
+	}
+
+	if req.Name != nil {
+		inst.Name = *req.Name
+	}
+	if req.Email != nil {
+		inst.Email = *req.Email
+	}
+	if req.Phone != nil {
+		inst.Phone = *req.Phone
+	}
+	if req.Address != nil {
+		inst.Address = *req.Address
+	}
+	if req.Status != nil {
+		inst.Status = *req.Status
+	}
+	if req.Tax != nil {
+		applyTaxConfig(&inst.Tax, req.Tax)
+		inst.TaxExemptions = taxExemptions(inst, req.Tax)
+	}
+	if req.Branding != nil && req.Branding.Footer != nil {
+		inst.Branding.Footer = *req.Branding.Footer
+	}
+
+	inst.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, inst); err != nil {
+		uc.logger.Error(ctx, "failed to update institute", err, map[string]interface{}{
+			"institute_id": id,
+		})
+		return nil, fmt.Errorf("failed to update institute: %w", err)
+	}
+
+	uc.logger.Info(ctx, "institute updated", map[string]interface{}{
+		"institute_id": inst.ID,
+	})
+
+	return inst, nil
+}
+
+// UploadLogo stores the logo printed at the top of the institute's invoices,
+// replacing any previous one.
+func (uc *InstituteUseCase) UploadLogo(ctx context.Context, id uuid.UUID, upload *storage.Upload) (*institute.Institute, error) {
+	inst, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("institute not found")
+	}
+
+	contentType, body, err := inspectUpload(upload, uc.maxUploadSize, storage.TypePNG, storage.TypeJPEG)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%s%s", logoPrefix(inst), uuid.New(), storage.Extension(contentType))
+	if err := uc.storage.Put(ctx, key, body, upload.Size, contentType); err != nil {
+		uc.logger.Error(ctx, "failed to store institute logo", err, map[string]interface{}{
+			"institute_id": id,
+		})
+		return nil, fmt.Errorf("failed to store institute logo: %w", err)
+	}
+
+	previous := inst.Branding.LogoKey
+	inst.Branding.LogoKey = key
+	inst.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, inst); err != nil {
+		uc.logger.Error(ctx, "failed to update institute", err, map[string]interface{}{
+			"institute_id": id,
+		})
+		uc.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to update institute: %w", err)
+	}
+
+	if previous != "" {
+		if err := uc.storage.Delete(ctx, previous); err != nil {
+			uc.logger.Warn(ctx, "failed to delete replaced institute logo", map[string]interface{}{
+				"institute_id": id,
+				"error":        err.Error(),
+			})
+		}
+	}
+
+	uc.logger.Info(ctx, "institute logo uploaded", map[string]interface{}{
+		"institute_id": inst.ID,
+	})
+
+	return inst, nil
+}
+
+func logoPrefix(inst *institute.Institute) string {
+	return fmt.Sprintf("institutes/%s/logo-", inst.ID)
+}
+
+func (uc *InstituteUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, scoped := tenant.InstituteID(ctx); scoped {
+		return apperrors.Forbidden("only platform administrators can delete institutes")
+	}
+
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return apperrors.NotFound("institute not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete institute", err, map[string]interface{}{
+			"institute_id": id,
+		})
+		return fmt.Errorf("failed to delete institute: %w", err)
+	}
+
+	uc.logger.Info(ctx, "institute deleted", map[string]interface{}{
+		"institute_id": id,
+	})
+
+	return nil
+}
+
+func (uc *InstituteUseCase) List(ctx context.Context, filter institute.InstituteFilter) ([]*institute.Institute, int64, error) {
+	institutes, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list institutes", err, nil)
+		return nil, 0, fmt.Errorf("failed to list institutes: %w", err)
+	}
+
+	return institutes, total, nil
+}
+
+func applyTaxConfig(tax *institute.TaxConfig, req *institute.UpdateTaxConfigRequest) {
+	if req.Name != nil {
+		tax.Name = *req.Name
+	}
+	if req.Rate != nil {
+		tax.Rate = *req.Rate
+	}
+	if req.Inclusive != nil {
+		tax.Inclusive = *req.Inclusive
+	}
+	if req.Number != nil {
+		tax.Number = *req.Number
+	}
+}
+
+// taxExemptions is the institute's exemptions with those of each kind the
+// request names replaced.
+func taxExemptions(inst *institute.Institute, req *institute.UpdateTaxConfigRequest) []institute.TaxExemption {
+	var exemptions []institute.TaxExemption
+	for _, e := range inst.TaxExemptions {
+		if (e.PackageID != nil && req.ExemptPackageIDs == nil) || (e.CourseID != nil && req.ExemptCourseIDs == nil) {
+			exemptions = append(exemptions, e)
+		}
+	}
+	if req.ExemptPackageIDs != nil {
+		for i := range *req.ExemptPackageIDs {
+			exemptions = append(exemptions, institute.TaxExemption{ID: uuid.New(), InstituteID: inst.ID, PackageID: &(*req.ExemptPackageIDs)[i]})
+		}
+	}
+	if req.ExemptCourseIDs != nil {
+		for i := range *req.ExemptCourseIDs {
+			exemptions = append(exemptions, institute.TaxExemption{ID: uuid.New(), InstituteID: inst.ID, CourseID: &(*req.ExemptCourseIDs)[i]})
+		}
+	}
+	return exemptions
+}
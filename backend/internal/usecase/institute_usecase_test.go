@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memInstituteRepo keeps institutes in a map and, like the database
+// repository, hides other institutes from tenant-scoped callers.
+type memInstituteRepo struct {
+	institute.Repository
+	institutes map[uuid.UUID]*institute.Institute
+}
+
+func (r *memInstituteRepo) Create(ctx context.Context, inst *institute.Institute) error {
+	r.institutes[inst.ID] = inst
+	return nil
+}
+
+func (r *memInstituteRepo) FindByID(ctx context.Context, id uuid.UUID) (*institute.Institute, error) {
+	inst, ok := r.institutes[id]
+	if !ok || !tenant.CanAccess(ctx, id) {
+		return nil, errors.New("institute not found")
+	}
+	return inst, nil
+}
+
+func (r *memInstituteRepo) FindByCode(ctx context.Context, code string) (*institute.Institute, error) {
+	for _, inst := range r.institutes {
+		if inst.Code == code {
+			return inst, nil
+		}
+	}
+	return nil, errors.New("institute not found")
+}
+
+func (r *memInstituteRepo) Update(ctx context.Context, inst *institute.Institute) error {
+	r.institutes[inst.ID] = inst
+	return nil
+}
+
+func (r *memInstituteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.institutes, id)
+	return nil
+}
+
+func TestInstituteCRUD(t *testing.T) {
+	repo := &memInstituteRepo{institutes: map[uuid.UUID]*institute.Institute{}}
+	uc := NewInstituteUseCase(repo, newMemStorage(), 1<<20, nopLogger{})
+	platform := context.Background()
+
+	inst, err := uc.Create(platform, &institute.CreateInstituteRequest{Name: "Himalayan Driving School", Code: " hds "})
+	require.NoError(t, err)
+	assert.Equal(t, "HDS", inst.Code)
+	assert.Equal(t, institute.StatusActive, inst.Status)
+
+	_, err = uc.Create(platform, &institute.CreateInstituteRequest{Name: "Another", Code: "HDS"})
+	assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+
+	own := tenant.WithInstituteID(platform, inst.ID)
+	name := "Himalayan Driving Academy"
+	updated, err := uc.Update(own, inst.ID, &institute.UpdateInstituteRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, name, updated.Name)
+
+	got, err := uc.GetByID(own, inst.ID)
+	require.NoError(t, err)
+	assert.Equal(t, name, got.Name)
+
+	inactive := institute.StatusInactive
+	updated, err = uc.Update(platform, inst.ID, &institute.UpdateInstituteRequest{Status: &inactive})
+	require.NoError(t, err)
+	assert.Equal(t, institute.StatusInactive, updated.Status)
+
+	require.NoError(t, uc.Delete(platform, inst.ID))
+	_, err = uc.GetByID(platform, inst.ID)
+	assert.Equal(t, http.StatusNotFound, apperrors.GetStatusCode(err))
+}
+
+func TestInstituteAdminsCannotManageTheirInstitute(t *testing.T) {
+	inst := &institute.Institute{ID: uuid.New(), Name: "Himalayan Driving School", Code: "HDS", Status: institute.StatusActive}
+	other := &institute.Institute{ID: uuid.New(), Name: "Valley Driving School", Code: "VDS", Status: institute.StatusActive}
+	repo := &memInstituteRepo{institutes: map[uuid.UUID]*institute.Institute{inst.ID: inst, other.ID: other}}
+	uc := NewInstituteUseCase(repo, newMemStorage(), 1<<20, nopLogger{})
+	own := tenant.WithInstituteID(context.Background(), inst.ID)
+	inactive := institute.StatusInactive
+
+	_, err := uc.Create(own, &institute.CreateInstituteRequest{Name: "Branch", Code: "BR"})
+	assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+
+	_, err = uc.Update(own, inst.ID, &institute.UpdateInstituteRequest{Status: &inactive})
+	assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+
+	assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(uc.Delete(own, inst.ID)))
+
+	name := "Taken over"
+	_, err = uc.Update(own, other.ID, &institute.UpdateInstituteRequest{Name: &name})
+	assert.Equal(t, http.StatusNotFound, apperrors.GetStatusCode(err))
+
+	assert.Equal(t, institute.StatusActive, inst.Status)
+	assert.Equal(t, "Valley Driving School", other.Name)
+	assert.Len(t, repo.institutes, 2)
+}
+
+func TestUpdateTaxExemptions(t *testing.T) {
+	inst := &institute.Institute{ID: uuid.New(), Name: "Himalayan Driving School", Code: "HDS", Status: institute.StatusActive}
+	repo := &memInstituteRepo{institutes: map[uuid.UUID]*institute.Institute{inst.ID: inst}}
+	uc := NewInstituteUseCase(repo, newMemStorage(), 1<<20, nopLogger{})
+	ctx := context.Background()
+	examFee, trialCourse, drivingCourse := uuid.New(), uuid.New(), uuid.New()
+
+	got, err := uc.Update(ctx, inst.ID, &institute.UpdateInstituteRequest{Tax: &institute.UpdateTaxConfigRequest{
+		ExemptPackageIDs: &[]uuid.UUID{examFee},
+		ExemptCourseIDs:  &[]uuid.UUID{trialCourse},
+	}})
+	require.NoError(t, err)
+	assert.True(t, got.TaxExempt(&examFee, nil))
+	assert.True(t, got.TaxExempt(nil, &trialCourse))
+	assert.False(t, got.TaxExempt(nil, &drivingCourse))
+	assert.False(t, got.TaxExempt(nil, nil), "items naming neither are taxed")
+
+	t.Run("replaces only the kind named", func(t *testing.T) {
+		rate := 13.0
+		got, err := uc.Update(ctx, inst.ID, &institute.UpdateInstituteRequest{Tax: &institute.UpdateTaxConfigRequest{
+			Rate:            &rate,
+			ExemptCourseIDs: &[]uuid.UUID{drivingCourse},
+		}})
+		require.NoError(t, err)
+		assert.True(t, got.TaxExempt(&examFee, nil))
+		assert.False(t, got.TaxExempt(nil, &trialCourse))
+		assert.True(t, got.TaxExempt(nil, &drivingCourse))
+	})
+
+	t.Run("clears exemptions", func(t *testing.T) {
+		got, err := uc.Update(ctx, inst.ID, &institute.UpdateInstituteRequest{Tax: &institute.UpdateTaxConfigRequest{
+			ExemptPackageIDs: &[]uuid.UUID{},
+			ExemptCourseIDs:  &[]uuid.UUID{},
+		}})
+		require.NoError(t, err)
+		assert.Empty(t, got.TaxExemptions)
+	})
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/chalak/backend/pkg/bs"
+)
+
+// InvoiceNumbering is how invoice numbers are laid out and the day each
+// fiscal year begins. Numbers count up from 1 per institute and fiscal year.
+type InvoiceNumbering struct {
+	// Format lays out the number using {code} for the institute code, {fy}
+	// for the fiscal year and {seq} for the counter; {seq:5} pads the counter
+	// to five digits.
+	Format string
+	// StartMonth and StartDay are the BS month and day each fiscal year
+	// begins. Nepal's fiscal year begins on 1 Shrawan (month 4).
+	StartMonth int
+	StartDay   int
+}
+
+var invoiceNumberField = regexp.MustCompile(`\{(code|fy|seq)(?::(\d+))?\}`)
+
+// fiscalYear returns the BS label of the fiscal year containing t, taking
+// the date in Kathmandu: "2082-83" for the year starting in 2082, or just
+// "2082" when fiscal years start on 1 Baisakh.
+func (n InvoiceNumbering) fiscalYear(t time.Time) (string, error) {
+	d, err := bs.FromAD(t.In(bs.Kathmandu))
+	if err != nil {
+		return "", fmt.Errorf("failed to find fiscal year of %s: %w", t.Format("2006-01-02"), err)
+	}
+
+	year := d.Year
+	if d.Month < n.StartMonth || (d.Month == n.StartMonth && d.Day < n.StartDay) {
+		year--
+	}
+
+	if n.StartMonth == 1 && n.StartDay == 1 {
+		return strconv.Itoa(year), nil
+	}
+	return fmt.Sprintf("%d-%02d", year, (year+1)%100), nil
+}
+
+func (n InvoiceNumbering) format(code, fiscalYear string, seq int64) string {
+	return invoiceNumberField.ReplaceAllStringFunc(n.Format, func(field string) string {
+		m := invoiceNumberField.FindStringSubmatch(field)
+		switch m[1] {
+		case "code":
+			return code
+		case "fy":
+			return fiscalYear
+		}
+		width, _ := strconv.Atoi(m[2])
+		return fmt.Sprintf("%0*d", width, seq)
+	})
+}
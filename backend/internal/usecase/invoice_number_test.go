@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoiceNumbering(t *testing.T) {
+	n := InvoiceNumbering{Format: "INV-{code}-{fy}-{seq:5}", StartMonth: 4, StartDay: 1}
+
+	assert.Equal(t, "INV-KTM-2081-82-00042", n.format("KTM", "2081-82", 42))
+	assert.Equal(t, "INV-KTM-2081-82-123456", n.format("KTM", "2081-82", 123456))
+
+	calendar := InvoiceNumbering{Format: "{fy}/{seq}", StartMonth: 1, StartDay: 1}
+	fy, err := calendar.fiscalYear(time.Date(2025, 4, 13, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2081", fy)
+	fy, err = calendar.fiscalYear(time.Date(2025, 4, 14, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2082", fy)
+	assert.Equal(t, "2082/7", calendar.format("KTM", "2082", 7))
+}
+
+func TestFiscalYearTurnsOnShrawanFirstInKathmandu(t *testing.T) {
+	n := InvoiceNumbering{Format: "{fy}-{seq}", StartMonth: 4, StartDay: 1}
+
+	// 1 Shrawan 2081 BS began at midnight in Kathmandu on 16 July 2024,
+	// which was 18:15 UTC on the 15th.
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{name: "Asar 32 in Kathmandu", at: time.Date(2024, 7, 15, 18, 14, 59, 0, time.UTC), want: "2080-81"},
+		{name: "Shrawan 1 in Kathmandu, still the 15th in UTC", at: time.Date(2024, 7, 15, 18, 15, 0, 0, time.UTC), want: "2081-82"},
+		{name: "Shrawan 1 in UTC", at: time.Date(2024, 7, 16, 12, 0, 0, 0, time.UTC), want: "2081-82"},
+		{name: "Baisakh does not start a fiscal year", at: time.Date(2024, 4, 13, 12, 0, 0, 0, time.UTC), want: "2080-81"},
+		{name: "Asar of the next BS year", at: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), want: "2081-82"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := n.fiscalYear(tt.at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := n.fiscalYear(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+}
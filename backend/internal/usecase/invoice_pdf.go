@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/go-pdf/fpdf"
+)
+
+// invoiceDocument is everything printed on an invoice.
+type invoiceDocument struct {
+	Institute *institute.Institute
+	Logo      []byte
+	LogoType  string
+	Invoice   *invoice.Invoice
+	Student   *student.Student
+	Payments  []*payment.Payment
+}
+
+const pdfDate = "2 Jan 2006"
+
+// totalLine is a row of the totals block under the items.
+type totalLine struct {
+	label  string
+	amount money.Amount
+	bold   bool
+}
+
+// renderInvoice lays out a portrait A4 invoice, running onto more pages when
+// there are many items or payments. The institute's footer text is repeated
+// at the bottom of every page.
+func renderInvoice(d invoiceDocument) ([]byte, error) {
+	inv, inst := d.Invoice, d.Institute
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Invoice "+inv.InvoiceNumber, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 25)
+	pdf.AliasNbPages("")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	width, _ := pdf.GetPageSize()
+	content := width - 30
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-20)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(90, 90, 90)
+		if inst.Branding.Footer != "" {
+			pdf.MultiCell(content, 4, tr(inst.Branding.Footer), "", "C", false)
+		}
+		pdf.CellFormat(content, 4, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.AddPage()
+
+	// Institute header, with the logo on the left when there is one.
+	textX := 15.0
+	if len(d.Logo) > 0 {
+		opts := fpdf.ImageOptions{ImageType: d.LogoType, ReadDpi: true}
+		pdf.RegisterImageOptionsReader("logo", opts, bytes.NewReader(d.Logo))
+		if pdf.Ok() {
+			pdf.ImageOptions("logo", 15, 15, 0, 22, false, opts, 0, "")
+			textX = 15 + 22*imageAspect(pdf, "logo") + 5
+		} else {
+			// A logo fpdf cannot read should not stop the invoice printing.
+			pdf.ClearError()
+		}
+	}
+
+	// The institute details stop where the invoice number block begins.
+	headerWidth := width - 85 - textX
+	pdf.SetXY(textX, 15)
+	pdf.SetFont("Helvetica", "B", 15)
+	pdf.CellFormat(headerWidth, 7, tr(inst.Name), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{inst.Address, joinNonEmpty(" | ", inst.Phone, inst.Email)} {
+		if line != "" {
+			pdf.CellFormat(headerWidth, 4.5, tr(line), "", 2, "L", false, 0, "")
+		}
+	}
+	if inst.Tax.Number != "" {
+		pdf.CellFormat(headerWidth, 4.5, tr(inst.Tax.Name+" No: "+inst.Tax.Number), "", 2, "L", false, 0, "")
+	}
+
+	pdf.SetXY(width-85, 15)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(70, 9, "INVOICE", "", 2, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{
+		"No: " + inv.InvoiceNumber,
+		"Date: " + inv.CreatedAt.Format(pdfDate),
+		"Due: " + inv.DueDate.Format(pdfDate),
+		"Status: " + strings.ToUpper(inv.Status),
+	} {
+		pdf.CellFormat(70, 4.5, tr(line), "", 2, "R", false, 0, "")
+	}
+
+	// Bill to
+	pdf.SetXY(15, 50)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(content, 6, "Bill To", "B", 1, "L", false, 0, "")
+	if d.Student != nil {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(content, 5.5, tr(d.Student.FirstName+" "+d.Student.LastName), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, line := range []string{d.Student.Address, joinNonEmpty(" | ", d.Student.Phone, d.Student.Email)} {
+			if line != "" {
+				pdf.CellFormat(content, 4.5, tr(line), "", 1, "L", false, 0, "")
+			}
+		}
+	}
+	pdf.Ln(5)
+
+	// Items
+	cols := []float64{10, content - 10 - 15 - 30 - 25 - 30, 15, 30, 25, 30}
+	taxHeader := "Tax"
+	if inv.TaxName != "" {
+		taxHeader = inv.TaxName
+	}
+	tableHeader(pdf, cols, []string{"#", "Description", "Qty", "Unit Price", taxHeader, "Amount"}, "CLRRRR")
+	for i, item := range inv.Items {
+		tax := "-"
+		if item.TaxExempt {
+			tax = "Exempt"
+		} else if item.TaxAmount != 0 {
+			tax = item.TaxAmount.String()
+		}
+		tableRow(pdf, tr, cols, []string{
+			fmt.Sprint(i + 1),
+			item.Description,
+			fmt.Sprint(item.Quantity),
+			item.UnitPrice.String(),
+			tax,
+			item.Amount.String(),
+		}, "CLRRRR")
+	}
+	pdf.Ln(3)
+
+	// Totals
+	balance := inv.Balance()
+	if balance < 0 {
+		balance = 0
+	}
+	// Payments are printed as received; approved reversals show up as the
+	// gap between those and what the invoice still counts as paid.
+	var received money.Amount
+	for _, p := range d.Payments {
+		received += p.Amount
+	}
+	totals := []totalLine{{label: "Subtotal", amount: inv.Amount}}
+	if inv.TaxAmount > 0 {
+		totals = append(totals,
+			totalLine{label: "Taxable", amount: inv.TaxableAmount},
+			totalLine{label: taxHeader, amount: inv.TaxAmount},
+		)
+		if inv.ExemptAmount > 0 {
+			totals = append(totals, totalLine{label: "Exempt", amount: inv.ExemptAmount})
+		}
+	}
+	totals = append(totals, totalLine{label: "Total", amount: inv.TotalAmount, bold: true})
+	if inv.CreditedAmount > 0 {
+		totals = append(totals, totalLine{label: "Credited", amount: inv.CreditedAmount})
+	}
+	if received > inv.PaidAmount {
+		totals = append(totals,
+			totalLine{label: "Paid", amount: received},
+			totalLine{label: "Refunded", amount: received - inv.PaidAmount},
+		)
+	} else {
+		totals = append(totals, totalLine{label: "Paid", amount: inv.PaidAmount})
+	}
+	totals = append(totals, totalLine{label: "Balance Due", amount: balance, bold: true})
+	for _, t := range totals {
+		style := ""
+		if t.bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.SetX(width - 15 - 85)
+		pdf.CellFormat(45, 6, tr(t.label), "", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 6, "NPR "+t.amount.String(), "", 1, "R", false, 0, "")
+	}
+	if inv.TaxInclusive && inv.TaxAmount > 0 {
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(content, 5, tr("Prices include "+taxHeader+"."), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Payment history
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(content, 6, "Payments", "B", 1, "L", false, 0, "")
+	pdf.Ln(1)
+	if len(d.Payments) == 0 {
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.CellFormat(content, 5, "No payments recorded.", "", 1, "L", false, 0, "")
+	} else {
+		payCols := []float64{35, 35, content - 35 - 35 - 35, 35}
+		tableHeader(pdf, payCols, []string{"Date", "Method", "Notes", "Amount"}, "LLLR")
+		for _, p := range d.Payments {
+			tableRow(pdf, tr, payCols, []string{
+				p.PaymentDate.Format(pdfDate),
+				paymentMethodLabel(p.PaymentMethod),
+				p.Notes,
+				p.Amount.String(),
+			}, "LLLR")
+		}
+	}
+
+	if inv.Notes != "" {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(content, 6, "Notes", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(content, 4.5, tr(inv.Notes), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func tableHeader(pdf *fpdf.Fpdf, widths []float64, cells []string, align string) {
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	for i, cell := range cells {
+		pdf.CellFormat(widths[i], 7, cell, "TB", 0, align[i:i+1], true, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+func tableRow(pdf *fpdf.Fpdf, tr func(string) string, widths []float64, cells []string, align string) {
+	pdf.SetFont("Helvetica", "", 9)
+	for i, cell := range cells {
+		text := tr(cell)
+		// Long text is cut to the column rather than wrapped so rows keep
+		// a single height.
+		for len(text) > 1 && pdf.GetStringWidth(text) > widths[i]-2 {
+			text = text[:len(text)-1]
+		}
+		pdf.CellFormat(widths[i], 6, text, "B", 0, align[i:i+1], false, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+// imageAspect returns the width of a registered image per unit of height.
+func imageAspect(pdf *fpdf.Fpdf, name string) float64 {
+	info := pdf.GetImageInfo(name)
+	if info == nil || info.Height() == 0 {
+		return 1
+	}
+	return info.Width() / info.Height()
+}
+
+// logoImageType maps a stored logo's extension to the image type fpdf
+// expects.
+func logoImageType(key string) string {
+	if strings.HasSuffix(key, storage.Extension(storage.TypePNG)) {
+		return "PNG"
+	}
+	return "JPG"
+}
+
+func paymentMethodLabel(method string) string {
+	switch method {
+	case payment.MethodBankTransfer:
+		return "Bank Transfer"
+	case "":
+		return ""
+	}
+	return strings.ToUpper(method[:1]) + method[1:]
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
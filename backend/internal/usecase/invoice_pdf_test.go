@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleInvoiceDocument() invoiceDocument {
+	now := time.Date(2024, time.July, 20, 9, 0, 0, 0, time.UTC)
+	return invoiceDocument{
+		Institute: &institute.Institute{
+			Name:     "Himalayan Driving School",
+			Address:  "Baneshwor, Kathmandu",
+			Phone:    "01-4480000",
+			Tax:      institute.TaxConfig{Name: "VAT", Rate: 13, Number: "601234567"},
+			Branding: institute.Branding{Footer: "Thank you for learning with us."},
+		},
+		Invoice: &invoice.Invoice{
+			InvoiceNumber: "INV-HDS-2081-82-00001",
+			Amount:        money.FromMinor(1500000),
+			TaxableAmount: money.FromMinor(1000000),
+			ExemptAmount:  money.FromMinor(500000),
+			TaxName:       "VAT",
+			TaxAmount:     money.FromMinor(130000),
+			TotalAmount:   money.FromMinor(1630000),
+			PaidAmount:    money.FromMinor(500000),
+			Status:        invoice.StatusPending,
+			DueDate:       now.AddDate(0, 0, 14),
+			CreatedAt:     now,
+			Items: []invoice.InvoiceItem{
+				{Description: "Car driving course", Quantity: 1, UnitPrice: money.FromMinor(1000000), Amount: money.FromMinor(1000000), TaxRate: 13, TaxAmount: money.FromMinor(130000)},
+				{Description: "Trial exam fee", Quantity: 1, UnitPrice: money.FromMinor(500000), Amount: money.FromMinor(500000), TaxExempt: true},
+			},
+		},
+		Student: &student.Student{FirstName: "Sita", LastName: "Sharma", Phone: "9800000000"},
+		Payments: []*payment.Payment{
+			{Amount: money.FromMinor(500000), PaymentMethod: payment.MethodCash, PaymentDate: now},
+		},
+	}
+}
+
+func TestRenderInvoice(t *testing.T) {
+	var logo bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	img.Set(1, 1, color.Black)
+	require.NoError(t, png.Encode(&logo, img))
+
+	doc := sampleInvoiceDocument()
+	doc.Logo, doc.LogoType = logo.Bytes(), "PNG"
+
+	file, err := renderInvoice(doc)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(file, []byte("%PDF")))
+}
+
+func TestRenderInvoiceSkipsUnreadableLogo(t *testing.T) {
+	doc := sampleInvoiceDocument()
+	doc.Logo, doc.LogoType = []byte("not an image"), "PNG"
+
+	file, err := renderInvoice(doc)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(file, []byte("%PDF")))
+}
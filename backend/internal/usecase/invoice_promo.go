@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/promo"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+)
+
+// redeemPromo checks a promo code can be used on an invoice for the student
+// and returns the discount lines it adds. It must run in the transaction
+// that creates the invoice: the code stays locked until then so its usage
+// limits hold under concurrent invoicing.
+func (uc *InvoiceUseCase) redeemPromo(ctx context.Context, instituteID, studentID uuid.UUID, code string, items []invoice.CreateInvoiceItem, now time.Time) (*promo.PromoCode, []invoice.CreateInvoiceItem, error) {
+	pc, err := uc.promoRepo.FindByCodeForUpdate(ctx, instituteID, code)
+	if err != nil || !pc.IsActive {
+		return nil, nil, apperrors.BadRequest("promo code not found").WithDetails(map[string]interface{}{
+			"promo_code": code,
+		})
+	}
+
+	if (pc.ValidFrom != nil && now.Before(*pc.ValidFrom)) || (pc.ValidUntil != nil && now.After(*pc.ValidUntil)) {
+		return nil, nil, apperrors.BadRequest("promo code is not valid at this time").WithDetails(map[string]interface{}{
+			"valid_from":  pc.ValidFrom,
+			"valid_until": pc.ValidUntil,
+		})
+	}
+
+	if pc.MaxUses > 0 {
+		uses, err := uc.promoRepo.CountRedemptions(ctx, pc.ID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if uses >= pc.MaxUses {
+			return nil, nil, apperrors.BadRequest("promo code has been used up")
+		}
+	}
+
+	if pc.MaxUsesPerStudent > 0 {
+		uses, err := uc.promoRepo.CountRedemptions(ctx, pc.ID, &studentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if uses >= pc.MaxUsesPerStudent {
+			return nil, nil, apperrors.BadRequest("student has already used this promo code")
+		}
+	}
+
+	lines := promoDiscount(pc, items)
+	if len(lines) == 0 {
+		return nil, nil, apperrors.BadRequest("promo code does not apply to any item on this invoice")
+	}
+	return pc, lines, nil
+}
+
+// promoDiscount works out the discount a promo code gives on the items it
+// applies to, as negative lines priced like the items themselves. When the
+// discounted items mix taxable and exempt ones the discount is split between
+// a taxable and an exempt line in proportion, so tax is only taken off where
+// it was charged.
+func promoDiscount(pc *promo.PromoCode, items []invoice.CreateInvoiceItem) []invoice.CreateInvoiceItem {
+	var taxable, exempt money.Amount
+	for _, item := range items {
+		if !promoApplies(pc, item) {
+			continue
+		}
+		if item.TaxExempt {
+			exempt += item.UnitPrice.Mul(item.Quantity)
+		} else {
+			taxable += item.UnitPrice.Mul(item.Quantity)
+		}
+	}
+
+	base := taxable + exempt
+	if base <= 0 {
+		return nil
+	}
+
+	discount := pc.Amount
+	if pc.DiscountType == promo.DiscountPercentage {
+		discount = base.Percent(pc.Percentage)
+	}
+	if discount > base {
+		discount = base
+	}
+
+	taxablePart := discount.MulDiv(taxable.Minor(), base.Minor())
+	exemptPart := discount - taxablePart
+
+	var lines []invoice.CreateInvoiceItem
+	for _, part := range []struct {
+		amount money.Amount
+		exempt bool
+	}{{taxablePart, false}, {exemptPart, true}} {
+		if part.amount <= 0 {
+			continue
+		}
+		lines = append(lines, invoice.CreateInvoiceItem{
+			Description: "Discount (" + pc.Code + ")",
+			Quantity:    1,
+			UnitPrice:   -part.amount,
+			TaxExempt:   part.exempt,
+			IsDiscount:  true,
+		})
+	}
+	return lines
+}
+
+// promoApplies reports whether a promo code covers an item. A code with no
+// targets covers everything.
+func promoApplies(pc *promo.PromoCode, item invoice.CreateInvoiceItem) bool {
+	if len(pc.Targets) == 0 {
+		return true
+	}
+	for _, t := range pc.Targets {
+		if t.PackageID != nil && item.PackageID != nil && *t.PackageID == *item.PackageID {
+			return true
+		}
+		if t.CourseID != nil && item.CourseID != nil && *t.CourseID == *item.CourseID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/promo"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoDiscount(t *testing.T) {
+	course := uuid.New()
+	items := []invoice.CreateInvoiceItem{
+		{Description: "Driving course", Quantity: 1, UnitPrice: money.FromMinor(1500000), CourseID: &course},
+		{Description: "Trial exam fee", Quantity: 2, UnitPrice: money.FromMinor(50000), TaxExempt: true},
+	}
+
+	t.Run("percentage split across taxable and exempt items", func(t *testing.T) {
+		lines := promoDiscount(&promo.PromoCode{Code: "DASHAIN10", DiscountType: promo.DiscountPercentage, Percentage: 10}, items)
+		require.Len(t, lines, 2)
+		assert.Equal(t, money.FromMinor(-150000), lines[0].UnitPrice)
+		assert.False(t, lines[0].TaxExempt)
+		assert.Equal(t, money.FromMinor(-10000), lines[1].UnitPrice)
+		assert.True(t, lines[1].TaxExempt)
+
+		// Tax is only charged on what is left after the discount.
+		_, totals := priceItems(append(items, lines...), institute.TaxConfig{Name: "VAT", Rate: 13})
+		assert.Equal(t, money.FromMinor(1350000), totals.Taxable)
+		assert.Equal(t, money.FromMinor(175500), totals.Tax)
+	})
+
+	t.Run("fixed discount limited to targeted items", func(t *testing.T) {
+		pc := &promo.PromoCode{
+			Code:         "WELCOME",
+			DiscountType: promo.DiscountFixed,
+			Amount:       money.FromMinor(2000000),
+			Targets:      []promo.Target{{CourseID: &course}},
+		}
+		lines := promoDiscount(pc, items)
+		require.Len(t, lines, 1)
+		assert.Equal(t, money.FromMinor(-1500000), lines[0].UnitPrice)
+		assert.True(t, lines[0].IsDiscount)
+	})
+
+	t.Run("no matching items", func(t *testing.T) {
+		other := uuid.New()
+		pc := &promo.PromoCode{DiscountType: promo.DiscountFixed, Amount: 100, Targets: []promo.Target{{PackageID: &other}}}
+		assert.Empty(t, promoDiscount(pc, items))
+	})
+}
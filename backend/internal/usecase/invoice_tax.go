@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"math"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/pkg/money"
+)
+
+// invoiceTotals is the tax breakdown of an invoice. Amount is the total
+// before tax, split into the part tax was charged on and the exempt part.
+type invoiceTotals struct {
+	Amount  money.Amount
+	Taxable money.Amount
+	Exempt  money.Amount
+	Tax     money.Amount
+	Total   money.Amount
+}
+
+// priceItems works out each line's net amount and tax under the institute's
+// tax configuration. Tax is rounded per line and the invoice totals are the
+// sums of the lines, so the printed lines always add up. With inclusive
+// pricing the line total is what the customer pays and tax is worked out of
+// it; otherwise tax is added on top.
+func priceItems(items []invoice.CreateInvoiceItem, tax institute.TaxConfig) ([]invoice.InvoiceItem, invoiceTotals) {
+	// The rate in hundredths of a percent, so 13% is 1300.
+	basisPoints := int64(math.Round(tax.Rate * 100))
+
+	var totals invoiceTotals
+	priced := make([]invoice.InvoiceItem, 0, len(items))
+
+	for _, item := range items {
+		line := item.UnitPrice.Mul(item.Quantity)
+		net, lineTax := line, money.Zero
+		rate := 0.0
+
+		if !item.TaxExempt && basisPoints > 0 {
+			rate = tax.Rate
+			if tax.Inclusive {
+				net = line.MulDiv(10000, 10000+basisPoints)
+				lineTax = line - net
+			} else {
+				lineTax = line.MulDiv(basisPoints, 10000)
+			}
+		}
+
+		if item.TaxExempt || basisPoints == 0 {
+			totals.Exempt += net
+		} else {
+			totals.Taxable += net
+		}
+		totals.Amount += net
+		totals.Tax += lineTax
+
+		priced = append(priced, invoice.InvoiceItem{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      net,
+			TaxExempt:   item.TaxExempt,
+			TaxRate:     rate,
+			TaxAmount:   lineTax,
+			PackageID:   item.PackageID,
+			CourseID:    item.CourseID,
+			IsDiscount:  item.IsDiscount,
+		})
+	}
+
+	totals.Total = totals.Amount + totals.Tax
+	return priced, totals
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/chalak/backend/internal/domain/institute"
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceItems(t *testing.T) {
+	items := []invoice.CreateInvoiceItem{
+		{Description: "Driving course", Quantity: 1, UnitPrice: money.FromMinor(1500000)},
+		{Description: "Trial exam fee", Quantity: 2, UnitPrice: money.FromMinor(50000), TaxExempt: true},
+	}
+
+	t.Run("no tax registration", func(t *testing.T) {
+		priced, totals := priceItems(items, institute.TaxConfig{})
+		require.Len(t, priced, 2)
+		assert.Equal(t, money.FromMinor(1600000), totals.Total)
+		assert.Equal(t, money.FromMinor(1600000), totals.Exempt)
+		assert.Zero(t, totals.Tax)
+		assert.Zero(t, priced[0].TaxRate)
+	})
+
+	t.Run("exclusive VAT", func(t *testing.T) {
+		priced, totals := priceItems(items, institute.TaxConfig{Name: "VAT", Rate: 13})
+		assert.Equal(t, money.FromMinor(195000), priced[0].TaxAmount)
+		assert.Equal(t, 13.0, priced[0].TaxRate)
+		assert.Zero(t, priced[1].TaxAmount)
+		assert.Zero(t, priced[1].TaxRate)
+
+		assert.Equal(t, money.FromMinor(1500000), totals.Taxable)
+		assert.Equal(t, money.FromMinor(100000), totals.Exempt)
+		assert.Equal(t, money.FromMinor(1600000), totals.Amount)
+		assert.Equal(t, money.FromMinor(195000), totals.Tax)
+		assert.Equal(t, money.FromMinor(1795000), totals.Total)
+	})
+
+	t.Run("inclusive VAT", func(t *testing.T) {
+		priced, totals := priceItems(items, institute.TaxConfig{Name: "VAT", Rate: 13, Inclusive: true})
+		// 15000 / 1.13 = 13274.336...
+		assert.Equal(t, money.FromMinor(1327434), priced[0].Amount)
+		assert.Equal(t, money.FromMinor(172566), priced[0].TaxAmount)
+		// The customer pays the listed prices.
+		assert.Equal(t, money.FromMinor(1600000), totals.Total)
+		assert.Equal(t, totals.Amount+totals.Tax, totals.Total)
+	})
+}
@@ -3,68 +3,164 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/chalak/backend/internal/domain/institute"
 	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/internal/domain/promo"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/internal/domain/transaction"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/chalak/backend/pkg/tenant"
 	"github.com/google/uuid"
 )
 
 type InvoiceUseCase struct {
-	repo   invoice.Repository
-	logger logger.Logger
+	repo          invoice.Repository
+	instituteRepo institute.Repository
+	paymentRepo   payment.Repository
+	studentRepo   student.Repository
+	promoRepo     promo.Repository
+	storage       storage.Storage
+	tx            transaction.Manager
+	numbering     InvoiceNumbering
+	logger        logger.Logger
 }
 
-func NewInvoiceUseCase(repo invoice.Repository, logger logger.Logger) *InvoiceUseCase {
+func NewInvoiceUseCase(
+	repo invoice.Repository,
+	instituteRepo institute.Repository,
+	paymentRepo payment.Repository,
+	studentRepo student.Repository,
+	promoRepo promo.Repository,
+	store storage.Storage,
+	tx transaction.Manager,
+	numbering InvoiceNumbering,
+	logger logger.Logger,
+) *InvoiceUseCase {
 	return &InvoiceUseCase{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		instituteRepo: instituteRepo,
+		paymentRepo:   paymentRepo,
+		studentRepo:   studentRepo,
+		promoRepo:     promoRepo,
+		storage:       store,
+		tx:            tx,
+		numbering:     numbering,
+		logger:        logger,
 	}
 }
 
+// Create issues an invoice, charging tax on its items as configured for the
+// institute and taking off any promo code the student claims. The invoice
+// takes the next number in the institute's fiscal year.
 func (uc *InvoiceUseCase) Create(ctx context.Context, req *invoice.CreateInvoiceRequest, createdBy uuid.UUID) (*invoice.Invoice, error) {
-	var amount, taxAmount, totalAmount float64
-	items := make([]invoice.InvoiceItem, 0, len(req.Items))
+	instituteID := req.InstituteID
+	if instituteID == uuid.Nil {
+		instituteID, _ = tenant.InstituteID(ctx)
+	}
+	if instituteID == uuid.Nil {
+		return nil, apperrors.BadRequest("institute_id is required")
+	}
 
-	for _, item := range req.Items {
-		itemAmount := float64(item.Quantity) * item.UnitPrice
-		amount += itemAmount
+	inst, err := uc.instituteRepo.FindByID(ctx, instituteID)
+	if err != nil {
+		return nil, apperrors.NotFound("institute not found")
+	}
 
-		items = append(items, invoice.InvoiceItem{
-			ID:          uuid.New(),
-			Description: item.Description,
-			Quantity:    item.Quantity,
-			UnitPrice:   item.UnitPrice,
-			Amount:      itemAmount,
-			CreatedAt:   time.Now().UTC(),
-			UpdatedAt:   time.Now().UTC(),
-		})
+	now := time.Now().UTC()
+	fiscalYear, err := uc.numbering.fiscalYear(now)
+	if err != nil {
+		return nil, err
 	}
 
-	taxAmount = amount * 0.0
-	totalAmount = amount + taxAmount
+	var inv *invoice.Invoice
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		lines := make([]invoice.CreateInvoiceItem, len(req.Items))
+		for i, item := range req.Items {
+			item.TaxExempt = inst.TaxExempt(item.PackageID, item.CourseID)
+			lines[i] = item
+		}
+		var pc *promo.PromoCode
+		if req.PromoCode != "" {
+			redeemed, discounts, err := uc.redeemPromo(ctx, instituteID, req.StudentID, req.PromoCode, lines, now)
+			if err != nil {
+				return err
+			}
+			pc = redeemed
+			lines = append(lines, discounts...)
+		}
 
-	invoiceNumber := fmt.Sprintf("INV-%d-%s", time.Now().Year(), uuid.New().String()[:8])
+		tax := inst.Tax
+		if req.TaxInclusive {
+			tax.Inclusive = true
+		}
+		items, totals := priceItems(lines, tax)
+		var discount money.Amount
+		for i := range items {
+			items[i].ID = uuid.New()
+			items[i].CreatedAt = now
+			items[i].UpdatedAt = now
+			if items[i].IsDiscount {
+				discount -= items[i].UnitPrice
+			}
+		}
 
-	inv := &invoice.Invoice{
-		ID:            uuid.New(),
-		InvoiceNumber: invoiceNumber,
-		StudentID:     req.StudentID,
-		InstituteID:   req.InstituteID,
-		Amount:        amount,
-		TaxAmount:     taxAmount,
-		TotalAmount:   totalAmount,
-		Status:        invoice.StatusPending,
-		DueDate:       req.DueDate,
-		Notes:         req.Notes,
-		Items:         items,
-		CreatedBy:     createdBy,
-		CreatedAt:     time.Now().UTC(),
-		UpdatedAt:     time.Now().UTC(),
-	}
+		inv = &invoice.Invoice{
+			ID:             uuid.New(),
+			StudentID:      req.StudentID,
+			InstituteID:    instituteID,
+			Amount:         totals.Amount,
+			TaxableAmount:  totals.Taxable,
+			ExemptAmount:   totals.Exempt,
+			TaxAmount:      totals.Tax,
+			TotalAmount:    totals.Total,
+			DiscountAmount: discount,
+			Status:         invoice.StatusPending,
+			DueDate:        req.DueDate,
+			Notes:          req.Notes,
+			Items:          items,
+			CreatedBy:      createdBy,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if totals.Taxable > 0 {
+			inv.TaxName = tax.Name
+			inv.TaxInclusive = tax.Inclusive
+		}
+		if pc != nil {
+			inv.PromoCodeID = &pc.ID
+		}
+
+		seq, err := uc.repo.NextNumber(ctx, instituteID, fiscalYear)
+		if err != nil {
+			return err
+		}
+		inv.InvoiceNumber = uc.numbering.format(inst.Code, fiscalYear, seq)
 
-	if err := uc.repo.Create(ctx, inv); err != nil {
+		if err := uc.repo.Create(ctx, inv); err != nil {
+			return err
+		}
+
+		if pc == nil {
+			return nil
+		}
+		return uc.promoRepo.CreateRedemption(ctx, &promo.Redemption{
+			ID:          uuid.New(),
+			PromoCodeID: pc.ID,
+			InstituteID: instituteID,
+			StudentID:   inv.StudentID,
+			InvoiceID:   inv.ID,
+			Amount:      discount,
+			CreatedAt:   now,
+		})
+	})
+	if err != nil {
 		uc.logger.Error(ctx, "failed to create invoice", err, map[string]interface{}{
 			"student_id": req.StudentID,
 		})
@@ -80,59 +176,91 @@ func (uc *InvoiceUseCase) Create(ctx context.Context, req *invoice.CreateInvoice
 	return inv, nil
 }
 
-func (uc *InvoiceUseCase) GetByID(ctx context.Context, id uuid.UUID) (*invoice.Invoice, error) {
-	inv, err := uc.repo.FindByID(ctx, id)
+// withTax is what a taxable charge of amount comes to on the institute's
+// invoices: amount itself under inclusive pricing, otherwise amount plus tax.
+func (uc *InvoiceUseCase) withTax(ctx context.Context, instituteID uuid.UUID, amount money.Amount) (money.Amount, error) {
+	inst, err := uc.instituteRepo.FindByID(ctx, instituteID)
 	if err != nil {
-		return nil, apperrors.NotFound("invoice not found")
+		return 0, apperrors.NotFound("institute not found")
 	}
-	return inv, nil
+	_, totals := priceItems([]invoice.CreateInvoiceItem{{Quantity: 1, UnitPrice: amount}}, inst.Tax)
+	return totals.Total, nil
 }
 
-func (uc *InvoiceUseCase) GetByInvoiceNumber(ctx context.Context, invoiceNumber string) (*invoice.Invoice, error) {
-	inv, err := uc.repo.FindByInvoiceNumber(ctx, invoiceNumber)
+func (uc *InvoiceUseCase) GetByID(ctx context.Context, id uuid.UUID) (*invoice.Invoice, error) {
+	inv, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, apperrors.NotFound("invoice not found")
 	}
 	return inv, nil
 }
 
-func (uc *InvoiceUseCase) MarkAsPaid(ctx context.Context, id uuid.UUID) error {
+// RenderPDF prints the invoice with the institute's branding and the
+// payments made against it so far.
+func (uc *InvoiceUseCase) RenderPDF(ctx context.Context, id uuid.UUID) ([]byte, *invoice.Invoice, error) {
 	inv, err := uc.repo.FindByID(ctx, id)
 	if err != nil {
-		return apperrors.NotFound("invoice not found")
+		return nil, nil, apperrors.NotFound("invoice not found")
+	}
+
+	inst, err := uc.instituteRepo.FindByID(ctx, inv.InstituteID)
+	if err != nil {
+		return nil, nil, apperrors.NotFound("institute not found")
 	}
 
-	if inv.Status == invoice.StatusPaid {
-		return apperrors.BadRequest("invoice already paid")
+	doc := invoiceDocument{Institute: inst, Invoice: inv}
+
+	// The invoice still prints if the student has since been removed.
+	if stu, err := uc.studentRepo.GetByID(ctx, inv.StudentID); err == nil {
+		doc.Student = stu
 	}
 
-	if err := uc.repo.MarkAsPaid(ctx, id); err != nil {
-		uc.logger.Error(ctx, "failed to mark invoice as paid", err, map[string]interface{}{
+	doc.Payments, err = uc.paymentRepo.GetByInvoiceID(ctx, inv.ID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to get invoice payments", err, map[string]interface{}{
 			"invoice_id": id,
 		})
-		return fmt.Errorf("failed to mark invoice as paid: %w", err)
+		return nil, nil, fmt.Errorf("failed to get invoice payments: %w", err)
 	}
 
-	uc.logger.Info(ctx, "invoice marked as paid", map[string]interface{}{
-		"invoice_id": id,
-	})
-
-	return nil
-}
+	if key := inst.Branding.LogoKey; key != "" {
+		logo, err := uc.readLogo(ctx, key)
+		if err != nil {
+			uc.logger.Warn(ctx, "failed to load institute logo", map[string]interface{}{
+				"institute_id": inst.ID,
+				"error":        err.Error(),
+			})
+		} else {
+			doc.Logo, doc.LogoType = logo, logoImageType(key)
+		}
+	}
 
-func (uc *InvoiceUseCase) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := uc.repo.Delete(ctx, id); err != nil {
-		uc.logger.Error(ctx, "failed to delete invoice", err, map[string]interface{}{
+	file, err := renderInvoice(doc)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to render invoice", err, map[string]interface{}{
 			"invoice_id": id,
 		})
-		return fmt.Errorf("failed to delete invoice: %w", err)
+		return nil, nil, err
 	}
 
-	uc.logger.Info(ctx, "invoice deleted", map[string]interface{}{
-		"invoice_id": id,
-	})
+	return file, inv, nil
+}
 
-	return nil
+func (uc *InvoiceUseCase) readLogo(ctx context.Context, key string) ([]byte, error) {
+	r, err := uc.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (uc *InvoiceUseCase) GetByInvoiceNumber(ctx context.Context, invoiceNumber string) (*invoice.Invoice, error) {
+	inv, err := uc.repo.FindByInvoiceNumber(ctx, invoiceNumber)
+	if err != nil {
+		return nil, apperrors.NotFound("invoice not found")
+	}
+	return inv, nil
 }
 
 func (uc *InvoiceUseCase) List(ctx context.Context, filter invoice.InvoiceFilter) ([]*invoice.Invoice, int64, error) {
@@ -145,7 +273,11 @@ func (uc *InvoiceUseCase) List(ctx context.Context, filter invoice.InvoiceFilter
 	return invoices, total, nil
 }
 
-func (uc *InvoiceUseCase) GetRevenue(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (float64, error) {
+func (uc *InvoiceUseCase) GetRevenue(ctx context.Context, instituteID uuid.UUID, dateFrom, dateTo time.Time) (money.Amount, error) {
+	if !tenant.CanAccess(ctx, instituteID) {
+		return 0, apperrors.NotFound("institute not found")
+	}
+
 	revenue, err := uc.repo.GetTotalRevenue(ctx, instituteID, dateFrom, dateTo)
 	if err != nil {
 		uc.logger.Error(ctx, "failed to get revenue", err, map[string]interface{}{
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/employee"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/internal/domain/vehicle"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type LessonUseCase struct {
+	repo         lesson.Repository
+	courseRepo   course.Repository
+	employeeRepo employee.Repository
+	studentRepo  student.Repository
+	vehicleRepo  vehicle.Repository
+	logger       logger.Logger
+}
+
+func NewLessonUseCase(
+	repo lesson.Repository,
+	courseRepo course.Repository,
+	employeeRepo employee.Repository,
+	studentRepo student.Repository,
+	vehicleRepo vehicle.Repository,
+	logger logger.Logger,
+) *LessonUseCase {
+	return &LessonUseCase{
+		repo:         repo,
+		courseRepo:   courseRepo,
+		employeeRepo: employeeRepo,
+		studentRepo:  studentRepo,
+		vehicleRepo:  vehicleRepo,
+		logger:       logger,
+	}
+}
+
+func (uc *LessonUseCase) Create(ctx context.Context, req *lesson.CreateSessionRequest) (*lesson.Session, error) {
+	if _, err := uc.courseRepo.GetByID(ctx, req.CourseID); err != nil {
+		return nil, apperrors.NotFound("course not found")
+	}
+
+	instructor, err := uc.findInstructor(ctx, req.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.VehicleID != nil {
+		if req.Type != lesson.TypePractical {
+			return nil, apperrors.BadRequest("only practical lessons can use a vehicle")
+		}
+		if err := uc.checkVehicle(ctx, *req.VehicleID, instructor.InstituteID); err != nil {
+			return nil, err
+		}
+	}
+
+	studentIDs := uniqueIDs(req.StudentIDs)
+	if len(studentIDs) > req.Capacity {
+		return nil, apperrors.BadRequest("number of students exceeds session capacity")
+	}
+	for _, studentID := range studentIDs {
+		if err := uc.checkStudent(ctx, studentID, instructor.InstituteID); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &lesson.Session{
+		ID:           uuid.New(),
+		InstituteID:  instructor.InstituteID,
+		CourseID:     req.CourseID,
+		InstructorID: instructor.ID,
+		VehicleID:    req.VehicleID,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Type:         req.Type,
+		Capacity:     req.Capacity,
+		Location:     req.Location,
+		Status:       lesson.StatusScheduled,
+		Notes:        req.Notes,
+		StudentIDs:   studentIDs,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := uc.repo.Create(ctx, session); err != nil {
+		if conflict := bookingConflict(err); conflict != nil {
+			return nil, conflict
+		}
+		uc.logger.Error(ctx, "failed to create lesson session", err, map[string]interface{}{
+			"course_id":     req.CourseID,
+			"instructor_id": req.InstructorID,
+		})
+		return nil, fmt.Errorf("failed to create lesson session: %w", err)
+	}
+
+	uc.logger.Info(ctx, "lesson session created", map[string]interface{}{
+		"session_id": session.ID,
+		"start_time": session.StartTime,
+	})
+
+	return session, nil
+}
+
+func (uc *LessonUseCase) GetByID(ctx context.Context, id uuid.UUID) (*lesson.Session, error) {
+	session, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("lesson session not found")
+	}
+	return session, nil
+}
+
+func (uc *LessonUseCase) Update(ctx context.Context, id uuid.UUID, req *lesson.UpdateSessionRequest) (*lesson.Session, error) {
+	session, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("lesson session not found")
+	}
+
+	if req.InstructorID != nil && *req.InstructorID != session.InstructorID {
+		instructor, err := uc.findInstructor(ctx, *req.InstructorID)
+		if err != nil {
+			return nil, err
+		}
+		if instructor.InstituteID != session.InstituteID {
+			return nil, apperrors.BadRequest("instructor belongs to another institute")
+		}
+		session.InstructorID = instructor.ID
+	}
+	if req.VehicleID != nil && (session.VehicleID == nil || *req.VehicleID != *session.VehicleID) {
+		if err := uc.checkVehicle(ctx, *req.VehicleID, session.InstituteID); err != nil {
+			return nil, err
+		}
+		session.VehicleID = req.VehicleID
+	}
+	if req.StartTime != nil {
+		session.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		session.EndTime = *req.EndTime
+	}
+	if !session.EndTime.After(session.StartTime) {
+		return nil, apperrors.BadRequest("end time must be after start time")
+	}
+	if req.Type != nil {
+		session.Type = *req.Type
+	}
+	if session.VehicleID != nil && session.Type != lesson.TypePractical {
+		return nil, apperrors.BadRequest("only practical lessons can use a vehicle")
+	}
+	if req.Capacity != nil {
+		if *req.Capacity < len(session.StudentIDs) {
+			return nil, apperrors.BadRequest("capacity cannot be below the number of students already booked")
+		}
+		session.Capacity = *req.Capacity
+	}
+	if req.Location != nil {
+		session.Location = *req.Location
+	}
+	if req.Status != nil {
+		session.Status = *req.Status
+	}
+	if req.Notes != nil {
+		session.Notes = *req.Notes
+	}
+
+	session.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, session); err != nil {
+		if conflict := bookingConflict(err); conflict != nil {
+			return nil, conflict
+		}
+		uc.logger.Error(ctx, "failed to update lesson session", err, map[string]interface{}{
+			"session_id": id,
+		})
+		return nil, fmt.Errorf("failed to update lesson session: %w", err)
+	}
+
+	uc.logger.Info(ctx, "lesson session updated", map[string]interface{}{
+		"session_id": session.ID,
+	})
+
+	return session, nil
+}
+
+func (uc *LessonUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return apperrors.NotFound("lesson session not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete lesson session", err, map[string]interface{}{
+			"session_id": id,
+		})
+		return fmt.Errorf("failed to delete lesson session: %w", err)
+	}
+
+	uc.logger.Info(ctx, "lesson session deleted", map[string]interface{}{
+		"session_id": id,
+	})
+
+	return nil
+}
+
+func (uc *LessonUseCase) List(ctx context.Context, filter lesson.SessionFilter) ([]*lesson.Session, int64, error) {
+	sessions, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list lesson sessions", err, nil)
+		return nil, 0, fmt.Errorf("failed to list lesson sessions: %w", err)
+	}
+
+	return sessions, total, nil
+}
+
+func (uc *LessonUseCase) AddStudents(ctx context.Context, id uuid.UUID, req *lesson.RosterRequest) (*lesson.Session, error) {
+	session, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("lesson session not found")
+	}
+	if session.Status != lesson.StatusScheduled {
+		return nil, apperrors.BadRequest("students can only be added to scheduled sessions")
+	}
+
+	booked := make(map[uuid.UUID]bool, len(session.StudentIDs))
+	for _, studentID := range session.StudentIDs {
+		booked[studentID] = true
+	}
+
+	added := make([]uuid.UUID, 0, len(req.StudentIDs))
+	for _, studentID := range uniqueIDs(req.StudentIDs) {
+		if booked[studentID] {
+			continue
+		}
+		if err := uc.checkStudent(ctx, studentID, session.InstituteID); err != nil {
+			return nil, err
+		}
+		added = append(added, studentID)
+	}
+
+	if len(session.StudentIDs)+len(added) > session.Capacity {
+		return nil, apperrors.BadRequest("session is full")
+	}
+
+	if err := uc.repo.AddStudents(ctx, session, added); err != nil {
+		if conflict := bookingConflict(err); conflict != nil {
+			return nil, conflict
+		}
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		uc.logger.Error(ctx, "failed to add students to lesson session", err, map[string]interface{}{
+			"session_id": session.ID,
+		})
+		return nil, fmt.Errorf("failed to add students to lesson session: %w", err)
+	}
+	session.StudentIDs = append(session.StudentIDs, added...)
+
+	return session, nil
+}
+
+func (uc *LessonUseCase) RemoveStudent(ctx context.Context, id, studentID uuid.UUID) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return apperrors.NotFound("lesson session not found")
+	}
+
+	if err := uc.repo.RemoveStudent(ctx, id, studentID); err != nil {
+		uc.logger.Error(ctx, "failed to remove student from lesson session", err, map[string]interface{}{
+			"session_id": id,
+			"student_id": studentID,
+		})
+		return fmt.Errorf("failed to remove student from lesson session: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *LessonUseCase) findInstructor(ctx context.Context, id uuid.UUID) (*employee.Employee, error) {
+	instructor, err := uc.employeeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("instructor not found")
+	}
+	if instructor.Status != employee.StatusActive {
+		return nil, apperrors.BadRequest("instructor is not active")
+	}
+	return instructor, nil
+}
+
+func (uc *LessonUseCase) checkVehicle(ctx context.Context, vehicleID, instituteID uuid.UUID) error {
+	v, err := uc.vehicleRepo.FindByID(ctx, vehicleID)
+	if err != nil || v.InstituteID != instituteID {
+		return apperrors.NotFound("vehicle not found")
+	}
+	if v.Status != vehicle.StatusActive {
+		return apperrors.BadRequest("vehicle is not available")
+	}
+	return nil
+}
+
+func (uc *LessonUseCase) checkStudent(ctx context.Context, studentID, instituteID uuid.UUID) error {
+	s, err := uc.studentRepo.GetByID(ctx, studentID)
+	if err != nil || s.InstituteID != instituteID {
+		return apperrors.NotFound(fmt.Sprintf("student %s not found", studentID))
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// bookingConflict turns a double-booking reported by the repository into a
+// 409 that lists the clashing sessions.
+func bookingConflict(err error) *apperrors.AppError {
+	var conflictErr *lesson.ConflictError
+	if !errors.As(err, &conflictErr) {
+		return nil
+	}
+	conflicts := conflictErr.Conflicts
+	if conflicts == nil {
+		conflicts = []lesson.Conflict{}
+	}
+	return apperrors.Conflict("lesson session overlaps existing bookings").WithDetails(map[string]interface{}{
+		"conflicts": conflicts,
+	})
+}
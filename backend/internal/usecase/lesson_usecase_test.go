@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/employee"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *memSessionRepo) Create(ctx context.Context, session *lesson.Session) error {
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memSessionRepo) Update(ctx context.Context, session *lesson.Session) error {
+	r.sessions[session.ID] = session
+	return nil
+}
+
+// AddStudents records the additions on a copy, so the use case is the one
+// that updates the session it was handed.
+func (r *memSessionRepo) AddStudents(ctx context.Context, session *lesson.Session, studentIDs []uuid.UUID) error {
+	stored := *r.sessions[session.ID]
+	stored.StudentIDs = append(append([]uuid.UUID(nil), stored.StudentIDs...), studentIDs...)
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+type lessonEmployeeRepo struct {
+	employee.Repository
+	instructor *employee.Employee
+}
+
+func (r *lessonEmployeeRepo) FindByID(ctx context.Context, id uuid.UUID) (*employee.Employee, error) {
+	if r.instructor.ID != id {
+		return nil, errors.New("employee not found")
+	}
+	return r.instructor, nil
+}
+
+type lessonStudentRepo struct {
+	student.Repository
+	students map[uuid.UUID]*student.Student
+}
+
+func (r *lessonStudentRepo) GetByID(ctx context.Context, id uuid.UUID) (*student.Student, error) {
+	s, ok := r.students[id]
+	if !ok {
+		return nil, errors.New("student not found")
+	}
+	return s, nil
+}
+
+type lessonFixture struct {
+	uc         *LessonUseCase
+	sessions   *memSessionRepo
+	course     *course.Course
+	instructor *employee.Employee
+	students   []uuid.UUID
+	outsider   uuid.UUID
+}
+
+// newLessonFixture sets up an instructor with three students of their
+// institute and one student of another.
+func newLessonFixture() *lessonFixture {
+	instituteID := uuid.New()
+	f := &lessonFixture{
+		sessions:   &memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{}},
+		course:     &course.Course{ID: uuid.New(), Name: "Car Driving", IsActive: true},
+		instructor: &employee.Employee{ID: uuid.New(), InstituteID: instituteID, Status: employee.StatusActive},
+		outsider:   uuid.New(),
+	}
+	students := &lessonStudentRepo{students: map[uuid.UUID]*student.Student{
+		f.outsider: {ID: f.outsider, InstituteID: uuid.New()},
+	}}
+	for i := 0; i < 3; i++ {
+		id := uuid.New()
+		students.students[id] = &student.Student{ID: id, InstituteID: instituteID}
+		f.students = append(f.students, id)
+	}
+	f.uc = NewLessonUseCase(f.sessions, &certCourseRepo{crs: f.course}, &lessonEmployeeRepo{instructor: f.instructor}, students, nil, nopLogger{})
+	return f
+}
+
+func (f *lessonFixture) create(capacity int, studentIDs ...uuid.UUID) (*lesson.Session, error) {
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	return f.uc.Create(context.Background(), &lesson.CreateSessionRequest{
+		CourseID:     f.course.ID,
+		InstructorID: f.instructor.ID,
+		StartTime:    start,
+		EndTime:      start.Add(time.Hour),
+		Type:         lesson.TypeTheory,
+		Capacity:     capacity,
+		StudentIDs:   studentIDs,
+	})
+}
+
+func TestCreateSessionChecksCapacityAndStudents(t *testing.T) {
+	f := newLessonFixture()
+
+	session, err := f.create(2, f.students[0], f.students[1], f.students[0])
+	require.NoError(t, err)
+	assert.Equal(t, f.students[:2], session.StudentIDs)
+	assert.Equal(t, f.instructor.InstituteID, session.InstituteID)
+
+	_, err = f.create(2, f.students...)
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+
+	_, err = f.create(2, f.students[0], f.outsider)
+	assert.Equal(t, http.StatusNotFound, apperrors.GetStatusCode(err))
+
+	assert.Len(t, f.sessions.sessions, 1)
+}
+
+func TestAddStudents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("adds new students and skips booked ones", func(t *testing.T) {
+		f := newLessonFixture()
+		session, err := f.create(3, f.students[0])
+		require.NoError(t, err)
+
+		got, err := f.uc.AddStudents(ctx, session.ID, &lesson.RosterRequest{StudentIDs: []uuid.UUID{f.students[0], f.students[1], f.students[1]}})
+
+		require.NoError(t, err)
+		assert.Equal(t, f.students[:2], got.StudentIDs)
+		assert.Equal(t, f.students[:2], f.sessions.sessions[session.ID].StudentIDs)
+	})
+
+	t.Run("refuses to overfill the session", func(t *testing.T) {
+		f := newLessonFixture()
+		session, err := f.create(2, f.students[0])
+		require.NoError(t, err)
+
+		_, err = f.uc.AddStudents(ctx, session.ID, &lesson.RosterRequest{StudentIDs: f.students[1:]})
+
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+		assert.Contains(t, err.Error(), "session is full")
+		assert.Equal(t, f.students[:1], f.sessions.sessions[session.ID].StudentIDs)
+	})
+
+	t.Run("refuses students of another institute", func(t *testing.T) {
+		f := newLessonFixture()
+		session, err := f.create(3)
+		require.NoError(t, err)
+
+		_, err = f.uc.AddStudents(ctx, session.ID, &lesson.RosterRequest{StudentIDs: []uuid.UUID{f.students[0], f.outsider}})
+
+		assert.Equal(t, http.StatusNotFound, apperrors.GetStatusCode(err))
+		assert.Empty(t, f.sessions.sessions[session.ID].StudentIDs)
+	})
+
+	t.Run("refuses sessions that are not scheduled", func(t *testing.T) {
+		f := newLessonFixture()
+		session, err := f.create(3)
+		require.NoError(t, err)
+		session.Status = lesson.StatusCompleted
+
+		_, err = f.uc.AddStudents(ctx, session.ID, &lesson.RosterRequest{StudentIDs: f.students[:1]})
+
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	})
+}
+
+func TestUpdateSessionKeepsCapacityAboveRoster(t *testing.T) {
+	f := newLessonFixture()
+	session, err := f.create(3, f.students[0], f.students[1])
+	require.NoError(t, err)
+
+	one, two := 1, 2
+	_, err = f.uc.Update(context.Background(), session.ID, &lesson.UpdateSessionRequest{Capacity: &one})
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+
+	got, err := f.uc.Update(context.Background(), session.ID, &lesson.UpdateSessionRequest{Capacity: &two})
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Capacity)
+}
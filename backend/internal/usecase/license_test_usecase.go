@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/employee"
+	"github.com/chalak/backend/internal/domain/licensetest"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+type LicenseTestUseCase struct {
+	repo         licensetest.Repository
+	studentRepo  student.Repository
+	courseRepo   course.Repository
+	employeeRepo employee.Repository
+	maxAttempts  int
+	logger       logger.Logger
+}
+
+func NewLicenseTestUseCase(
+	repo licensetest.Repository,
+	studentRepo student.Repository,
+	courseRepo course.Repository,
+	employeeRepo employee.Repository,
+	maxAttempts int,
+	logger logger.Logger,
+) *LicenseTestUseCase {
+	return &LicenseTestUseCase{
+		repo:         repo,
+		studentRepo:  studentRepo,
+		courseRepo:   courseRepo,
+		employeeRepo: employeeRepo,
+		maxAttempts:  maxAttempts,
+		logger:       logger,
+	}
+}
+
+// Create schedules the student's next attempt at a test. A student may not
+// retake a test they passed, book a second attempt while one is pending, go
+// beyond maxAttempts, or sit the trial before passing the written test.
+// Graded attempts cannot be deleted, so they always count.
+func (uc *LicenseTestUseCase) Create(ctx context.Context, studentID uuid.UUID, req *licensetest.CreateLicenseTestRequest, userID uuid.UUID) (*licensetest.LicenseTest, error) {
+	s, err := uc.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+
+	if req.CourseID != nil {
+		if _, err := uc.courseRepo.GetByID(ctx, *req.CourseID); err != nil {
+			return nil, apperrors.NotFound("course not found")
+		}
+	}
+	if req.InstructorID != nil {
+		if err := uc.checkInstructor(ctx, *req.InstructorID, s.InstituteID); err != nil {
+			return nil, err
+		}
+	}
+
+	tests, _, err := uc.repo.List(ctx, licensetest.LicenseTestFilter{StudentID: &s.ID})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list license tests", err, map[string]interface{}{
+			"student_id": s.ID,
+		})
+		return nil, fmt.Errorf("failed to list license tests: %w", err)
+	}
+
+	attempts := 0
+	writtenPassed := false
+	for _, t := range tests {
+		if t.Type == licensetest.TypeWritten && t.Result == licensetest.ResultPassed {
+			writtenPassed = true
+		}
+		if t.Type != req.Type {
+			continue
+		}
+		switch t.Result {
+		case licensetest.ResultPassed:
+			return nil, apperrors.BadRequest(fmt.Sprintf("student has already passed the %s test", req.Type))
+		case licensetest.ResultPending:
+			return nil, apperrors.BadRequest(fmt.Sprintf("student already has a pending %s test", req.Type))
+		}
+		if t.AttemptNumber > attempts {
+			attempts = t.AttemptNumber
+		}
+	}
+
+	if req.Type == licensetest.TypeTrial && !writtenPassed {
+		return nil, apperrors.BadRequest("student must pass the written test before the trial")
+	}
+	if attempts >= uc.maxAttempts {
+		return nil, apperrors.BadRequest(fmt.Sprintf("student has used all %d attempts at the %s test", uc.maxAttempts, req.Type))
+	}
+
+	t := &licensetest.LicenseTest{
+		ID:            uuid.New(),
+		InstituteID:   s.InstituteID,
+		StudentID:     s.ID,
+		CourseID:      req.CourseID,
+		InstructorID:  req.InstructorID,
+		Type:          req.Type,
+		ScheduledDate: req.ScheduledDate,
+		TestCenter:    req.TestCenter,
+		Result:        licensetest.ResultPending,
+		AttemptNumber: attempts + 1,
+		Remarks:       req.Remarks,
+		CreatedBy:     userID,
+		CreatedAt:     time.Now().UTC(),
+		UpdatedAt:     time.Now().UTC(),
+	}
+
+	if err := uc.repo.Create(ctx, t); err != nil {
+		uc.logger.Error(ctx, "failed to create license test", err, map[string]interface{}{
+			"student_id": s.ID,
+			"type":       req.Type,
+		})
+		return nil, fmt.Errorf("failed to create license test: %w", err)
+	}
+
+	uc.logger.Info(ctx, "license test scheduled", map[string]interface{}{
+		"license_test_id": t.ID,
+		"student_id":      t.StudentID,
+		"type":            t.Type,
+		"attempt":         t.AttemptNumber,
+	})
+
+	return t, nil
+}
+
+func (uc *LicenseTestUseCase) GetByID(ctx context.Context, studentID, id uuid.UUID) (*licensetest.LicenseTest, error) {
+	t, err := uc.repo.FindByID(ctx, id)
+	if err != nil || t.StudentID != studentID {
+		return nil, apperrors.NotFound("license test not found")
+	}
+	return t, nil
+}
+
+func (uc *LicenseTestUseCase) Update(ctx context.Context, studentID, id uuid.UUID, req *licensetest.UpdateLicenseTestRequest) (*licensetest.LicenseTest, error) {
+	t, err := uc.GetByID(ctx, studentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ScheduledDate != nil {
+		t.ScheduledDate = *req.ScheduledDate
+	}
+	if req.TestCenter != nil {
+		t.TestCenter = *req.TestCenter
+	}
+	if req.InstructorID != nil {
+		if err := uc.checkInstructor(ctx, *req.InstructorID, t.InstituteID); err != nil {
+			return nil, err
+		}
+		t.InstructorID = req.InstructorID
+	}
+	if req.Result != nil {
+		if *req.Result != licensetest.ResultPending && t.ScheduledDate.After(time.Now()) {
+			return nil, apperrors.BadRequest("cannot record a result before the test date")
+		}
+		if *req.Result == licensetest.ResultPending && t.Result != licensetest.ResultPending {
+			return nil, apperrors.BadRequest("a recorded result can be corrected but not withdrawn")
+		}
+		t.Result = *req.Result
+	}
+	if req.Remarks != nil {
+		t.Remarks = *req.Remarks
+	}
+
+	t.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, t); err != nil {
+		uc.logger.Error(ctx, "failed to update license test", err, map[string]interface{}{
+			"license_test_id": id,
+		})
+		return nil, fmt.Errorf("failed to update license test: %w", err)
+	}
+
+	uc.logger.Info(ctx, "license test updated", map[string]interface{}{
+		"license_test_id": t.ID,
+		"result":          t.Result,
+	})
+
+	return t, nil
+}
+
+// Delete cancels a pending test. Tests with a result are kept: they count
+// towards the student's attempts.
+func (uc *LicenseTestUseCase) Delete(ctx context.Context, studentID, id uuid.UUID) error {
+	t, err := uc.GetByID(ctx, studentID, id)
+	if err != nil {
+		return err
+	}
+	if t.Result != licensetest.ResultPending {
+		return apperrors.BadRequest("only pending tests can be deleted")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete license test", err, map[string]interface{}{
+			"license_test_id": id,
+		})
+		return fmt.Errorf("failed to delete license test: %w", err)
+	}
+
+	uc.logger.Info(ctx, "license test deleted", map[string]interface{}{
+		"license_test_id": id,
+	})
+
+	return nil
+}
+
+func (uc *LicenseTestUseCase) List(ctx context.Context, filter licensetest.LicenseTestFilter) ([]*licensetest.LicenseTest, int64, error) {
+	if filter.StudentID != nil {
+		if _, err := uc.studentRepo.GetByID(ctx, *filter.StudentID); err != nil {
+			return nil, 0, apperrors.NotFound("student not found")
+		}
+	}
+
+	tests, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list license tests", err, nil)
+		return nil, 0, fmt.Errorf("failed to list license tests: %w", err)
+	}
+
+	return tests, total, nil
+}
+
+func (uc *LicenseTestUseCase) checkInstructor(ctx context.Context, instructorID, instituteID uuid.UUID) error {
+	instructor, err := uc.employeeRepo.FindByID(ctx, instructorID)
+	if err != nil || instructor.InstituteID != instituteID {
+		return apperrors.NotFound("instructor not found")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/licensetest"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memLicenseTestRepo struct {
+	licensetest.Repository
+	tests map[uuid.UUID]*licensetest.LicenseTest
+}
+
+func (r *memLicenseTestRepo) Create(ctx context.Context, t *licensetest.LicenseTest) error {
+	r.tests[t.ID] = t
+	return nil
+}
+
+func (r *memLicenseTestRepo) FindByID(ctx context.Context, id uuid.UUID) (*licensetest.LicenseTest, error) {
+	t, ok := r.tests[id]
+	if !ok {
+		return nil, errors.New("license test not found")
+	}
+	return t, nil
+}
+
+func (r *memLicenseTestRepo) Update(ctx context.Context, t *licensetest.LicenseTest) error {
+	r.tests[t.ID] = t
+	return nil
+}
+
+func (r *memLicenseTestRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.tests, id)
+	return nil
+}
+
+func (r *memLicenseTestRepo) List(ctx context.Context, filter licensetest.LicenseTestFilter) ([]*licensetest.LicenseTest, int64, error) {
+	var list []*licensetest.LicenseTest
+	for _, t := range r.tests {
+		if filter.StudentID == nil || t.StudentID == *filter.StudentID {
+			list = append(list, t)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func TestLicenseTestAttempts(t *testing.T) {
+	ctx := context.Background()
+	stu := &student.Student{ID: uuid.New(), InstituteID: uuid.New()}
+	repo := &memLicenseTestRepo{tests: map[uuid.UUID]*licensetest.LicenseTest{}}
+	uc := NewLicenseTestUseCase(repo, &certStudentRepo{stu: stu}, nil, nil, 2, nopLogger{})
+
+	failed, absent := licensetest.ResultFailed, licensetest.ResultAbsent
+	pending := licensetest.ResultPending
+	book := func() (*licensetest.LicenseTest, error) {
+		return uc.Create(ctx, stu.ID, &licensetest.CreateLicenseTestRequest{
+			Type:          licensetest.TypeWritten,
+			ScheduledDate: time.Now().AddDate(0, 0, -1),
+			TestCenter:    "Ekantakuna",
+		}, uuid.New())
+	}
+
+	first, err := book()
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.AttemptNumber)
+
+	t.Run("a pending booking can be cancelled", func(t *testing.T) {
+		extra := *first
+		extra.ID = uuid.New()
+		repo.tests[extra.ID] = &extra
+		require.NoError(t, uc.Delete(ctx, stu.ID, extra.ID))
+		assert.Len(t, repo.tests, 1)
+	})
+
+	_, err = uc.Update(ctx, stu.ID, first.ID, &licensetest.UpdateLicenseTestRequest{Result: &failed})
+	require.NoError(t, err)
+
+	t.Run("a graded attempt cannot be deleted", func(t *testing.T) {
+		err := uc.Delete(ctx, stu.ID, first.ID)
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+		assert.Contains(t, repo.tests, first.ID)
+	})
+
+	t.Run("a result can be corrected but not withdrawn", func(t *testing.T) {
+		_, err := uc.Update(ctx, stu.ID, first.ID, &licensetest.UpdateLicenseTestRequest{Result: &pending})
+		assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+
+		got, err := uc.Update(ctx, stu.ID, first.ID, &licensetest.UpdateLicenseTestRequest{Result: &absent})
+		require.NoError(t, err)
+		assert.Equal(t, licensetest.ResultAbsent, got.Result)
+	})
+
+	second, err := book()
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.AttemptNumber)
+	_, err = uc.Update(ctx, stu.ID, second.ID, &licensetest.UpdateLicenseTestRequest{Result: &failed})
+	require.NoError(t, err)
+
+	_, err = book()
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Contains(t, err.Error(), "all 2 attempts")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/chalak/backend/internal/domain/invoice"
 	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/internal/domain/transaction"
 	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/google/uuid"
 )
@@ -13,73 +14,76 @@ import (
 type PaymentUseCase struct {
 	paymentRepo payment.Repository
 	invoiceRepo invoice.Repository
+	tx          transaction.Manager
 }
 
-func NewPaymentUseCase(paymentRepo payment.Repository, invoiceRepo invoice.Repository) *PaymentUseCase {
+func NewPaymentUseCase(paymentRepo payment.Repository, invoiceRepo invoice.Repository, tx transaction.Manager) *PaymentUseCase {
 	return &PaymentUseCase{
 		paymentRepo: paymentRepo,
 		invoiceRepo: invoiceRepo,
+		tx:          tx,
 	}
 }
 
+// AddPayment records a payment against an invoice and updates its balance.
+// The invoice row stays locked until both are saved, so concurrent payments
+// on the same invoice are applied one after the other and cannot overpay it.
 func (uc *PaymentUseCase) AddPayment(ctx context.Context, req *payment.CreatePaymentRequest, userID uuid.UUID) (*payment.Payment, error) {
-	// Get invoice
-	inv, err := uc.invoiceRepo.FindByID(ctx, req.InvoiceID)
-	if err != nil {
-		return nil, apperrors.NotFound("invoice not found")
-	}
-
-	// Check if invoice is already paid or canceled
-	if inv.Status == invoice.StatusPaid {
-		return nil, apperrors.BadRequest("invoice is already fully paid")
-	}
-	if inv.Status == invoice.StatusCanceled {
-		return nil, apperrors.BadRequest("cannot add payment to canceled invoice")
-	}
+	var p *payment.Payment
 
-	// Check if payment amount is valid
-	remainingAmount := inv.TotalAmount - inv.PaidAmount
-	if req.Amount > remainingAmount {
-		return nil, apperrors.BadRequest("payment amount exceeds remaining balance")
-	}
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Get and lock invoice
+		inv, err := uc.invoiceRepo.FindByIDForUpdate(ctx, req.InvoiceID)
+		if err != nil {
+			return apperrors.NotFound("invoice not found")
+		}
 
-	// Create payment
-	p := &payment.Payment{
-		InvoiceID:     req.InvoiceID,
-		Amount:        req.Amount,
-		PaymentMethod: req.PaymentMethod,
-		PaymentDate:   req.PaymentDate,
-		Notes:         req.Notes,
-		CreatedBy:     userID,
-	}
+		// Check if invoice is already paid or canceled
+		if inv.Status == invoice.StatusPaid {
+			return apperrors.BadRequest("invoice is already fully paid")
+		}
+		if inv.Status == invoice.StatusCanceled {
+			return apperrors.BadRequest("cannot add payment to canceled invoice")
+		}
 
-	if p.PaymentDate.IsZero() {
-		p.PaymentDate = time.Now()
-	}
+		// Check if payment amount is valid
+		remainingAmount := inv.Balance()
+		if req.Amount > remainingAmount {
+			return apperrors.BadRequest("payment amount exceeds remaining balance").WithDetails(map[string]interface{}{
+				"remaining_amount": remainingAmount,
+			})
+		}
 
-	if err := uc.paymentRepo.Create(p); err != nil {
-		return nil, apperrors.New(err, "failed to create payment")
-	}
+		// Create payment
+		p = &payment.Payment{
+			InvoiceID:     req.InvoiceID,
+			Amount:        req.Amount,
+			PaymentMethod: req.PaymentMethod,
+			PaymentDate:   req.PaymentDate,
+			Notes:         req.Notes,
+			CreatedBy:     userID,
+		}
 
-	// Update invoice paid amount
-	inv.PaidAmount += req.Amount
+		if p.PaymentDate.IsZero() {
+			p.PaymentDate = time.Now()
+		}
 
-	// Update status based on paid amount
-	if inv.PaidAmount >= inv.TotalAmount {
-		inv.Status = invoice.StatusPaid
-		now := time.Now()
-		inv.PaidAt = &now
-	} else if inv.PaidAmount > 0 {
-		// Partial payment - keep as pending or check if overdue
-		if time.Now().After(inv.DueDate) {
-			inv.Status = invoice.StatusOverdue
-		} else {
-			inv.Status = invoice.StatusPending
+		if err := uc.paymentRepo.Create(ctx, p); err != nil {
+			return apperrors.New(err, "failed to create payment")
 		}
-	}
 
-	if err := uc.invoiceRepo.Update(ctx, inv); err != nil {
-		return nil, apperrors.New(err, "failed to update invoice")
+		// Update invoice paid amount
+		inv.PaidAmount += req.Amount
+
+		settleInvoice(inv, time.Now())
+
+		if err := uc.invoiceRepo.Update(ctx, inv); err != nil {
+			return apperrors.New(err, "failed to update invoice")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return p, nil
@@ -92,7 +96,7 @@ func (uc *PaymentUseCase) GetPaymentsByInvoice(ctx context.Context, invoiceID uu
 		return nil, apperrors.NotFound("invoice not found")
 	}
 
-	payments, err := uc.paymentRepo.GetByInvoiceID(invoiceID)
+	payments, err := uc.paymentRepo.GetByInvoiceID(ctx, invoiceID)
 	if err != nil {
 		return nil, apperrors.New(err, "failed to get payments")
 	}
@@ -101,9 +105,35 @@ func (uc *PaymentUseCase) GetPaymentsByInvoice(ctx context.Context, invoiceID uu
 }
 
 func (uc *PaymentUseCase) GetPaymentByID(ctx context.Context, id uuid.UUID) (*payment.Payment, error) {
-	p, err := uc.paymentRepo.GetByID(id)
+	p, err := uc.paymentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, apperrors.NotFound("payment not found")
 	}
+
+	// Payments carry no institute of their own; the invoice lookup is scoped.
+	if _, err := uc.invoiceRepo.FindByID(ctx, p.InvoiceID); err != nil {
+		return nil, apperrors.NotFound("payment not found")
+	}
 	return p, nil
 }
+
+// settleInvoice sets the invoice's status from what has been paid against
+// what is due once credit notes are taken off. An invoice credited in full
+// with nothing paid on it is canceled.
+func settleInvoice(inv *invoice.Invoice, now time.Time) {
+	switch {
+	case inv.PaidAmount > 0 && inv.Balance() <= 0:
+		inv.Status = invoice.StatusPaid
+		if inv.PaidAt == nil {
+			inv.PaidAt = &now
+		}
+		return
+	case inv.AmountDue() <= 0:
+		inv.Status = invoice.StatusCanceled
+	case now.After(inv.DueDate):
+		inv.Status = invoice.StatusOverdue
+	default:
+		inv.Status = invoice.StatusPending
+	}
+	inv.PaidAt = nil
+}
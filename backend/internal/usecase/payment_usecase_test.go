@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddPayment(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 7)
+
+	tests := []struct {
+		name       string
+		invoice    invoice.Invoice
+		amount     money.Amount
+		wantCode   int
+		wantStatus string
+		wantPaid   money.Amount
+	}{
+		{name: "part payment", invoice: invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending, DueDate: due}, amount: 4000, wantStatus: invoice.StatusPending, wantPaid: 4000},
+		{name: "settles the balance", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 4000, Status: invoice.StatusPending, DueDate: due}, amount: 6000, wantStatus: invoice.StatusPaid, wantPaid: 10000},
+		{name: "settles what credit notes left", invoice: invoice.Invoice{TotalAmount: 10000, CreditedAmount: 2500, Status: invoice.StatusPending, DueDate: due}, amount: 7500, wantStatus: invoice.StatusPaid, wantPaid: 7500},
+		{name: "overpays", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 4000, Status: invoice.StatusPending, DueDate: due}, amount: 6001, wantCode: http.StatusBadRequest},
+		{name: "overpays a credited invoice", invoice: invoice.Invoice{TotalAmount: 10000, CreditedAmount: 2500, Status: invoice.StatusPending, DueDate: due}, amount: 7501, wantCode: http.StatusBadRequest},
+		{name: "already paid", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 10000, Status: invoice.StatusPaid, DueDate: due}, amount: 1, wantCode: http.StatusBadRequest},
+		{name: "canceled", invoice: invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusCanceled, DueDate: due}, amount: 1000, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := tt.invoice
+			inv.ID = uuid.New()
+			invoices := newMemInvoiceRepo(&inv)
+			payments := newMemPaymentRepo()
+			tx := newMemTx(invoices, payments)
+			uc := NewPaymentUseCase(payments, invoices, tx)
+
+			p, err := uc.AddPayment(context.Background(), &payment.CreatePaymentRequest{
+				InvoiceID:     inv.ID,
+				Amount:        tt.amount,
+				PaymentMethod: "cash",
+			}, uuid.New())
+
+			assert.Equal(t, []uuid.UUID{inv.ID}, invoices.locked)
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Empty(t, payments.payments)
+				assert.Equal(t, tt.invoice.PaidAmount, invoices.invoices[inv.ID].PaidAmount)
+				assert.Equal(t, 1, tx.rollbacks)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, p.Amount)
+			assert.False(t, p.PaymentDate.IsZero())
+			assert.Len(t, payments.payments, 1)
+			assert.Equal(t, tt.wantPaid, invoices.invoices[inv.ID].PaidAmount)
+			assert.Equal(t, tt.wantStatus, invoices.invoices[inv.ID].Status)
+			assert.Equal(t, 1, tx.commits)
+		})
+	}
+}
+
+func TestAddPaymentRollsBackWhenTheInvoiceUpdateFails(t *testing.T) {
+	inv := &invoice.Invoice{ID: uuid.New(), TotalAmount: 10000, Status: invoice.StatusPending, DueDate: time.Now().AddDate(0, 0, 7)}
+	invoices := newMemInvoiceRepo(inv)
+	invoices.updateErr = errors.New("connection reset")
+	payments := newMemPaymentRepo()
+	tx := newMemTx(invoices, payments)
+	uc := NewPaymentUseCase(payments, invoices, tx)
+
+	_, err := uc.AddPayment(context.Background(), &payment.CreatePaymentRequest{
+		InvoiceID:     inv.ID,
+		Amount:        4000,
+		PaymentMethod: "cash",
+	}, uuid.New())
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, apperrors.GetStatusCode(err))
+	assert.Equal(t, 1, tx.rollbacks)
+	assert.Equal(t, 0, tx.commits)
+	assert.Empty(t, payments.payments, "the payment must not outlive the failed invoice update")
+	assert.Equal(t, money.Zero, invoices.invoices[inv.ID].PaidAmount)
+	assert.Equal(t, invoice.StatusPending, invoices.invoices[inv.ID].Status)
+}
+
+func TestSettleInvoice(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	paidAt := now.AddDate(0, 0, -3)
+
+	tests := []struct {
+		name       string
+		invoice    invoice.Invoice
+		wantStatus string
+		wantPaidAt *time.Time
+	}{
+		{name: "nothing paid", invoice: invoice.Invoice{TotalAmount: 10000, DueDate: now.AddDate(0, 0, 1)}, wantStatus: invoice.StatusPending},
+		{name: "past due", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 5000, DueDate: now.AddDate(0, 0, -1)}, wantStatus: invoice.StatusOverdue},
+		{name: "paid in full", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 10000, DueDate: now.AddDate(0, 0, -1)}, wantStatus: invoice.StatusPaid, wantPaidAt: &now},
+		{name: "keeps the first paid date", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 10000, PaidAt: &paidAt}, wantStatus: invoice.StatusPaid, wantPaidAt: &paidAt},
+		{name: "credit covers the balance", invoice: invoice.Invoice{TotalAmount: 10000, CreditedAmount: 4000, PaidAmount: 6000}, wantStatus: invoice.StatusPaid, wantPaidAt: &now},
+		{name: "credited in full with nothing paid", invoice: invoice.Invoice{TotalAmount: 10000, CreditedAmount: 10000, DueDate: now.AddDate(0, 0, 1)}, wantStatus: invoice.StatusCanceled},
+		{name: "reversed payment reopens", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 6000, Status: invoice.StatusPaid, PaidAt: &paidAt, DueDate: now.AddDate(0, 0, 1)}, wantStatus: invoice.StatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := tt.invoice
+			settleInvoice(&inv, now)
+			assert.Equal(t, tt.wantStatus, inv.Status)
+			assert.Equal(t, tt.wantPaidAt, inv.PaidAt)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/promo"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+type PromoUseCase struct {
+	repo   promo.Repository
+	logger logger.Logger
+}
+
+func NewPromoUseCase(repo promo.Repository, logger logger.Logger) *PromoUseCase {
+	return &PromoUseCase{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (uc *PromoUseCase) Create(ctx context.Context, req *promo.CreatePromoCodeRequest, createdBy uuid.UUID) (*promo.PromoCode, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if strings.ContainsAny(code, " \t") {
+		return nil, apperrors.BadRequest("promo code cannot contain spaces")
+	}
+
+	switch {
+	case req.DiscountType == promo.DiscountPercentage && req.Percentage == 0:
+		return nil, apperrors.BadRequest("percentage is required for a percentage discount")
+	case req.DiscountType == promo.DiscountFixed && req.Amount == 0:
+		return nil, apperrors.BadRequest("amount is required for a fixed discount")
+	}
+
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidUntil.After(*req.ValidFrom) {
+		return nil, apperrors.BadRequest("valid_until must be after valid_from")
+	}
+
+	instituteID := req.InstituteID
+	if instituteID == uuid.Nil {
+		instituteID, _ = tenant.InstituteID(ctx)
+	}
+
+	now := time.Now().UTC()
+	pc := &promo.PromoCode{
+		ID:                uuid.New(),
+		InstituteID:       instituteID,
+		Code:              code,
+		Description:       req.Description,
+		DiscountType:      req.DiscountType,
+		ValidFrom:         req.ValidFrom,
+		ValidUntil:        req.ValidUntil,
+		MaxUses:           req.MaxUses,
+		MaxUsesPerStudent: req.MaxUsesPerStudent,
+		IsActive:          true,
+		CreatedBy:         createdBy,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if pc.DiscountType == promo.DiscountPercentage {
+		pc.Percentage = req.Percentage
+	} else {
+		pc.Amount = req.Amount
+	}
+	for i := range req.PackageIDs {
+		pc.Targets = append(pc.Targets, promo.Target{ID: uuid.New(), PromoCodeID: pc.ID, PackageID: &req.PackageIDs[i]})
+	}
+	for i := range req.CourseIDs {
+		pc.Targets = append(pc.Targets, promo.Target{ID: uuid.New(), PromoCodeID: pc.ID, CourseID: &req.CourseIDs[i]})
+	}
+
+	if err := uc.repo.Create(ctx, pc); err != nil {
+		uc.logger.Error(ctx, "failed to create promo code", err, map[string]interface{}{
+			"code": code,
+		})
+		return nil, fmt.Errorf("failed to create promo code: %w", err)
+	}
+
+	uc.logger.Info(ctx, "promo code created", map[string]interface{}{
+		"promo_code_id": pc.ID,
+		"code":          pc.Code,
+	})
+
+	return pc, nil
+}
+
+// GetByID returns the code along with how often it has been used and what
+// it has cost so far.
+func (uc *PromoUseCase) GetByID(ctx context.Context, id uuid.UUID) (*promo.PromoCode, error) {
+	pc, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("promo code not found")
+	}
+
+	pc.Usage, err = uc.repo.Usage(ctx, id)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to get promo code usage", err, map[string]interface{}{
+			"promo_code_id": id,
+		})
+		return nil, fmt.Errorf("failed to get promo code usage: %w", err)
+	}
+
+	return pc, nil
+}
+
+func (uc *PromoUseCase) List(ctx context.Context, filter promo.PromoCodeFilter) ([]*promo.PromoCode, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	codes, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list promo codes", err, nil)
+		return nil, 0, fmt.Errorf("failed to list promo codes: %w", err)
+	}
+
+	return codes, total, nil
+}
+
+func (uc *PromoUseCase) Update(ctx context.Context, id uuid.UUID, req *promo.UpdatePromoCodeRequest) (*promo.PromoCode, error) {
+	pc, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("promo code not found")
+	}
+
+	if req.Description != nil {
+		pc.Description = *req.Description
+	}
+	if req.ValidFrom != nil {
+		pc.ValidFrom = req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		pc.ValidUntil = req.ValidUntil
+	}
+	if req.MaxUses != nil {
+		pc.MaxUses = *req.MaxUses
+	}
+	if req.MaxUsesPerStudent != nil {
+		pc.MaxUsesPerStudent = *req.MaxUsesPerStudent
+	}
+	if req.IsActive != nil {
+		pc.IsActive = *req.IsActive
+	}
+
+	if pc.ValidFrom != nil && pc.ValidUntil != nil && !pc.ValidUntil.After(*pc.ValidFrom) {
+		return nil, apperrors.BadRequest("valid_until must be after valid_from")
+	}
+
+	pc.UpdatedAt = time.Now().UTC()
+	if err := uc.repo.Update(ctx, pc); err != nil {
+		uc.logger.Error(ctx, "failed to update promo code", err, map[string]interface{}{
+			"promo_code_id": id,
+		})
+		return nil, fmt.Errorf("failed to update promo code: %w", err)
+	}
+
+	uc.logger.Info(ctx, "promo code updated", map[string]interface{}{
+		"promo_code_id": id,
+	})
+
+	return pc, nil
+}
+
+func (uc *PromoUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return apperrors.NotFound("promo code not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete promo code", err, map[string]interface{}{
+			"promo_code_id": id,
+		})
+		return fmt.Errorf("failed to delete promo code: %w", err)
+	}
+
+	uc.logger.Info(ctx, "promo code deleted", map[string]interface{}{
+		"promo_code_id": id,
+	})
+
+	return nil
+}
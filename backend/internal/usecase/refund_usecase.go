@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/internal/domain/refund"
+	"github.com/chalak/backend/internal/domain/transaction"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// RefundUseCase handles credit notes and payment reversals. Both are
+// requested with a reason and only touch the invoice once an approver signs
+// them off, at which point the invoice row is locked exactly as it is for a
+// new payment.
+type RefundUseCase struct {
+	repo        refund.Repository
+	invoiceRepo invoice.Repository
+	paymentRepo payment.Repository
+	tx          transaction.Manager
+	logger      logger.Logger
+}
+
+func NewRefundUseCase(
+	repo refund.Repository,
+	invoiceRepo invoice.Repository,
+	paymentRepo payment.Repository,
+	tx transaction.Manager,
+	logger logger.Logger,
+) *RefundUseCase {
+	return &RefundUseCase{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		paymentRepo: paymentRepo,
+		tx:          tx,
+		logger:      logger,
+	}
+}
+
+// RequestCreditNote asks for the amount due on an invoice to be lowered.
+func (uc *RefundUseCase) RequestCreditNote(ctx context.Context, invoiceID uuid.UUID, req *refund.CreateCreditNoteRequest, requestedBy uuid.UUID) (*refund.CreditNote, error) {
+	inv, err := uc.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, apperrors.NotFound("invoice not found")
+	}
+
+	if req.Amount > inv.AmountDue() {
+		return nil, apperrors.BadRequest("credit exceeds the amount due on the invoice").WithDetails(map[string]interface{}{
+			"amount_due": inv.AmountDue(),
+		})
+	}
+
+	now := time.Now().UTC()
+	note := &refund.CreditNote{
+		ID:          uuid.New(),
+		InstituteID: inv.InstituteID,
+		InvoiceID:   inv.ID,
+		Amount:      req.Amount,
+		Reason:      req.Reason,
+		Status:      refund.StatusPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := uc.repo.CreateCreditNote(ctx, note); err != nil {
+		uc.logger.Error(ctx, "failed to create credit note", err, map[string]interface{}{
+			"invoice_id": invoiceID,
+		})
+		return nil, fmt.Errorf("failed to create credit note: %w", err)
+	}
+
+	uc.logger.Info(ctx, "credit note requested", map[string]interface{}{
+		"credit_note_id": note.ID,
+		"invoice_id":     note.InvoiceID,
+		"amount":         note.Amount,
+	})
+
+	return note, nil
+}
+
+func (uc *RefundUseCase) ListCreditNotes(ctx context.Context, invoiceID uuid.UUID) ([]*refund.CreditNote, error) {
+	if _, err := uc.invoiceRepo.FindByID(ctx, invoiceID); err != nil {
+		return nil, apperrors.NotFound("invoice not found")
+	}
+
+	notes, err := uc.repo.ListCreditNotes(ctx, invoiceID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list credit notes", err, map[string]interface{}{
+			"invoice_id": invoiceID,
+		})
+		return nil, fmt.Errorf("failed to list credit notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// ApproveCreditNote takes the credit off the invoice's amount due. Whoever
+// asked for the credit cannot sign it off themselves.
+func (uc *RefundUseCase) ApproveCreditNote(ctx context.Context, id uuid.UUID, approvedBy uuid.UUID) (*refund.CreditNote, error) {
+	note, err := uc.repo.FindCreditNote(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("credit note not found")
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		inv, err := uc.invoiceRepo.FindByIDForUpdate(ctx, note.InvoiceID)
+		if err != nil {
+			return apperrors.NotFound("invoice not found")
+		}
+
+		// Reload under the invoice lock so two approvals cannot both apply.
+		note, err = uc.repo.FindCreditNote(ctx, id)
+		if err != nil {
+			return apperrors.NotFound("credit note not found")
+		}
+		if note.Status != refund.StatusPending {
+			return apperrors.BadRequest("only pending credit notes can be approved")
+		}
+		if note.RequestedBy == approvedBy {
+			return apperrors.Forbidden("a credit note must be approved by someone other than who requested it")
+		}
+		if note.Amount > inv.AmountDue() {
+			return apperrors.BadRequest("credit exceeds the amount due on the invoice").WithDetails(map[string]interface{}{
+				"amount_due": inv.AmountDue(),
+			})
+		}
+
+		now := time.Now().UTC()
+		inv.CreditedAmount += note.Amount
+		settleInvoice(inv, now)
+		if err := uc.invoiceRepo.Update(ctx, inv); err != nil {
+			return apperrors.New(err, "failed to update invoice")
+		}
+
+		note.Status = refund.StatusApproved
+		note.ApprovedBy = &approvedBy
+		note.ApprovedAt = &now
+		note.UpdatedAt = now
+		if err := uc.repo.UpdateCreditNote(ctx, note); err != nil {
+			return apperrors.New(err, "failed to update credit note")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info(ctx, "credit note approved", map[string]interface{}{
+		"credit_note_id": note.ID,
+		"invoice_id":     note.InvoiceID,
+		"approved_by":    approvedBy,
+	})
+
+	return note, nil
+}
+
+// RejectCreditNote closes a pending credit note without touching the
+// invoice. It takes the same lock as approval so the two cannot both win.
+func (uc *RefundUseCase) RejectCreditNote(ctx context.Context, id uuid.UUID, rejectedBy uuid.UUID) (*refund.CreditNote, error) {
+	note, err := uc.repo.FindCreditNote(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("credit note not found")
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.invoiceRepo.FindByIDForUpdate(ctx, note.InvoiceID); err != nil {
+			return apperrors.NotFound("invoice not found")
+		}
+
+		note, err = uc.repo.FindCreditNote(ctx, id)
+		if err != nil {
+			return apperrors.NotFound("credit note not found")
+		}
+		if note.Status != refund.StatusPending {
+			return apperrors.BadRequest("only pending credit notes can be rejected")
+		}
+
+		now := time.Now().UTC()
+		note.Status = refund.StatusRejected
+		note.ApprovedBy = &rejectedBy
+		note.ApprovedAt = &now
+		note.UpdatedAt = now
+		if err := uc.repo.UpdateCreditNote(ctx, note); err != nil {
+			return apperrors.New(err, "failed to reject credit note")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info(ctx, "credit note rejected", map[string]interface{}{
+		"credit_note_id": id,
+		"rejected_by":    rejectedBy,
+	})
+
+	return note, nil
+}
+
+// RequestReversal asks for all or part of a payment to be taken back. A
+// payment cannot be reversed for more than was paid, counting reversals
+// still awaiting approval.
+func (uc *RefundUseCase) RequestReversal(ctx context.Context, paymentID uuid.UUID, req *refund.CreateReversalRequest, requestedBy uuid.UUID) (*refund.Reversal, error) {
+	p, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, apperrors.NotFound("payment not found")
+	}
+
+	var rev *refund.Reversal
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Locking the invoice serializes requests against its payments.
+		inv, err := uc.invoiceRepo.FindByIDForUpdate(ctx, p.InvoiceID)
+		if err != nil {
+			return apperrors.NotFound("payment not found")
+		}
+
+		reversed, err := uc.repo.ReversedAmount(ctx, p.ID)
+		if err != nil {
+			return apperrors.New(err, "failed to get reversed amount")
+		}
+		if req.Amount > p.Amount-reversed {
+			return apperrors.BadRequest("reversal exceeds the amount left on the payment").WithDetails(map[string]interface{}{
+				"reversible_amount": p.Amount - reversed,
+			})
+		}
+
+		now := time.Now().UTC()
+		rev = &refund.Reversal{
+			ID:          uuid.New(),
+			InstituteID: inv.InstituteID,
+			InvoiceID:   inv.ID,
+			PaymentID:   p.ID,
+			Amount:      req.Amount,
+			Reason:      req.Reason,
+			Status:      refund.StatusPending,
+			RequestedBy: requestedBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := uc.repo.CreateReversal(ctx, rev); err != nil {
+			return apperrors.New(err, "failed to create payment reversal")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info(ctx, "payment reversal requested", map[string]interface{}{
+		"reversal_id": rev.ID,
+		"payment_id":  rev.PaymentID,
+		"amount":      rev.Amount,
+	})
+
+	return rev, nil
+}
+
+func (uc *RefundUseCase) ListReversals(ctx context.Context, paymentID uuid.UUID) ([]*refund.Reversal, error) {
+	p, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, apperrors.NotFound("payment not found")
+	}
+
+	// Payments carry no institute of their own; the invoice lookup is scoped.
+	if _, err := uc.invoiceRepo.FindByID(ctx, p.InvoiceID); err != nil {
+		return nil, apperrors.NotFound("payment not found")
+	}
+
+	reversals, err := uc.repo.ListReversals(ctx, paymentID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list payment reversals", err, map[string]interface{}{
+			"payment_id": paymentID,
+		})
+		return nil, fmt.Errorf("failed to list payment reversals: %w", err)
+	}
+
+	return reversals, nil
+}
+
+// ApproveReversal takes the reversed amount off what has been paid on the
+// invoice. The payment itself stays as it was recorded. Whoever asked for
+// the reversal cannot sign it off themselves.
+func (uc *RefundUseCase) ApproveReversal(ctx context.Context, id uuid.UUID, approvedBy uuid.UUID) (*refund.Reversal, error) {
+	rev, err := uc.repo.FindReversal(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("payment reversal not found")
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		inv, err := uc.invoiceRepo.FindByIDForUpdate(ctx, rev.InvoiceID)
+		if err != nil {
+			return apperrors.NotFound("invoice not found")
+		}
+
+		rev, err = uc.repo.FindReversal(ctx, id)
+		if err != nil {
+			return apperrors.NotFound("payment reversal not found")
+		}
+		if rev.Status != refund.StatusPending {
+			return apperrors.BadRequest("only pending payment reversals can be approved")
+		}
+		if rev.RequestedBy == approvedBy {
+			return apperrors.Forbidden("a payment reversal must be approved by someone other than who requested it")
+		}
+		if rev.Amount > inv.PaidAmount {
+			return apperrors.Conflict("reversal exceeds the amount paid on the invoice").WithDetails(map[string]interface{}{
+				"paid_amount": inv.PaidAmount,
+			})
+		}
+
+		now := time.Now().UTC()
+		inv.PaidAmount -= rev.Amount
+		settleInvoice(inv, now)
+		if err := uc.invoiceRepo.Update(ctx, inv); err != nil {
+			return apperrors.New(err, "failed to update invoice")
+		}
+
+		rev.Status = refund.StatusApproved
+		rev.ApprovedBy = &approvedBy
+		rev.ApprovedAt = &now
+		rev.UpdatedAt = now
+		if err := uc.repo.UpdateReversal(ctx, rev); err != nil {
+			return apperrors.New(err, "failed to update payment reversal")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info(ctx, "payment reversal approved", map[string]interface{}{
+		"reversal_id": rev.ID,
+		"payment_id":  rev.PaymentID,
+		"approved_by": approvedBy,
+	})
+
+	return rev, nil
+}
+
+// RejectReversal closes a pending payment reversal, leaving the invoice as
+// it is.
+func (uc *RefundUseCase) RejectReversal(ctx context.Context, id uuid.UUID, rejectedBy uuid.UUID) (*refund.Reversal, error) {
+	rev, err := uc.repo.FindReversal(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("payment reversal not found")
+	}
+
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.invoiceRepo.FindByIDForUpdate(ctx, rev.InvoiceID); err != nil {
+			return apperrors.NotFound("invoice not found")
+		}
+
+		rev, err = uc.repo.FindReversal(ctx, id)
+		if err != nil {
+			return apperrors.NotFound("payment reversal not found")
+		}
+		if rev.Status != refund.StatusPending {
+			return apperrors.BadRequest("only pending payment reversals can be rejected")
+		}
+
+		now := time.Now().UTC()
+		rev.Status = refund.StatusRejected
+		rev.ApprovedBy = &rejectedBy
+		rev.ApprovedAt = &now
+		rev.UpdatedAt = now
+		if err := uc.repo.UpdateReversal(ctx, rev); err != nil {
+			return apperrors.New(err, "failed to reject payment reversal")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info(ctx, "payment reversal rejected", map[string]interface{}{
+		"reversal_id": id,
+		"rejected_by": rejectedBy,
+	})
+
+	return rev, nil
+}
@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/invoice"
+	"github.com/chalak/backend/internal/domain/payment"
+	"github.com/chalak/backend/internal/domain/refund"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/money"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memRefundRepo keeps credit notes and reversals in maps.
+type memRefundRepo struct {
+	refund.Repository
+	notes     map[uuid.UUID]refund.CreditNote
+	reversals map[uuid.UUID]refund.Reversal
+	updateErr error
+}
+
+func newMemRefundRepo() *memRefundRepo {
+	return &memRefundRepo{notes: map[uuid.UUID]refund.CreditNote{}, reversals: map[uuid.UUID]refund.Reversal{}}
+}
+
+func (r *memRefundRepo) snapshot() func() {
+	notes := make(map[uuid.UUID]refund.CreditNote, len(r.notes))
+	for id, n := range r.notes {
+		notes[id] = n
+	}
+	reversals := make(map[uuid.UUID]refund.Reversal, len(r.reversals))
+	for id, rev := range r.reversals {
+		reversals[id] = rev
+	}
+	return func() { r.notes, r.reversals = notes, reversals }
+}
+
+func (r *memRefundRepo) CreateCreditNote(ctx context.Context, note *refund.CreditNote) error {
+	r.notes[note.ID] = *note
+	return nil
+}
+
+func (r *memRefundRepo) FindCreditNote(ctx context.Context, id uuid.UUID) (*refund.CreditNote, error) {
+	note, ok := r.notes[id]
+	if !ok {
+		return nil, errors.New("credit note not found")
+	}
+	return &note, nil
+}
+
+func (r *memRefundRepo) UpdateCreditNote(ctx context.Context, note *refund.CreditNote) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.notes[note.ID] = *note
+	return nil
+}
+
+func (r *memRefundRepo) CreateReversal(ctx context.Context, rev *refund.Reversal) error {
+	r.reversals[rev.ID] = *rev
+	return nil
+}
+
+func (r *memRefundRepo) FindReversal(ctx context.Context, id uuid.UUID) (*refund.Reversal, error) {
+	rev, ok := r.reversals[id]
+	if !ok {
+		return nil, errors.New("payment reversal not found")
+	}
+	return &rev, nil
+}
+
+func (r *memRefundRepo) UpdateReversal(ctx context.Context, rev *refund.Reversal) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.reversals[rev.ID] = *rev
+	return nil
+}
+
+func (r *memRefundRepo) ReversedAmount(ctx context.Context, paymentID uuid.UUID) (money.Amount, error) {
+	var total money.Amount
+	for _, rev := range r.reversals {
+		if rev.PaymentID == paymentID && rev.Status != refund.StatusRejected {
+			total += rev.Amount
+		}
+	}
+	return total, nil
+}
+
+type refundFixture struct {
+	uc       *RefundUseCase
+	tx       *memTx
+	invoices *memInvoiceRepo
+	payments *memPaymentRepo
+	refunds  *memRefundRepo
+	invoice  uuid.UUID
+}
+
+func newRefundFixture(inv invoice.Invoice, payments ...*payment.Payment) *refundFixture {
+	inv.ID = uuid.New()
+	if inv.DueDate.IsZero() {
+		inv.DueDate = time.Now().AddDate(0, 0, 7)
+	}
+	f := &refundFixture{
+		invoices: newMemInvoiceRepo(&inv),
+		payments: newMemPaymentRepo(payments...),
+		refunds:  newMemRefundRepo(),
+		invoice:  inv.ID,
+	}
+	for _, p := range payments {
+		p.InvoiceID = inv.ID
+	}
+	f.tx = newMemTx(f.invoices, f.payments, f.refunds)
+	f.uc = NewRefundUseCase(f.refunds, f.invoices, f.payments, f.tx, nopLogger{})
+	return f
+}
+
+func (f *refundFixture) pendingNote(amount money.Amount) uuid.UUID {
+	id := uuid.New()
+	f.refunds.notes[id] = refund.CreditNote{ID: id, InvoiceID: f.invoice, Amount: amount, Status: refund.StatusPending}
+	return id
+}
+
+func (f *refundFixture) pendingReversal(paymentID uuid.UUID, amount money.Amount) uuid.UUID {
+	id := uuid.New()
+	f.refunds.reversals[id] = refund.Reversal{ID: id, InvoiceID: f.invoice, PaymentID: paymentID, Amount: amount, Status: refund.StatusPending}
+	return id
+}
+
+func (f *refundFixture) stored() *invoice.Invoice {
+	return f.invoices.invoices[f.invoice]
+}
+
+func TestApproveCreditNote(t *testing.T) {
+	tests := []struct {
+		name         string
+		invoice      invoice.Invoice
+		amount       money.Amount
+		wantCode     int
+		wantStatus   string
+		wantCredited money.Amount
+	}{
+		{name: "part of the balance", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 2000, Status: invoice.StatusPending}, amount: 3000, wantStatus: invoice.StatusPending, wantCredited: 3000},
+		{name: "settles what was not paid", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 6000, Status: invoice.StatusPending}, amount: 4000, wantStatus: invoice.StatusPaid, wantCredited: 4000},
+		{name: "credits an unpaid invoice in full", invoice: invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending}, amount: 10000, wantStatus: invoice.StatusCanceled, wantCredited: 10000},
+		{name: "over-credit", invoice: invoice.Invoice{TotalAmount: 10000, CreditedAmount: 7000, Status: invoice.StatusPending}, amount: 3001, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefundFixture(tt.invoice)
+			id := f.pendingNote(tt.amount)
+			approver := uuid.New()
+
+			note, err := f.uc.ApproveCreditNote(context.Background(), id, approver)
+
+			assert.Equal(t, []uuid.UUID{f.invoice}, f.invoices.locked)
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Equal(t, 1, f.tx.rollbacks)
+				assert.Equal(t, tt.invoice.CreditedAmount, f.stored().CreditedAmount)
+				assert.Equal(t, refund.StatusPending, f.refunds.notes[id].Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, refund.StatusApproved, note.Status)
+			assert.Equal(t, &approver, note.ApprovedBy)
+			assert.Equal(t, refund.StatusApproved, f.refunds.notes[id].Status)
+			assert.Equal(t, tt.wantCredited, f.stored().CreditedAmount)
+			assert.Equal(t, tt.wantStatus, f.stored().Status)
+			assert.Equal(t, 1, f.tx.commits)
+		})
+	}
+}
+
+func TestApproveCreditNoteChecksTheBalanceLeftByEarlierCredits(t *testing.T) {
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending})
+	first := f.pendingNote(6000)
+	second := f.pendingNote(6000)
+
+	_, err := f.uc.ApproveCreditNote(context.Background(), first, uuid.New())
+	require.NoError(t, err)
+	_, err = f.uc.ApproveCreditNote(context.Background(), second, uuid.New())
+
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Equal(t, money.Amount(6000), f.stored().CreditedAmount)
+	assert.Equal(t, refund.StatusPending, f.refunds.notes[second].Status)
+}
+
+func TestApproveCreditNoteRollsBackWhenTheNoteUpdateFails(t *testing.T) {
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending})
+	id := f.pendingNote(4000)
+	f.refunds.updateErr = errors.New("connection reset")
+
+	_, err := f.uc.ApproveCreditNote(context.Background(), id, uuid.New())
+
+	require.Error(t, err)
+	assert.Equal(t, 1, f.tx.rollbacks)
+	assert.Equal(t, money.Zero, f.stored().CreditedAmount, "the credit must not outlive the failed note update")
+}
+
+func TestRequestCreditNoteRejectsOverCredit(t *testing.T) {
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, PaidAmount: 4000, CreditedAmount: 1000, Status: invoice.StatusPending})
+
+	_, err := f.uc.RequestCreditNote(context.Background(), f.invoice, &refund.CreateCreditNoteRequest{Amount: 9001, Reason: "discount"}, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+
+	note, err := f.uc.RequestCreditNote(context.Background(), f.invoice, &refund.CreateCreditNoteRequest{Amount: 9000, Reason: "discount"}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, refund.StatusPending, note.Status)
+	assert.Equal(t, money.Amount(1000), f.stored().CreditedAmount, "a request leaves the invoice alone")
+}
+
+func TestRejectCreditNote(t *testing.T) {
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending})
+	id := f.pendingNote(4000)
+	rejecter := uuid.New()
+
+	note, err := f.uc.RejectCreditNote(context.Background(), id, rejecter)
+	require.NoError(t, err)
+	assert.Equal(t, refund.StatusRejected, note.Status)
+	assert.Equal(t, &rejecter, note.ApprovedBy)
+	assert.Equal(t, refund.StatusRejected, f.refunds.notes[id].Status)
+	assert.Equal(t, []uuid.UUID{f.invoice}, f.invoices.locked)
+	assert.Equal(t, money.Zero, f.stored().CreditedAmount)
+
+	_, err = f.uc.ApproveCreditNote(context.Background(), id, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Equal(t, money.Zero, f.stored().CreditedAmount)
+}
+
+func TestRejectCreditNoteSeesAnApprovalThatWonTheLock(t *testing.T) {
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending})
+	id := f.pendingNote(4000)
+
+	// The approval commits between the reject's first read and its lock.
+	f.invoices.onLock = func() {
+		note := f.refunds.notes[id]
+		note.Status = refund.StatusApproved
+		f.refunds.notes[id] = note
+	}
+
+	_, err := f.uc.RejectCreditNote(context.Background(), id, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.NotEqual(t, refund.StatusRejected, f.refunds.notes[id].Status)
+}
+
+func TestRequestReversalCountsPendingReversals(t *testing.T) {
+	p := &payment.Payment{ID: uuid.New(), Amount: 5000}
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, PaidAmount: 5000, Status: invoice.StatusPending}, p)
+	f.pendingReversal(p.ID, 3000)
+
+	_, err := f.uc.RequestReversal(context.Background(), p.ID, &refund.CreateReversalRequest{Amount: 2001, Reason: "duplicate"}, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Len(t, f.refunds.reversals, 1)
+
+	rev, err := f.uc.RequestReversal(context.Background(), p.ID, &refund.CreateReversalRequest{Amount: 2000, Reason: "duplicate"}, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, refund.StatusPending, rev.Status)
+	assert.Len(t, f.refunds.reversals, 2)
+}
+
+func TestApproveReversal(t *testing.T) {
+	paidAt := time.Now().AddDate(0, 0, -1)
+
+	tests := []struct {
+		name       string
+		invoice    invoice.Invoice
+		amount     money.Amount
+		wantCode   int
+		wantStatus string
+		wantPaid   money.Amount
+	}{
+		{name: "reopens a paid invoice", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 10000, Status: invoice.StatusPaid, PaidAt: &paidAt}, amount: 4000, wantStatus: invoice.StatusPending, wantPaid: 6000},
+		{name: "goes overdue past the due date", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 10000, Status: invoice.StatusPaid, PaidAt: &paidAt, DueDate: time.Now().AddDate(0, 0, -1)}, amount: 10000, wantStatus: invoice.StatusOverdue, wantPaid: 0},
+		{name: "over-reversal", invoice: invoice.Invoice{TotalAmount: 10000, PaidAmount: 3000, Status: invoice.StatusPending}, amount: 3001, wantCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &payment.Payment{ID: uuid.New(), Amount: tt.amount}
+			f := newRefundFixture(tt.invoice, p)
+			id := f.pendingReversal(p.ID, tt.amount)
+
+			rev, err := f.uc.ApproveReversal(context.Background(), id, uuid.New())
+
+			assert.Equal(t, []uuid.UUID{f.invoice}, f.invoices.locked)
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				assert.Equal(t, 1, f.tx.rollbacks)
+				assert.Equal(t, tt.invoice.PaidAmount, f.stored().PaidAmount)
+				assert.Equal(t, refund.StatusPending, f.refunds.reversals[id].Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, refund.StatusApproved, rev.Status)
+			assert.Equal(t, tt.wantPaid, f.stored().PaidAmount)
+			assert.Equal(t, tt.wantStatus, f.stored().Status)
+			assert.Nil(t, f.stored().PaidAt)
+			assert.Equal(t, 1, f.tx.commits)
+		})
+	}
+}
+
+func TestRefundsNeedASecondPerson(t *testing.T) {
+	ctx := context.Background()
+	requester := uuid.New()
+
+	t.Run("credit note", func(t *testing.T) {
+		f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, Status: invoice.StatusPending})
+		note, err := f.uc.RequestCreditNote(ctx, f.invoice, &refund.CreateCreditNoteRequest{Amount: 4000, Reason: "discount"}, requester)
+		require.NoError(t, err)
+
+		_, err = f.uc.ApproveCreditNote(ctx, note.ID, requester)
+		assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+		assert.Equal(t, refund.StatusPending, f.refunds.notes[note.ID].Status)
+		assert.Zero(t, f.stored().CreditedAmount)
+
+		approved, err := f.uc.ApproveCreditNote(ctx, note.ID, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, refund.StatusApproved, approved.Status)
+	})
+
+	t.Run("payment reversal", func(t *testing.T) {
+		p := &payment.Payment{ID: uuid.New(), Amount: 5000}
+		f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, PaidAmount: 5000, Status: invoice.StatusPending}, p)
+		rev, err := f.uc.RequestReversal(ctx, p.ID, &refund.CreateReversalRequest{Amount: 5000, Reason: "duplicate"}, requester)
+		require.NoError(t, err)
+
+		_, err = f.uc.ApproveReversal(ctx, rev.ID, requester)
+		assert.Equal(t, http.StatusForbidden, apperrors.GetStatusCode(err))
+		assert.Equal(t, refund.StatusPending, f.refunds.reversals[rev.ID].Status)
+		assert.Equal(t, money.Amount(5000), f.stored().PaidAmount)
+
+		approved, err := f.uc.ApproveReversal(ctx, rev.ID, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, refund.StatusApproved, approved.Status)
+	})
+}
+
+func TestRejectReversal(t *testing.T) {
+	p := &payment.Payment{ID: uuid.New(), Amount: 5000}
+	f := newRefundFixture(invoice.Invoice{TotalAmount: 10000, PaidAmount: 5000, Status: invoice.StatusPending}, p)
+	id := f.pendingReversal(p.ID, 5000)
+
+	rev, err := f.uc.RejectReversal(context.Background(), id, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, refund.StatusRejected, rev.Status)
+	assert.Equal(t, []uuid.UUID{f.invoice}, f.invoices.locked)
+	assert.Equal(t, money.Amount(5000), f.stored().PaidAmount)
+
+	_, err = f.uc.RejectReversal(context.Background(), id, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+
+	_, err = f.uc.ApproveReversal(context.Background(), id, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Equal(t, money.Amount(5000), f.stored().PaidAmount)
+}
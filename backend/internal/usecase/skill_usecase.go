@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/skill"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSkillMaxScore  = 5
+	defaultSkillPassScore = 4
+)
+
+type SkillUseCase struct {
+	repo           skill.Repository
+	courseRepo     course.Repository
+	attendanceRepo attendance.Repository
+	lessonRepo     lesson.Repository
+	studentRepo    student.Repository
+	logger         logger.Logger
+}
+
+func NewSkillUseCase(
+	repo skill.Repository,
+	courseRepo course.Repository,
+	attendanceRepo attendance.Repository,
+	lessonRepo lesson.Repository,
+	studentRepo student.Repository,
+	logger logger.Logger,
+) *SkillUseCase {
+	return &SkillUseCase{
+		repo:           repo,
+		courseRepo:     courseRepo,
+		attendanceRepo: attendanceRepo,
+		lessonRepo:     lessonRepo,
+		studentRepo:    studentRepo,
+		logger:         logger,
+	}
+}
+
+func (uc *SkillUseCase) Create(ctx context.Context, courseID uuid.UUID, req *skill.CreateSkillRequest) (*skill.Skill, error) {
+	if _, err := uc.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, apperrors.NotFound("course not found")
+	}
+
+	s := &skill.Skill{
+		ID:          uuid.New(),
+		InstituteID: req.InstituteID,
+		CourseID:    courseID,
+		Name:        req.Name,
+		Description: req.Description,
+		MaxScore:    req.MaxScore,
+		PassScore:   req.PassScore,
+		SortOrder:   req.SortOrder,
+		IsActive:    true,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if s.MaxScore == 0 {
+		s.MaxScore = defaultSkillMaxScore
+	}
+	if s.PassScore == 0 {
+		s.PassScore = min(defaultSkillPassScore, s.MaxScore)
+	}
+	if s.PassScore > s.MaxScore {
+		return nil, apperrors.BadRequest("pass score cannot exceed max score")
+	}
+
+	if err := uc.repo.Create(ctx, s); err != nil {
+		uc.logger.Error(ctx, "failed to create skill", err, map[string]interface{}{
+			"course_id": courseID,
+		})
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+
+	uc.logger.Info(ctx, "skill created", map[string]interface{}{
+		"skill_id":  s.ID,
+		"course_id": s.CourseID,
+	})
+
+	return s, nil
+}
+
+func (uc *SkillUseCase) Update(ctx context.Context, courseID, id uuid.UUID, req *skill.UpdateSkillRequest) (*skill.Skill, error) {
+	s, err := uc.repo.FindByID(ctx, id)
+	if err != nil || s.CourseID != courseID {
+		return nil, apperrors.NotFound("skill not found")
+	}
+
+	if req.Name != nil {
+		s.Name = *req.Name
+	}
+	if req.Description != nil {
+		s.Description = *req.Description
+	}
+	if req.MaxScore != nil {
+		s.MaxScore = *req.MaxScore
+	}
+	if req.PassScore != nil {
+		s.PassScore = *req.PassScore
+	}
+	if req.SortOrder != nil {
+		s.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		s.IsActive = *req.IsActive
+	}
+	if s.PassScore > s.MaxScore {
+		return nil, apperrors.BadRequest("pass score cannot exceed max score")
+	}
+
+	s.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, s); err != nil {
+		uc.logger.Error(ctx, "failed to update skill", err, map[string]interface{}{
+			"skill_id": id,
+		})
+		return nil, fmt.Errorf("failed to update skill: %w", err)
+	}
+
+	return s, nil
+}
+
+func (uc *SkillUseCase) Delete(ctx context.Context, courseID, id uuid.UUID) error {
+	s, err := uc.repo.FindByID(ctx, id)
+	if err != nil || s.CourseID != courseID {
+		return apperrors.NotFound("skill not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete skill", err, map[string]interface{}{
+			"skill_id": id,
+		})
+		return fmt.Errorf("failed to delete skill: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *SkillUseCase) List(ctx context.Context, courseID uuid.UUID) ([]*skill.Skill, error) {
+	if _, err := uc.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, apperrors.NotFound("course not found")
+	}
+
+	skills, err := uc.repo.ListByCourse(ctx, courseID, nil, false)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list skills", err, map[string]interface{}{
+			"course_id": courseID,
+		})
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+
+	return skills, nil
+}
+
+// Grade records skill scores for an attended practical lesson. Only skills
+// on the institute's checklist for the lesson's course can be graded.
+func (uc *SkillUseCase) Grade(ctx context.Context, attendanceID uuid.UUID, req *skill.GradeRequest, gradedBy uuid.UUID) ([]*skill.Score, error) {
+	att, err := uc.attendanceRepo.FindByID(ctx, attendanceID)
+	if err != nil {
+		return nil, apperrors.NotFound("attendance not found")
+	}
+	if att.Status != attendance.StatusPresent && att.Status != attendance.StatusLate {
+		return nil, apperrors.BadRequest("only students who attended can be graded")
+	}
+
+	session, err := uc.lessonRepo.FindByID(ctx, att.ClassID)
+	if err != nil {
+		return nil, apperrors.NotFound("class not found")
+	}
+	if session.Type != lesson.TypePractical {
+		return nil, apperrors.BadRequest("skills are only graded in practical lessons")
+	}
+
+	scores := make([]*skill.Score, 0, len(req.Scores))
+	seen := make(map[uuid.UUID]bool, len(req.Scores))
+	for _, input := range req.Scores {
+		if seen[input.SkillID] {
+			return nil, apperrors.BadRequest("each skill can only be graded once per lesson")
+		}
+		seen[input.SkillID] = true
+
+		s, err := uc.repo.FindByID(ctx, input.SkillID)
+		if err != nil || s.CourseID != session.CourseID || s.InstituteID != session.InstituteID || !s.IsActive {
+			return nil, apperrors.BadRequest(fmt.Sprintf("skill %s is not on this course's checklist", input.SkillID))
+		}
+		if input.Score > s.MaxScore {
+			return nil, apperrors.BadRequest(fmt.Sprintf("score for %s cannot exceed %d", s.Name, s.MaxScore))
+		}
+
+		scores = append(scores, &skill.Score{
+			ID:           uuid.New(),
+			AttendanceID: att.ID,
+			SkillID:      s.ID,
+			StudentID:    att.StudentID,
+			Score:        input.Score,
+			Comment:      input.Comment,
+			GradedBy:     gradedBy,
+			CreatedAt:    time.Now().UTC(),
+			UpdatedAt:    time.Now().UTC(),
+		})
+	}
+
+	if err := uc.repo.SaveScores(ctx, scores); err != nil {
+		uc.logger.Error(ctx, "failed to save skill scores", err, map[string]interface{}{
+			"attendance_id": attendanceID,
+		})
+		return nil, fmt.Errorf("failed to save skill scores: %w", err)
+	}
+
+	uc.logger.Info(ctx, "skills graded", map[string]interface{}{
+		"attendance_id": att.ID,
+		"student_id":    att.StudentID,
+		"skills":        len(scores),
+	})
+
+	return uc.GetScores(ctx, attendanceID)
+}
+
+func (uc *SkillUseCase) GetScores(ctx context.Context, attendanceID uuid.UUID) ([]*skill.Score, error) {
+	if _, err := uc.attendanceRepo.FindByID(ctx, attendanceID); err != nil {
+		return nil, apperrors.NotFound("attendance not found")
+	}
+
+	scores, err := uc.repo.ListScoresByAttendance(ctx, attendanceID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list skill scores", err, map[string]interface{}{
+			"attendance_id": attendanceID,
+		})
+		return nil, fmt.Errorf("failed to list skill scores: %w", err)
+	}
+
+	return scores, nil
+}
+
+// GetProgress reports the student's latest score and trend on every active
+// skill of their institute's checklist for the course, and whether they are
+// ready for the trial.
+func (uc *SkillUseCase) GetProgress(ctx context.Context, studentID, courseID uuid.UUID) (*skill.Progress, error) {
+	stu, err := uc.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, apperrors.NotFound("student not found")
+	}
+	if _, err := uc.courseRepo.GetByID(ctx, courseID); err != nil {
+		return nil, apperrors.NotFound("course not found")
+	}
+
+	skills, err := uc.repo.ListByCourse(ctx, courseID, &stu.InstituteID, true)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list skills", err, map[string]interface{}{
+			"course_id": courseID,
+		})
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+
+	history, err := uc.repo.ListScoreHistory(ctx, studentID, courseID)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list skill score history", err, map[string]interface{}{
+			"student_id": studentID,
+			"course_id":  courseID,
+		})
+		return nil, fmt.Errorf("failed to list skill score history: %w", err)
+	}
+
+	progress := buildProgress(skills, history)
+	progress.StudentID = studentID
+	progress.CourseID = courseID
+	return progress, nil
+}
+
+// buildProgress folds a date-ordered score history into per-skill progress.
+func buildProgress(skills []*skill.Skill, history []skill.ScoreEntry) *skill.Progress {
+	bySkill := make(map[uuid.UUID][]skill.ScoreEntry, len(skills))
+	for _, entry := range history {
+		bySkill[entry.SkillID] = append(bySkill[entry.SkillID], entry)
+	}
+
+	progress := &skill.Progress{
+		Skills: make([]skill.SkillProgress, 0, len(skills)),
+		Ready:  len(skills) > 0,
+	}
+
+	for _, s := range skills {
+		entries := bySkill[s.ID]
+		sp := skill.SkillProgress{
+			SkillID:   s.ID,
+			Name:      s.Name,
+			MaxScore:  s.MaxScore,
+			PassScore: s.PassScore,
+			History:   make([]skill.ScoreEntry, 0, len(entries)),
+		}
+		sp.History = append(sp.History, entries...)
+
+		if n := len(entries); n > 0 {
+			total := 0
+			for _, e := range entries {
+				total += e.Score
+			}
+			sp.Average = float64(total) / float64(n)
+
+			latest := entries[n-1].Score
+			sp.Latest = &latest
+
+			if n > 1 {
+				prior := entries[max(0, n-4) : n-1]
+				priorTotal := 0
+				for _, e := range prior {
+					priorTotal += e.Score
+				}
+				priorAvg := float64(priorTotal) / float64(len(prior))
+				switch {
+				case float64(latest) > priorAvg:
+					sp.Trend = skill.TrendImproving
+				case float64(latest) < priorAvg:
+					sp.Trend = skill.TrendDeclining
+				default:
+					sp.Trend = skill.TrendSteady
+				}
+			}
+		}
+
+		if sp.Latest == nil || *sp.Latest < s.PassScore {
+			progress.Ready = false
+		}
+		progress.Skills = append(progress.Skills, sp)
+	}
+
+	return progress
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	"github.com/chalak/backend/internal/domain/course"
+	"github.com/chalak/backend/internal/domain/lesson"
+	"github.com/chalak/backend/internal/domain/skill"
+	"github.com/chalak/backend/internal/domain/student"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memSkillRepo struct {
+	skill.Repository
+	skills []*skill.Skill
+	scores []*skill.Score
+}
+
+func (r *memSkillRepo) FindByID(ctx context.Context, id uuid.UUID) (*skill.Skill, error) {
+	for _, s := range r.skills {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, errors.New("skill not found")
+}
+
+func (r *memSkillRepo) ListByCourse(ctx context.Context, courseID uuid.UUID, instituteID *uuid.UUID, activeOnly bool) ([]*skill.Skill, error) {
+	var list []*skill.Skill
+	for _, s := range r.skills {
+		if s.CourseID != courseID || (instituteID != nil && s.InstituteID != *instituteID) || (activeOnly && !s.IsActive) {
+			continue
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (r *memSkillRepo) SaveScores(ctx context.Context, scores []*skill.Score) error {
+	r.scores = append(r.scores, scores...)
+	return nil
+}
+
+func (r *memSkillRepo) ListScoresByAttendance(ctx context.Context, attendanceID uuid.UUID) ([]*skill.Score, error) {
+	var list []*skill.Score
+	for _, s := range r.scores {
+		if s.AttendanceID == attendanceID {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+
+func (r *memSkillRepo) ListScoreHistory(ctx context.Context, studentID, courseID uuid.UUID) ([]skill.ScoreEntry, error) {
+	var entries []skill.ScoreEntry
+	for _, s := range r.scores {
+		if s.StudentID == studentID {
+			entries = append(entries, skill.ScoreEntry{SkillID: s.SkillID, AttendanceID: s.AttendanceID, Score: s.Score})
+		}
+	}
+	return entries, nil
+}
+
+func TestSkillChecklistsBelongToTheirInstitute(t *testing.T) {
+	ctx := context.Background()
+	crs := &course.Course{ID: uuid.New(), Name: "Car Driving", IsActive: true}
+	stu := &student.Student{ID: uuid.New(), InstituteID: uuid.New()}
+	checklist := func(instituteID uuid.UUID, name string) *skill.Skill {
+		return &skill.Skill{ID: uuid.New(), InstituteID: instituteID, CourseID: crs.ID, Name: name, MaxScore: 5, PassScore: 4, IsActive: true}
+	}
+	own, other := checklist(stu.InstituteID, "Hill start"), checklist(uuid.New(), "Reverse parking")
+
+	session := &lesson.Session{ID: uuid.New(), InstituteID: stu.InstituteID, CourseID: crs.ID, Type: lesson.TypePractical}
+	att := &attendance.Attendance{ID: uuid.New(), StudentID: stu.ID, ClassID: session.ID, Status: attendance.StatusPresent}
+
+	skills := &memSkillRepo{skills: []*skill.Skill{own, other}}
+	uc := NewSkillUseCase(
+		skills,
+		&certCourseRepo{crs: crs},
+		&memAttendanceRepo{records: map[uuid.UUID]*attendance.Attendance{att.ID: att}},
+		&memSessionRepo{sessions: map[uuid.UUID]*lesson.Session{session.ID: session}},
+		&certStudentRepo{stu: stu},
+		nopLogger{},
+	)
+
+	_, err := uc.Grade(ctx, att.ID, &skill.GradeRequest{Scores: []skill.ScoreInput{{SkillID: other.ID, Score: 5}}}, uuid.New())
+	assert.Equal(t, http.StatusBadRequest, apperrors.GetStatusCode(err))
+	assert.Empty(t, skills.scores)
+
+	scores, err := uc.Grade(ctx, att.ID, &skill.GradeRequest{Scores: []skill.ScoreInput{{SkillID: own.ID, Score: 4}}}, uuid.New())
+	require.NoError(t, err)
+	require.Len(t, scores, 1)
+
+	progress, err := uc.GetProgress(ctx, stu.ID, crs.ID)
+	require.NoError(t, err)
+	require.Len(t, progress.Skills, 1)
+	assert.Equal(t, own.ID, progress.Skills[0].SkillID)
+	assert.True(t, progress.Ready, "another institute's checklist does not hold the student back")
+}
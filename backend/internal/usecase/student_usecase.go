@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/internal/domain/user"
+	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/tenant"
 	"github.com/google/uuid"
 )
 
@@ -19,14 +22,16 @@ type StudentUseCase interface {
 }
 
 type studentUseCase struct {
-	repo   student.Repository
-	logger logger.Logger
+	repo     student.Repository
+	userRepo user.Repository
+	logger   logger.Logger
 }
 
-func NewStudentUseCase(repo student.Repository, log logger.Logger) StudentUseCase {
+func NewStudentUseCase(repo student.Repository, userRepo user.Repository, log logger.Logger) StudentUseCase {
 	return &studentUseCase{
-		repo:   repo,
-		logger: log,
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   log,
 	}
 }
 
@@ -46,6 +51,16 @@ func (uc *studentUseCase) CreateStudent(ctx context.Context, req student.CreateS
 		}
 	}
 
+	instituteID := req.InstituteID
+	if id, ok := tenant.InstituteID(ctx); ok && instituteID == uuid.Nil {
+		instituteID = id
+	}
+	if req.UserID != nil {
+		if err := uc.checkUser(ctx, *req.UserID, instituteID, uuid.Nil); err != nil {
+			return nil, err
+		}
+	}
+
 	s := &student.Student{
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
@@ -54,6 +69,7 @@ func (uc *studentUseCase) CreateStudent(ctx context.Context, req student.CreateS
 		DateOfBirth: req.DateOfBirth,
 		Address:     req.Address,
 		InstituteID: req.InstituteID,
+		UserID:      req.UserID,
 		Status:      "active",
 		EnrolledAt:  time.Now().UTC(),
 	}
@@ -119,6 +135,12 @@ func (uc *studentUseCase) UpdateStudent(ctx context.Context, id uuid.UUID, req s
 	if req.Status != nil {
 		s.Status = *req.Status
 	}
+	if req.UserID != nil && (s.UserID == nil || *req.UserID != *s.UserID) {
+		if err := uc.checkUser(ctx, *req.UserID, s.InstituteID, s.ID); err != nil {
+			return nil, err
+		}
+		s.UserID = req.UserID
+	}
 
 	if err := uc.repo.Update(ctx, s); err != nil {
 		uc.logger.Error(ctx, "failed to update student", err, map[string]interface{}{
@@ -156,4 +178,18 @@ func (uc *studentUseCase) ListStudents(ctx context.Context, filter student.Stude
 		return nil, 0, fmt.Errorf("failed to list students: %w", err)
 	}
 	return students, total, nil
-}
\ No newline at end of file
+}
+
+// checkUser makes sure a student record may be linked to the login userID:
+// the account must be in the student's institute and not already linked to
+// another student, since self-service features find the student by it.
+func (uc *studentUseCase) checkUser(ctx context.Context, userID, instituteID, studentID uuid.UUID) error {
+	usr, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil || usr.InstituteID == nil || *usr.InstituteID != instituteID {
+		return apperrors.NotFound("user not found")
+	}
+	if linked, _ := uc.repo.GetByUserID(ctx, userID); linked != nil && linked.ID != studentID {
+		return apperrors.Conflict("user is already linked to another student")
+	}
+	return nil
+}
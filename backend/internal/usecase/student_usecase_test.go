@@ -3,10 +3,13 @@ package usecase
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/chalak/backend/internal/domain/student"
+	"github.com/chalak/backend/internal/domain/user"
+	apperrors "github.com/chalak/backend/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -37,6 +40,14 @@ func (m *MockStudentRepository) GetByEmail(ctx context.Context, email string) (*
 	return args.Get(0).(*student.Student), args.Error(1)
 }
 
+func (m *MockStudentRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*student.Student, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*student.Student), args.Error(1)
+}
+
 func (m *MockStudentRepository) Update(ctx context.Context, s *student.Student) error {
 	args := m.Called(ctx, s)
 	return args.Error(0)
@@ -87,7 +98,7 @@ func (m *MockLogger) Fatal(ctx context.Context, msg string, err error, fields ma
 func TestCreateStudent_Success(t *testing.T) {
 	mockRepo := new(MockStudentRepository)
 	mockLogger := new(MockLogger)
-	uc := NewStudentUseCase(mockRepo, mockLogger)
+	uc := NewStudentUseCase(mockRepo, nil, mockLogger)
 
 	ctx := context.Background()
 	instituteID := uuid.New()
@@ -122,7 +133,7 @@ func TestCreateStudent_Success(t *testing.T) {
 func TestCreateStudent_EmailAlreadyExists(t *testing.T) {
 	mockRepo := new(MockStudentRepository)
 	mockLogger := new(MockLogger)
-	uc := NewStudentUseCase(mockRepo, mockLogger)
+	uc := NewStudentUseCase(mockRepo, nil, mockLogger)
 
 	ctx := context.Background()
 	instituteID := uuid.New()
@@ -152,7 +163,7 @@ func TestCreateStudent_EmailAlreadyExists(t *testing.T) {
 func TestGetStudent_Success(t *testing.T) {
 	mockRepo := new(MockStudentRepository)
 	mockLogger := new(MockLogger)
-	uc := NewStudentUseCase(mockRepo, mockLogger)
+	uc := NewStudentUseCase(mockRepo, nil, mockLogger)
 
 	ctx := context.Background()
 	studentID := uuid.New()
@@ -179,7 +190,7 @@ func TestGetStudent_Success(t *testing.T) {
 func TestGetStudent_NotFound(t *testing.T) {
 	mockRepo := new(MockStudentRepository)
 	mockLogger := new(MockLogger)
-	uc := NewStudentUseCase(mockRepo, mockLogger)
+	uc := NewStudentUseCase(mockRepo, nil, mockLogger)
 
 	ctx := context.Background()
 	studentID := uuid.New()
@@ -200,7 +211,7 @@ func TestGetStudent_NotFound(t *testing.T) {
 func TestDeleteStudent_Success(t *testing.T) {
 	mockRepo := new(MockStudentRepository)
 	mockLogger := new(MockLogger)
-	uc := NewStudentUseCase(mockRepo, mockLogger)
+	uc := NewStudentUseCase(mockRepo, nil, mockLogger)
 
 	ctx := context.Background()
 	studentID := uuid.New()
@@ -219,7 +230,7 @@ func TestDeleteStudent_Success(t *testing.T) {
 func TestListStudents_Success(t *testing.T) {
 	mockRepo := new(MockStudentRepository)
 	mockLogger := new(MockLogger)
-	uc := NewStudentUseCase(mockRepo, mockLogger)
+	uc := NewStudentUseCase(mockRepo, nil, mockLogger)
 
 	ctx := context.Background()
 	filter := student.StudentFilter{
@@ -243,4 +254,76 @@ func TestListStudents_Success(t *testing.T) {
 	assert.Equal(t, expectedTotal, total)
 
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func (r *memUserRepo) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func TestCreateStudent_LinksUser(t *testing.T) {
+	ctx := context.Background()
+	instituteID, otherInstitute := uuid.New(), uuid.New()
+	own := &user.User{ID: uuid.New(), InstituteID: &instituteID}
+	foreign := &user.User{ID: uuid.New(), InstituteID: &otherInstitute}
+	unassigned := &user.User{ID: uuid.New()}
+	users := &memUserRepo{users: []*user.User{own, foreign, unassigned}}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		wantCode int
+	}{
+		{name: "user of the institute", userID: own.ID},
+		{name: "user of another institute", userID: foreign.ID, wantCode: http.StatusNotFound},
+		{name: "user without an institute", userID: unassigned.ID, wantCode: http.StatusNotFound},
+		{name: "unknown user", userID: uuid.New(), wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockStudentRepository)
+			uc := NewStudentUseCase(mockRepo, users, nopLogger{})
+			req := student.CreateStudentRequest{FirstName: "Sita", LastName: "Sharma", InstituteID: instituteID, UserID: &tt.userID}
+
+			if tt.wantCode == 0 {
+				mockRepo.On("GetByUserID", ctx, tt.userID).Return(nil, errors.New("student not found"))
+				mockRepo.On("Create", ctx, mock.AnythingOfType("*student.Student")).Return(nil)
+			}
+
+			result, err := uc.CreateStudent(ctx, req)
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperrors.GetStatusCode(err))
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.userID, *result.UserID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateStudent_UserAlreadyLinked(t *testing.T) {
+	ctx := context.Background()
+	instituteID := uuid.New()
+	usr := &user.User{ID: uuid.New(), InstituteID: &instituteID}
+	mockRepo := new(MockStudentRepository)
+	uc := NewStudentUseCase(mockRepo, &memUserRepo{users: []*user.User{usr}}, nopLogger{})
+
+	s := &student.Student{ID: uuid.New(), InstituteID: instituteID}
+	linked := &student.Student{ID: uuid.New(), InstituteID: instituteID, UserID: &usr.ID}
+	mockRepo.On("GetByID", ctx, s.ID).Return(s, nil)
+	mockRepo.On("GetByUserID", ctx, usr.ID).Return(linked, nil)
+
+	_, err := uc.UpdateStudent(ctx, s.ID, student.UpdateStudentRequest{UserID: &usr.ID})
+
+	assert.Equal(t, http.StatusConflict, apperrors.GetStatusCode(err))
+	assert.Nil(t, s.UserID)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
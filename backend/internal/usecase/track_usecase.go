@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/attendance"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/geo"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/storage"
+	"github.com/google/uuid"
+)
+
+// trackFiles gives the content type and extension a track is stored with.
+// Tracks are checked by parsing them, not by storage.Inspect, which only
+// recognises binary formats.
+var trackFiles = map[string]struct{ contentType, extension string }{
+	geo.FormatGPX:     {"application/gpx+xml", ".gpx"},
+	geo.FormatGeoJSON: {"application/geo+json", ".geojson"},
+}
+
+// TrackUseCase keeps the GPS routes instructors record during practical
+// lessons, attached to the student's attendance record.
+type TrackUseCase struct {
+	attendanceRepo attendance.Repository
+	storage        storage.Storage
+	maxUploadSize  int64
+	linkExpiry     time.Duration
+	logger         logger.Logger
+}
+
+func NewTrackUseCase(
+	attendanceRepo attendance.Repository,
+	store storage.Storage,
+	maxUploadSize int64,
+	linkExpiry time.Duration,
+	logger logger.Logger,
+) *TrackUseCase {
+	return &TrackUseCase{
+		attendanceRepo: attendanceRepo,
+		storage:        store,
+		maxUploadSize:  maxUploadSize,
+		linkExpiry:     linkExpiry,
+		logger:         logger,
+	}
+}
+
+// MaxUploadSize is the largest track file accepted, in bytes.
+func (uc *TrackUseCase) MaxUploadSize() int64 {
+	return uc.maxUploadSize
+}
+
+// Upload stores a GPX or GeoJSON track for the attendance record and
+// summarises it, replacing any track uploaded before.
+func (uc *TrackUseCase) Upload(ctx context.Context, attendanceID uuid.UUID, upload *storage.Upload, uploadedBy uuid.UUID) (*attendance.Track, error) {
+	if _, err := uc.attendanceRepo.FindByID(ctx, attendanceID); err != nil {
+		return nil, apperrors.NotFound("attendance not found")
+	}
+
+	if upload.Size > uc.maxUploadSize {
+		return nil, apperrors.TooLarge(fmt.Sprintf("file must not be larger than %d KB", uc.maxUploadSize/1024))
+	}
+	data, err := io.ReadAll(io.LimitReader(upload.File, uc.maxUploadSize+1))
+	if err != nil {
+		return nil, apperrors.BadRequest("failed to read uploaded file")
+	}
+	if int64(len(data)) > uc.maxUploadSize {
+		return nil, apperrors.TooLarge(fmt.Sprintf("file must not be larger than %d KB", uc.maxUploadSize/1024))
+	}
+
+	route, format, err := geo.Parse(data)
+	if err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+	summary := geo.Summarize(route)
+
+	previous, _ := uc.attendanceRepo.FindTrack(ctx, attendanceID)
+
+	file := trackFiles[format]
+	key := fmt.Sprintf("tracks/%s/%s%s", attendanceID, uuid.New(), file.extension)
+	if err := uc.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), file.contentType); err != nil {
+		uc.logger.Error(ctx, "failed to store attendance track", err, map[string]interface{}{
+			"attendance_id": attendanceID,
+		})
+		return nil, fmt.Errorf("failed to store attendance track: %w", err)
+	}
+
+	now := time.Now().UTC()
+	track := &attendance.Track{
+		AttendanceID:    attendanceID,
+		FileKey:         key,
+		FileName:        upload.FileName,
+		Format:          format,
+		Size:            int64(len(data)),
+		DistanceMeters:  summary.DistanceMeters,
+		DurationSeconds: summary.DurationSeconds,
+		MaxSpeedKPH:     summary.MaxSpeedKPH,
+		Points:          summary.Points,
+		StartedAt:       summary.StartedAt,
+		EndedAt:         summary.EndedAt,
+		UploadedBy:      uploadedBy,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if previous != nil {
+		track.CreatedAt = previous.CreatedAt
+	}
+
+	if err := uc.attendanceRepo.SaveTrack(ctx, track); err != nil {
+		uc.logger.Error(ctx, "failed to save attendance track", err, map[string]interface{}{
+			"attendance_id": attendanceID,
+		})
+		uc.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to save attendance track: %w", err)
+	}
+
+	if previous != nil {
+		if err := uc.storage.Delete(ctx, previous.FileKey); err != nil {
+			uc.logger.Warn(ctx, "failed to delete replaced attendance track", map[string]interface{}{
+				"attendance_id": attendanceID,
+				"error":         err.Error(),
+			})
+		}
+	}
+
+	uc.logger.Info(ctx, "attendance track uploaded", map[string]interface{}{
+		"attendance_id":   attendanceID,
+		"distance_meters": track.DistanceMeters,
+	})
+
+	return track, nil
+}
+
+// Get returns the attendance record's track summary with its route as
+// GeoJSON.
+func (uc *TrackUseCase) Get(ctx context.Context, attendanceID uuid.UUID) (*attendance.TrackDetail, error) {
+	track, err := uc.attendanceRepo.FindTrack(ctx, attendanceID)
+	if err != nil {
+		return nil, apperrors.NotFound("attendance has no track")
+	}
+
+	file, err := uc.storage.Get(ctx, track.FileKey)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to read attendance track", err, map[string]interface{}{
+			"attendance_id": attendanceID,
+		})
+		return nil, fmt.Errorf("failed to read attendance track: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attendance track: %w", err)
+	}
+
+	route, _, err := geo.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attendance track: %w", err)
+	}
+
+	return &attendance.TrackDetail{Track: track, Route: geo.ToGeoJSON(route)}, nil
+}
+
+// DownloadURL returns a time-limited link to the track file as uploaded.
+func (uc *TrackUseCase) DownloadURL(ctx context.Context, attendanceID uuid.UUID) (*storage.Link, error) {
+	track, err := uc.attendanceRepo.FindTrack(ctx, attendanceID)
+	if err != nil {
+		return nil, apperrors.NotFound("attendance has no track")
+	}
+
+	url, err := uc.storage.SignedURL(ctx, track.FileKey, uc.linkExpiry)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to sign attendance track url", err, map[string]interface{}{
+			"attendance_id": attendanceID,
+		})
+		return nil, fmt.Errorf("failed to sign attendance track url: %w", err)
+	}
+
+	return &storage.Link{URL: url, ExpiresAt: time.Now().Add(uc.linkExpiry).UTC()}, nil
+}
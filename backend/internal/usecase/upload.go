@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/storage"
+)
+
+// inspectUpload validates an upload's size and content type, turning
+// rejections into client errors.
+func inspectUpload(upload *storage.Upload, maxSize int64, allowed ...string) (string, io.Reader, error) {
+	contentType, body, err := storage.Inspect(upload.File, upload.Size, maxSize, allowed...)
+	switch {
+	case errors.Is(err, storage.ErrTooLarge):
+		return "", nil, apperrors.TooLarge(fmt.Sprintf("file must not be larger than %d KB", maxSize/1024))
+	case errors.Is(err, storage.ErrUnsupportedType):
+		return "", nil, apperrors.BadRequest("file must be one of: " + strings.Join(allowed, ", "))
+	case err != nil:
+		return "", nil, apperrors.BadRequest("failed to read uploaded file")
+	}
+	return contentType, body, nil
+}
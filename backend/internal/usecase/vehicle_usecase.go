@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/notification"
+	"github.com/chalak/backend/internal/domain/user"
+	"github.com/chalak/backend/internal/domain/vehicle"
+	apperrors "github.com/chalak/backend/pkg/errors"
+	"github.com/chalak/backend/pkg/logger"
+	"github.com/chalak/backend/pkg/tenant"
+	"github.com/google/uuid"
+)
+
+type VehicleUseCase struct {
+	repo             vehicle.Repository
+	userRepo         user.Repository
+	notificationRepo notification.Repository
+	logger           logger.Logger
+}
+
+func NewVehicleUseCase(
+	repo vehicle.Repository,
+	userRepo user.Repository,
+	notificationRepo notification.Repository,
+	logger logger.Logger,
+) *VehicleUseCase {
+	return &VehicleUseCase{
+		repo:             repo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		logger:           logger,
+	}
+}
+
+func (uc *VehicleUseCase) Create(ctx context.Context, req *vehicle.CreateVehicleRequest) (*vehicle.Vehicle, error) {
+	registrationNumber := normalizeRegistrationNumber(req.RegistrationNumber)
+
+	instituteID := req.InstituteID
+	if id, ok := tenant.InstituteID(ctx); ok && instituteID == uuid.Nil {
+		instituteID = id
+	}
+	if existing, _ := uc.repo.FindByRegistrationNumber(ctx, instituteID, registrationNumber); existing != nil {
+		return nil, apperrors.Conflict("vehicle with this registration number already exists")
+	}
+
+	v := &vehicle.Vehicle{
+		ID:                 uuid.New(),
+		InstituteID:        instituteID,
+		RegistrationNumber: registrationNumber,
+		Type:               req.Type,
+		LicenseCategory:    strings.ToUpper(strings.TrimSpace(req.LicenseCategory)),
+		Transmission:       req.Transmission,
+		Make:               req.Make,
+		Model:              req.Model,
+		Odometer:           req.Odometer,
+		BluebookExpiry:     req.BluebookExpiry,
+		InsuranceExpiry:    req.InsuranceExpiry,
+		PollutionExpiry:    req.PollutionExpiry,
+		Status:             vehicle.StatusActive,
+		Notes:              req.Notes,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+	}
+
+	if err := uc.repo.Create(ctx, v); err != nil {
+		uc.logger.Error(ctx, "failed to create vehicle", err, map[string]interface{}{
+			"registration_number": registrationNumber,
+		})
+		return nil, fmt.Errorf("failed to create vehicle: %w", err)
+	}
+
+	uc.logger.Info(ctx, "vehicle created", map[string]interface{}{
+		"vehicle_id":          v.ID,
+		"registration_number": v.RegistrationNumber,
+	})
+
+	return v, nil
+}
+
+func (uc *VehicleUseCase) GetByID(ctx context.Context, id uuid.UUID) (*vehicle.Vehicle, error) {
+	v, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("vehicle not found")
+	}
+	return v, nil
+}
+
+func (uc *VehicleUseCase) Update(ctx context.Context, id uuid.UUID, req *vehicle.UpdateVehicleRequest) (*vehicle.Vehicle, error) {
+	v, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NotFound("vehicle not found")
+	}
+
+	if req.RegistrationNumber != nil {
+		registrationNumber := normalizeRegistrationNumber(*req.RegistrationNumber)
+		if registrationNumber != v.RegistrationNumber {
+			if existing, _ := uc.repo.FindByRegistrationNumber(ctx, v.InstituteID, registrationNumber); existing != nil {
+				return nil, apperrors.Conflict("vehicle with this registration number already exists")
+			}
+			v.RegistrationNumber = registrationNumber
+		}
+	}
+	if req.Type != nil {
+		v.Type = *req.Type
+	}
+	if req.LicenseCategory != nil {
+		v.LicenseCategory = strings.ToUpper(strings.TrimSpace(*req.LicenseCategory))
+	}
+	if req.Transmission != nil {
+		v.Transmission = *req.Transmission
+	}
+	if req.Make != nil {
+		v.Make = *req.Make
+	}
+	if req.Model != nil {
+		v.Model = *req.Model
+	}
+	if req.Odometer != nil {
+		if *req.Odometer < v.Odometer {
+			return nil, apperrors.BadRequest("odometer reading cannot go backwards")
+		}
+		v.Odometer = *req.Odometer
+	}
+	if req.BluebookExpiry != nil {
+		v.BluebookExpiry = req.BluebookExpiry
+	}
+	if req.InsuranceExpiry != nil {
+		v.InsuranceExpiry = req.InsuranceExpiry
+	}
+	if req.PollutionExpiry != nil {
+		v.PollutionExpiry = req.PollutionExpiry
+	}
+	if req.Status != nil {
+		v.Status = *req.Status
+	}
+	if req.Notes != nil {
+		v.Notes = *req.Notes
+	}
+
+	v.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.Update(ctx, v); err != nil {
+		uc.logger.Error(ctx, "failed to update vehicle", err, map[string]interface{}{
+			"vehicle_id": id,
+		})
+		return nil, fmt.Errorf("failed to update vehicle: %w", err)
+	}
+
+	uc.logger.Info(ctx, "vehicle updated", map[string]interface{}{
+		"vehicle_id": v.ID,
+	})
+
+	return v, nil
+}
+
+func (uc *VehicleUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := uc.repo.FindByID(ctx, id); err != nil {
+		return apperrors.NotFound("vehicle not found")
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		uc.logger.Error(ctx, "failed to delete vehicle", err, map[string]interface{}{
+			"vehicle_id": id,
+		})
+		return fmt.Errorf("failed to delete vehicle: %w", err)
+	}
+
+	uc.logger.Info(ctx, "vehicle deleted", map[string]interface{}{
+		"vehicle_id": id,
+	})
+
+	return nil
+}
+
+func (uc *VehicleUseCase) List(ctx context.Context, filter vehicle.VehicleFilter) ([]*vehicle.Vehicle, int64, error) {
+	vehicles, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list vehicles", err, nil)
+		return nil, 0, fmt.Errorf("failed to list vehicles: %w", err)
+	}
+
+	return vehicles, total, nil
+}
+
+// NotifyExpiringDocuments reminds the admins of each institute about vehicle
+// documents that have expired or expire within window. Each admin is told
+// once per document and expiry date while it is expiring and once more when
+// it has expired, so the job can run daily and renewing a document re-arms
+// its reminders. It returns the number of notifications created.
+func (uc *VehicleUseCase) NotifyExpiringDocuments(ctx context.Context, window time.Duration) (int, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	before := today.Add(window).AddDate(0, 0, 1)
+
+	vehicles, err := uc.repo.FindWithDocumentsExpiringBefore(ctx, before)
+	if err != nil {
+		uc.logger.Error(ctx, "failed to find vehicles with expiring documents", err, nil)
+		return 0, fmt.Errorf("failed to find vehicles with expiring documents: %w", err)
+	}
+
+	admins := make(map[uuid.UUID][]*user.User)
+	sent := 0
+
+	for _, v := range vehicles {
+		recipients, ok := admins[v.InstituteID]
+		if !ok {
+			recipients, err = uc.instituteAdmins(ctx, v.InstituteID)
+			if err != nil {
+				return sent, err
+			}
+			admins[v.InstituteID] = recipients
+		}
+
+		for _, doc := range v.Documents() {
+			if !doc.ExpiresOn.Before(before) {
+				continue
+			}
+
+			expiresOn := doc.ExpiresOn.Format("2006-01-02")
+			state := "expiring"
+			title := "Vehicle document expiring"
+			message := fmt.Sprintf("The %s of %s expires on %s.", doc.Name, v.RegistrationNumber, expiresOn)
+			if doc.ExpiresOn.Before(today) {
+				state = "expired"
+				title = "Vehicle document expired"
+				message = fmt.Sprintf("The %s of %s expired on %s.", doc.Name, v.RegistrationNumber, expiresOn)
+			}
+
+			data, err := json.Marshal(map[string]string{
+				"vehicle_id": v.ID.String(),
+				"document":   doc.Name,
+				"expires_on": expiresOn,
+				"state":      state,
+			})
+			if err != nil {
+				return sent, fmt.Errorf("failed to encode notification data: %w", err)
+			}
+
+			for _, admin := range recipients {
+				exists, err := uc.notificationRepo.ExistsWithData(ctx, admin.ID, notification.TypeReminder, string(data))
+				if err != nil {
+					uc.logger.Error(ctx, "failed to check vehicle document reminder", err, map[string]interface{}{
+						"vehicle_id": v.ID,
+						"user_id":    admin.ID,
+					})
+					return sent, fmt.Errorf("failed to check reminder: %w", err)
+				}
+				if exists {
+					continue
+				}
+
+				notif := &notification.Notification{
+					ID:        uuid.New(),
+					UserID:    admin.ID,
+					Type:      notification.TypeReminder,
+					Title:     title,
+					Message:   message,
+					Data:      string(data),
+					SentVia:   notification.SentViaInApp,
+					CreatedAt: time.Now().UTC(),
+					UpdatedAt: time.Now().UTC(),
+				}
+				if err := uc.notificationRepo.Create(ctx, notif); err != nil {
+					uc.logger.Error(ctx, "failed to create vehicle document reminder", err, map[string]interface{}{
+						"vehicle_id": v.ID,
+						"user_id":    admin.ID,
+					})
+					return sent, fmt.Errorf("failed to create reminder: %w", err)
+				}
+				sent++
+			}
+		}
+	}
+
+	uc.logger.Info(ctx, "vehicle document reminders sent", map[string]interface{}{
+		"vehicles":      len(vehicles),
+		"notifications": sent,
+	})
+
+	return sent, nil
+}
+
+func (uc *VehicleUseCase) instituteAdmins(ctx context.Context, instituteID uuid.UUID) ([]*user.User, error) {
+	role := "admin"
+	status := "active"
+	admins, _, err := uc.userRepo.List(ctx, user.UserFilter{
+		InstituteID: &instituteID,
+		Role:        &role,
+		Status:      &status,
+	})
+	if err != nil {
+		uc.logger.Error(ctx, "failed to list institute admins", err, map[string]interface{}{
+			"institute_id": instituteID,
+		})
+		return nil, fmt.Errorf("failed to list institute admins: %w", err)
+	}
+	return admins, nil
+}
+
+func normalizeRegistrationNumber(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), " "))
+}
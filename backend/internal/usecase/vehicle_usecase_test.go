@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chalak/backend/internal/domain/notification"
+	"github.com/chalak/backend/internal/domain/user"
+	"github.com/chalak/backend/internal/domain/vehicle"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memVehicleRepo struct {
+	vehicle.Repository
+	vehicles []*vehicle.Vehicle
+}
+
+func (r *memVehicleRepo) FindWithDocumentsExpiringBefore(ctx context.Context, before time.Time) ([]*vehicle.Vehicle, error) {
+	var found []*vehicle.Vehicle
+	for _, v := range r.vehicles {
+		for _, doc := range v.Documents() {
+			if doc.ExpiresOn.Before(before) {
+				found = append(found, v)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+type memUserRepo struct {
+	user.Repository
+	users []*user.User
+}
+
+func (r *memUserRepo) List(ctx context.Context, filter user.UserFilter) ([]*user.User, int64, error) {
+	var list []*user.User
+	for _, u := range r.users {
+		if filter.InstituteID != nil && (u.InstituteID == nil || *u.InstituteID != *filter.InstituteID) {
+			continue
+		}
+		if filter.Role != nil && u.Role != *filter.Role {
+			continue
+		}
+		if filter.Status != nil && u.Status != *filter.Status {
+			continue
+		}
+		list = append(list, u)
+	}
+	return list, int64(len(list)), nil
+}
+
+// memNotificationRepo matches data the way the database's jsonb containment
+// does for flat objects.
+type memNotificationRepo struct {
+	notification.Repository
+	notifications []*notification.Notification
+}
+
+func (r *memNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
+	r.notifications = append(r.notifications, n)
+	return nil
+}
+
+func (r *memNotificationRepo) ExistsWithData(ctx context.Context, userID uuid.UUID, notifType string, data string) (bool, error) {
+	var want map[string]string
+	if err := json.Unmarshal([]byte(data), &want); err != nil {
+		return false, err
+	}
+	for _, n := range r.notifications {
+		if n.UserID != userID || n.Type != notifType {
+			continue
+		}
+		var got map[string]string
+		if err := json.Unmarshal([]byte(n.Data), &got); err != nil {
+			return false, err
+		}
+		contains := true
+		for k, v := range want {
+			if got[k] != v {
+				contains = false
+			}
+		}
+		if contains {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestNotifyExpiringDocuments(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := func(offset int) *time.Time {
+		d := today.AddDate(0, 0, offset)
+		return &d
+	}
+
+	instituteID, otherInstitute := uuid.New(), uuid.New()
+	admin := func(id uuid.UUID, role, status string) *user.User {
+		return &user.User{ID: uuid.New(), Role: role, Status: status, InstituteID: &id}
+	}
+	owner, manager := admin(instituteID, "admin", "active"), admin(instituteID, "admin", "active")
+	users := &memUserRepo{users: []*user.User{
+		owner,
+		manager,
+		admin(instituteID, "admin", "inactive"),
+		admin(instituteID, "instructor", "active"),
+		admin(otherInstitute, "admin", "active"),
+	}}
+
+	car := &vehicle.Vehicle{
+		ID:                 uuid.New(),
+		InstituteID:        instituteID,
+		RegistrationNumber: "BA 1 PA 1234",
+		BluebookExpiry:     day(-2),
+		InsuranceExpiry:    day(3),
+		PollutionExpiry:    day(60),
+	}
+	notifications := &memNotificationRepo{}
+	uc := NewVehicleUseCase(&memVehicleRepo{vehicles: []*vehicle.Vehicle{car}}, users, notifications, nopLogger{})
+	ctx := context.Background()
+	window := 30 * 24 * time.Hour
+
+	sent, err := uc.NotifyExpiringDocuments(ctx, window)
+	require.NoError(t, err)
+	assert.Equal(t, 4, sent)
+
+	titles := map[string]int{}
+	for _, n := range notifications.notifications {
+		assert.Contains(t, []uuid.UUID{owner.ID, manager.ID}, n.UserID)
+		titles[n.Title]++
+	}
+	assert.Equal(t, map[string]int{"Vehicle document expired": 2, "Vehicle document expiring": 2}, titles)
+
+	t.Run("runs again without repeating itself", func(t *testing.T) {
+		sent, err := uc.NotifyExpiringDocuments(ctx, window)
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("tells admins again once an expiring document has expired", func(t *testing.T) {
+		// The insurance reminder was sent while it was still expiring; the
+		// date has since passed.
+		for _, n := range notifications.notifications {
+			var data map[string]string
+			require.NoError(t, json.Unmarshal([]byte(n.Data), &data))
+			if data["document"] == vehicle.DocumentInsurance {
+				data["expires_on"] = day(-1).Format("2006-01-02")
+				b, _ := json.Marshal(data)
+				n.Data = string(b)
+			}
+		}
+		car.InsuranceExpiry = day(-1)
+		before := len(notifications.notifications)
+
+		sent, err := uc.NotifyExpiringDocuments(ctx, window)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		for _, n := range notifications.notifications[before:] {
+			assert.Equal(t, "Vehicle document expired", n.Title)
+			assert.Contains(t, n.Message, "insurance")
+		}
+	})
+
+	t.Run("renewing a document re-arms its reminder", func(t *testing.T) {
+		car.BluebookExpiry = day(10)
+		before := len(notifications.notifications)
+
+		sent, err := uc.NotifyExpiringDocuments(ctx, window)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		for _, n := range notifications.notifications[before:] {
+			assert.Equal(t, "Vehicle document expiring", n.Title)
+			assert.Contains(t, n.Message, "bluebook")
+		}
+	})
+}
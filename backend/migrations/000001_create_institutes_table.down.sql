@@ -0,0 +1,6 @@
+ALTER TABLE expenses DROP CONSTRAINT IF EXISTS fk_expenses_institute;
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS fk_invoices_institute;
+ALTER TABLE employees DROP CONSTRAINT IF EXISTS fk_employees_institute;
+ALTER TABLE students DROP CONSTRAINT IF EXISTS fk_students_institute;
+
+DROP TABLE IF EXISTS institutes;
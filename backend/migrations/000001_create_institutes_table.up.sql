@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS institutes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(20),
+    address TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_institutes_code ON institutes(code);
+CREATE INDEX IF NOT EXISTS idx_institutes_deleted_at ON institutes(deleted_at);
+
+-- Existing rows may reference institutes that were never created, so the
+-- constraints are added NOT VALID: new writes are checked, old rows are not.
+ALTER TABLE students
+    ADD CONSTRAINT fk_students_institute FOREIGN KEY (institute_id) REFERENCES institutes(id) NOT VALID;
+ALTER TABLE employees
+    ADD CONSTRAINT fk_employees_institute FOREIGN KEY (institute_id) REFERENCES institutes(id) NOT VALID;
+ALTER TABLE invoices
+    ADD CONSTRAINT fk_invoices_institute FOREIGN KEY (institute_id) REFERENCES institutes(id) NOT VALID;
+ALTER TABLE expenses
+    ADD CONSTRAINT fk_expenses_institute FOREIGN KEY (institute_id) REFERENCES institutes(id) NOT VALID;
@@ -0,0 +1,22 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS institute_id UUID REFERENCES institutes(id);
+
+CREATE INDEX IF NOT EXISTS idx_users_institute_id ON users(institute_id);
+
+-- Accounts created before users carried an institute are placed in the
+-- institute of the employee or student record linked to them. Anything still
+-- unassigned afterwards must be placed by an admin through
+-- PUT /users/{id}/assignment; until then its tokens only reach /auth/me, except
+-- for admin accounts, which remain platform admins and should be reviewed.
+UPDATE users u
+SET institute_id = e.institute_id, updated_at = CURRENT_TIMESTAMP
+FROM employees e
+WHERE e.user_id = u.id
+  AND e.deleted_at IS NULL
+  AND u.institute_id IS NULL;
+
+UPDATE users u
+SET institute_id = s.institute_id, updated_at = CURRENT_TIMESTAMP
+FROM students s
+WHERE s.user_id = u.id
+  AND s.deleted_at IS NULL
+  AND u.institute_id IS NULL;
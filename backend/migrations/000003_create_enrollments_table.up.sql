@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS enrollments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    student_id UUID NOT NULL REFERENCES students(id),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    package_id UUID REFERENCES packages(id),
+    course_id UUID REFERENCES courses(id),
+    start_date DATE NOT NULL,
+    expected_end_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    price DECIMAL(10,2) NOT NULL,
+    discount_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10,2) NOT NULL,
+    invoice_id UUID REFERENCES invoices(id),
+    notes TEXT,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_enrollments_package_or_course CHECK ((package_id IS NULL) <> (course_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_student_id ON enrollments(student_id);
+CREATE INDEX IF NOT EXISTS idx_enrollments_institute_id ON enrollments(institute_id);
+CREATE INDEX IF NOT EXISTS idx_enrollments_package_id ON enrollments(package_id);
+CREATE INDEX IF NOT EXISTS idx_enrollments_course_id ON enrollments(course_id);
+CREATE INDEX IF NOT EXISTS idx_enrollments_invoice_id ON enrollments(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_enrollments_deleted_at ON enrollments(deleted_at);
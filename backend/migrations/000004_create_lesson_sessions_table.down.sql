@@ -0,0 +1,4 @@
+ALTER TABLE attendances DROP CONSTRAINT IF EXISTS fk_attendances_class;
+
+DROP TABLE IF EXISTS lesson_session_students;
+DROP TABLE IF EXISTS lesson_sessions;
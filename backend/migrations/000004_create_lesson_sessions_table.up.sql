@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS lesson_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    course_id UUID NOT NULL REFERENCES courses(id),
+    instructor_id UUID NOT NULL REFERENCES employees(id),
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    capacity INT NOT NULL,
+    location VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_lesson_sessions_time CHECK (end_time > start_time),
+    CONSTRAINT chk_lesson_sessions_type CHECK (type IN ('theory', 'practical')),
+    CONSTRAINT chk_lesson_sessions_capacity CHECK (capacity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lesson_sessions_institute_id ON lesson_sessions(institute_id);
+CREATE INDEX IF NOT EXISTS idx_lesson_sessions_course_id ON lesson_sessions(course_id);
+CREATE INDEX IF NOT EXISTS idx_lesson_sessions_instructor_id ON lesson_sessions(instructor_id);
+CREATE INDEX IF NOT EXISTS idx_lesson_sessions_start_time ON lesson_sessions(start_time);
+CREATE INDEX IF NOT EXISTS idx_lesson_sessions_deleted_at ON lesson_sessions(deleted_at);
+
+CREATE TABLE IF NOT EXISTS lesson_session_students (
+    session_id UUID NOT NULL REFERENCES lesson_sessions(id) ON DELETE CASCADE,
+    student_id UUID NOT NULL REFERENCES students(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lesson_session_students_student_id ON lesson_session_students(student_id);
+
+-- Attendance recorded before sessions existed points at made-up class ids.
+ALTER TABLE attendances
+    ADD CONSTRAINT fk_attendances_class FOREIGN KEY (class_id) REFERENCES lesson_sessions(id) NOT VALID;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_data;
+
+DROP TABLE IF EXISTS vehicles;
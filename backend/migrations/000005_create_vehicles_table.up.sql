@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS vehicles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    registration_number VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    license_category VARCHAR(10) NOT NULL,
+    transmission VARCHAR(20) NOT NULL,
+    make VARCHAR(100),
+    model VARCHAR(100),
+    odometer INT NOT NULL DEFAULT 0,
+    bluebook_expiry DATE,
+    insurance_expiry DATE,
+    pollution_expiry DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_vehicles_type CHECK (type IN ('car', 'motorcycle', 'scooter', 'heavy')),
+    CONSTRAINT chk_vehicles_transmission CHECK (transmission IN ('manual', 'automatic')),
+    CONSTRAINT chk_vehicles_status CHECK (status IN ('active', 'maintenance', 'retired')),
+    CONSTRAINT chk_vehicles_odometer CHECK (odometer >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vehicles_registration_number
+    ON vehicles(institute_id, registration_number) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_vehicles_institute_id ON vehicles(institute_id);
+CREATE INDEX IF NOT EXISTS idx_vehicles_deleted_at ON vehicles(deleted_at);
+
+-- Backs the duplicate check of the daily document expiry reminders.
+CREATE INDEX IF NOT EXISTS idx_notifications_data ON notifications USING GIN (data);
@@ -0,0 +1,5 @@
+ALTER TABLE lesson_sessions DROP CONSTRAINT IF EXISTS excl_lesson_sessions_vehicle;
+ALTER TABLE lesson_sessions DROP CONSTRAINT IF EXISTS excl_lesson_sessions_instructor;
+
+DROP INDEX IF EXISTS idx_lesson_sessions_vehicle_id;
+ALTER TABLE lesson_sessions DROP COLUMN IF EXISTS vehicle_id;
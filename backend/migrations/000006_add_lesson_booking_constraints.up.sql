@@ -0,0 +1,18 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE lesson_sessions ADD COLUMN IF NOT EXISTS vehicle_id UUID REFERENCES vehicles(id);
+CREATE INDEX IF NOT EXISTS idx_lesson_sessions_vehicle_id ON lesson_sessions(vehicle_id);
+
+-- Backstops for the booking checks in the application: an instructor or a
+-- vehicle can't be in two live sessions at once.
+ALTER TABLE lesson_sessions
+    ADD CONSTRAINT excl_lesson_sessions_instructor EXCLUDE USING gist (
+        instructor_id WITH =,
+        tsrange(start_time, end_time) WITH &&
+    ) WHERE (deleted_at IS NULL AND status <> 'cancelled');
+
+ALTER TABLE lesson_sessions
+    ADD CONSTRAINT excl_lesson_sessions_vehicle EXCLUDE USING gist (
+        vehicle_id WITH =,
+        tsrange(start_time, end_time) WITH &&
+    ) WHERE (deleted_at IS NULL AND status <> 'cancelled' AND vehicle_id IS NOT NULL);
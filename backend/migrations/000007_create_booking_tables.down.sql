@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS instructor_working_hours;
+
+DROP INDEX IF EXISTS idx_employees_user_id;
+ALTER TABLE employees DROP COLUMN IF EXISTS user_id;
+
+DROP INDEX IF EXISTS idx_students_user_id;
+ALTER TABLE students DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,19 @@
+ALTER TABLE students ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_user_id ON students(user_id) WHERE user_id IS NOT NULL;
+
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_user_id ON employees(user_id) WHERE user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS instructor_working_hours (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instructor_id UUID NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_instructor_working_hours_weekday CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT chk_instructor_working_hours_time CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_instructor_working_hours_instructor ON instructor_working_hours(instructor_id, weekday);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS license_tests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    student_id UUID NOT NULL REFERENCES students(id),
+    course_id UUID REFERENCES courses(id),
+    instructor_id UUID REFERENCES employees(id),
+    type VARCHAR(20) NOT NULL,
+    scheduled_date DATE NOT NULL,
+    test_center VARCHAR(255) NOT NULL,
+    result VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempt_number INT NOT NULL,
+    remarks TEXT,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_license_tests_type CHECK (type IN ('written', 'trial')),
+    CONSTRAINT chk_license_tests_result CHECK (result IN ('pending', 'passed', 'failed', 'absent')),
+    CONSTRAINT chk_license_tests_attempt CHECK (attempt_number > 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_license_tests_attempt
+    ON license_tests(student_id, type, attempt_number) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_license_tests_institute_id ON license_tests(institute_id);
+CREATE INDEX IF NOT EXISTS idx_license_tests_scheduled_date ON license_tests(scheduled_date);
+CREATE INDEX IF NOT EXISTS idx_license_tests_deleted_at ON license_tests(deleted_at);
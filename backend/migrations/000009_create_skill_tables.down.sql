@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS skill_scores;
+DROP TABLE IF EXISTS course_skills;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS course_skills (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    course_id UUID NOT NULL REFERENCES courses(id),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    max_score INT NOT NULL DEFAULT 5,
+    pass_score INT NOT NULL DEFAULT 4,
+    sort_order INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_course_skills_scores CHECK (max_score > 0 AND pass_score >= 0 AND pass_score <= max_score)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_skills_institute_course ON course_skills(institute_id, course_id);
+CREATE INDEX IF NOT EXISTS idx_course_skills_deleted_at ON course_skills(deleted_at);
+
+CREATE TABLE IF NOT EXISTS skill_scores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    attendance_id UUID NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    skill_id UUID NOT NULL REFERENCES course_skills(id),
+    student_id UUID NOT NULL REFERENCES students(id),
+    score INT NOT NULL,
+    comment TEXT,
+    graded_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_skill_scores_score CHECK (score >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_skill_scores_attendance_skill ON skill_scores(attendance_id, skill_id);
+CREATE INDEX IF NOT EXISTS idx_skill_scores_student_id ON skill_scores(student_id);
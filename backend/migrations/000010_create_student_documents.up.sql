@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS student_documents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    student_id UUID NOT NULL REFERENCES students(id),
+    type VARCHAR(30) NOT NULL,
+    file_key VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    uploaded_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_student_documents_type CHECK (type IN ('citizenship', 'photo', 'learner_license'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_documents_institute_id ON student_documents(institute_id);
+CREATE INDEX IF NOT EXISTS idx_student_documents_student_id ON student_documents(student_id);
+CREATE INDEX IF NOT EXISTS idx_student_documents_deleted_at ON student_documents(deleted_at);
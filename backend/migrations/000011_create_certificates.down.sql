@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS certificates;
+DROP SEQUENCE IF EXISTS certificate_serial_seq;
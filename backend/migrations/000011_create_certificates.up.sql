@@ -0,0 +1,23 @@
+CREATE SEQUENCE IF NOT EXISTS certificate_serial_seq;
+
+CREATE TABLE IF NOT EXISTS certificates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    student_id UUID NOT NULL REFERENCES students(id),
+    course_id UUID NOT NULL REFERENCES courses(id),
+    serial_number VARCHAR(80) NOT NULL UNIQUE,
+    attended_hours DECIMAL(8,2) NOT NULL,
+    start_date DATE NOT NULL,
+    completed_on DATE NOT NULL,
+    file_key VARCHAR(255) NOT NULL,
+    issued_by UUID NOT NULL,
+    issued_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificates_student_course
+    ON certificates(student_id, course_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_certificates_institute_id ON certificates(institute_id);
+CREATE INDEX IF NOT EXISTS idx_certificates_deleted_at ON certificates(deleted_at);
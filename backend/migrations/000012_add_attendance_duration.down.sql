@@ -0,0 +1,2 @@
+ALTER TABLE attendances DROP CONSTRAINT IF EXISTS chk_attendances_check_out;
+ALTER TABLE attendances DROP COLUMN IF EXISTS duration_minutes;
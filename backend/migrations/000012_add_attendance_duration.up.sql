@@ -0,0 +1,5 @@
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS duration_minutes INT;
+
+ALTER TABLE attendances
+    ADD CONSTRAINT chk_attendances_check_out
+    CHECK (check_out_at IS NULL OR (check_in_at IS NOT NULL AND check_out_at > check_in_at));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS attendance_history;
+DROP INDEX IF EXISTS idx_attendances_student_class_date;
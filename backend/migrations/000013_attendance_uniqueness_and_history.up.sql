@@ -0,0 +1,33 @@
+-- Keep only the most recently updated mark per student, class and date
+-- before enforcing uniqueness.
+UPDATE attendances a
+SET deleted_at = CURRENT_TIMESTAMP
+FROM (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY student_id, class_id, date
+        ORDER BY updated_at DESC, created_at DESC, id
+    ) AS rn
+    FROM attendances
+    WHERE deleted_at IS NULL
+) d
+WHERE a.id = d.id AND d.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendances_student_class_date
+    ON attendances(student_id, class_id, date) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS attendance_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    attendance_id UUID NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    changed_by UUID NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_history_attendance_id ON attendance_history(attendance_id);
+
+-- Seed the history with each live record's current status.
+INSERT INTO attendance_history (attendance_id, to_status, changed_by, changed_at)
+SELECT id, status, marked_by, created_at
+FROM attendances
+WHERE deleted_at IS NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS attendance_tracks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    attendance_id UUID NOT NULL REFERENCES attendances(id),
+    file_key VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    size BIGINT NOT NULL,
+    distance_meters DECIMAL(10,1) NOT NULL,
+    duration_seconds INT NOT NULL,
+    max_speed_kph DECIMAL(6,1) NOT NULL,
+    points INT NOT NULL,
+    started_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    uploaded_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_attendance_tracks_attendance_id UNIQUE (attendance_id),
+    CONSTRAINT chk_attendance_tracks_format CHECK (format IN ('gpx', 'geojson'))
+);
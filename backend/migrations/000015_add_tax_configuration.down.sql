@@ -0,0 +1,18 @@
+ALTER TABLE invoice_items
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS tax_exempt;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_name,
+    DROP COLUMN IF EXISTS exempt_amount,
+    DROP COLUMN IF EXISTS taxable_amount;
+
+ALTER TABLE institutes DROP CONSTRAINT IF EXISTS chk_institutes_tax_rate;
+
+ALTER TABLE institutes
+    DROP COLUMN IF EXISTS tax_number,
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS tax_name;
@@ -0,0 +1,22 @@
+ALTER TABLE institutes
+    ADD COLUMN IF NOT EXISTS tax_name VARCHAR(20) NOT NULL DEFAULT 'VAT',
+    ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tax_number VARCHAR(30);
+
+ALTER TABLE institutes
+    ADD CONSTRAINT chk_institutes_tax_rate CHECK (tax_rate >= 0 AND tax_rate < 100);
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS taxable_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS exempt_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_name VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Invoices issued before tax was configured carried none.
+UPDATE invoices SET exempt_amount = amount WHERE tax_amount = 0;
+
+ALTER TABLE invoice_items
+    ADD COLUMN IF NOT EXISTS tax_exempt BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    fiscal_year VARCHAR(10) NOT NULL,
+    last_number BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (institute_id, fiscal_year)
+);
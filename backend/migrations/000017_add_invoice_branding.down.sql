@@ -0,0 +1,3 @@
+ALTER TABLE institutes
+    DROP COLUMN IF EXISTS invoice_footer,
+    DROP COLUMN IF EXISTS invoice_logo_key;
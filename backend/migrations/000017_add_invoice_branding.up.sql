@@ -0,0 +1,3 @@
+ALTER TABLE institutes
+    ADD COLUMN IF NOT EXISTS invoice_logo_key VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS invoice_footer TEXT;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payment_reversals;
+DROP TABLE IF EXISTS credit_notes;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS credited_amount;
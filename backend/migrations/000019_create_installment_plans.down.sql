@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS installments;
+DROP TABLE IF EXISTS installment_plans;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS installment_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    student_id UUID NOT NULL REFERENCES students(id),
+    package_id UUID REFERENCES packages(id),
+    description VARCHAR(255) NOT NULL,
+    total_amount DECIMAL(10,2) NOT NULL,
+    installment_count INT NOT NULL,
+    invoice_upfront BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_installment_plans_total CHECK (total_amount > 0),
+    CONSTRAINT chk_installment_plans_count CHECK (installment_count >= 2),
+    CONSTRAINT chk_installment_plans_status CHECK (status IN ('active', 'canceled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_installment_plans_student_id ON installment_plans(student_id);
+CREATE INDEX IF NOT EXISTS idx_installment_plans_institute_id ON installment_plans(institute_id);
+
+CREATE TABLE IF NOT EXISTS installments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    plan_id UUID NOT NULL REFERENCES installment_plans(id) ON DELETE CASCADE,
+    sequence INT NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    due_date DATE NOT NULL,
+    invoice_id UUID REFERENCES invoices(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_installments_amount CHECK (amount > 0),
+    CONSTRAINT uq_installments_plan_sequence UNIQUE (plan_id, sequence)
+);
+
+-- The daily sweep looks for installments still waiting on an invoice.
+CREATE INDEX IF NOT EXISTS idx_installments_uninvoiced ON installments(due_date) WHERE invoice_id IS NULL;
@@ -0,0 +1,12 @@
+ALTER TABLE invoice_items
+    DROP COLUMN IF EXISTS is_discount,
+    DROP COLUMN IF EXISTS course_id,
+    DROP COLUMN IF EXISTS package_id;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS promo_code_id,
+    DROP COLUMN IF EXISTS discount_amount;
+
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_code_targets;
+DROP TABLE IF EXISTS promo_codes;
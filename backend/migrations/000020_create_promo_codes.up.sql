@@ -0,0 +1,56 @@
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    code VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    discount_type VARCHAR(20) NOT NULL,
+    percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    max_uses INT NOT NULL DEFAULT 0,
+    max_uses_per_student INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_promo_codes_discount CHECK (
+        (discount_type = 'percentage' AND percentage > 0 AND percentage <= 100)
+        OR (discount_type = 'fixed' AND amount > 0)
+    ),
+    CONSTRAINT chk_promo_codes_limits CHECK (max_uses >= 0 AND max_uses_per_student >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promo_codes_institute_code ON promo_codes(institute_id, UPPER(code)) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS promo_code_targets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promo_code_id UUID NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+    package_id UUID REFERENCES packages(id),
+    course_id UUID REFERENCES courses(id),
+    CONSTRAINT chk_promo_code_targets_one CHECK ((package_id IS NULL) <> (course_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_code_targets_promo_code_id ON promo_code_targets(promo_code_id);
+
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promo_code_id UUID NOT NULL REFERENCES promo_codes(id),
+    institute_id UUID NOT NULL REFERENCES institutes(id),
+    student_id UUID NOT NULL REFERENCES students(id),
+    invoice_id UUID NOT NULL REFERENCES invoices(id),
+    amount DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_promo_code_id ON promo_redemptions(promo_code_id, student_id);
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promo_code_id UUID REFERENCES promo_codes(id);
+
+ALTER TABLE invoice_items
+    ADD COLUMN IF NOT EXISTS package_id UUID REFERENCES packages(id),
+    ADD COLUMN IF NOT EXISTS course_id UUID REFERENCES courses(id),
+    ADD COLUMN IF NOT EXISTS is_discount BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS institute_tax_exemptions;
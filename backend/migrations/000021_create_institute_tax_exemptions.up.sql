@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS institute_tax_exemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institute_id UUID NOT NULL REFERENCES institutes(id) ON DELETE CASCADE,
+    package_id UUID REFERENCES packages(id),
+    course_id UUID REFERENCES courses(id),
+    CONSTRAINT chk_institute_tax_exemptions_one CHECK ((package_id IS NULL) <> (course_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_institute_tax_exemptions_institute_id ON institute_tax_exemptions(institute_id);
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"time"
 
@@ -9,14 +11,26 @@ import (
 )
 
 type TokenService interface {
-	GenerateToken(userID uuid.UUID, role string) (string, error)
+	GenerateToken(userID uuid.UUID, role string, instituteID *uuid.UUID) (string, error)
 	GenerateRefreshToken(userID uuid.UUID) (string, error)
 	ValidateToken(tokenString string) (*Claims, error)
 }
 
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Role   string    `json:"role"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Role        string     `json:"role"`
+	InstituteID *uuid.UUID `json:"institute_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// checkInAudience marks lesson check-in tokens. They are signed with their
+// own key as well, so they can never pass as login tokens.
+const checkInAudience = "lesson-check-in"
+
+// CheckInClaims identify the lesson session a QR check-in code was shown
+// for. The token ID lets callers reject replays.
+type CheckInClaims struct {
+	SessionID uuid.UUID `json:"session_id"`
 	jwt.RegisteredClaims
 }
 
@@ -34,10 +48,11 @@ func NewJWTService(secret string, expiry, refreshExpiry time.Duration) *JWTServi
 	}
 }
 
-func (j *JWTService) GenerateToken(userID uuid.UUID, role string) (string, error) {
+func (j *JWTService) GenerateToken(userID uuid.UUID, role string, instituteID *uuid.UUID) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Role:        role,
+		InstituteID: instituteID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -93,4 +108,55 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
+
+// GenerateCheckInToken signs a short-lived token for a lesson session's QR
+// check-in code.
+func (j *JWTService) GenerateCheckInToken(sessionID uuid.UUID, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := &CheckInClaims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{checkInAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        uuid.New().String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(j.checkInSecret())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign check-in token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+func (j *JWTService) ValidateCheckInToken(tokenString string) (*CheckInClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CheckInClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return j.checkInSecret(), nil
+	}, jwt.WithAudience(checkInAudience), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse check-in token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*CheckInClaims)
+	if !ok || !token.Valid || claims.ID == "" {
+		return nil, fmt.Errorf("invalid check-in token")
+	}
+
+	return claims, nil
+}
+
+func (j *JWTService) checkInSecret() []byte {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(checkInAudience))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInToken(t *testing.T) {
+	j := NewJWTService("test-secret", time.Hour, 24*time.Hour)
+	sessionID := uuid.New()
+
+	token, expiresAt, err := j.GenerateCheckInToken(sessionID, time.Minute)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+	claims, err := j.ValidateCheckInToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, claims.SessionID)
+	assert.NotEmpty(t, claims.ID)
+
+	again, _, err := j.GenerateCheckInToken(sessionID, time.Minute)
+	require.NoError(t, err)
+	againClaims, err := j.ValidateCheckInToken(again)
+	require.NoError(t, err)
+	assert.NotEqual(t, claims.ID, againClaims.ID, "every code needs its own ID to be used once")
+}
+
+func TestCheckInTokenRejected(t *testing.T) {
+	j := NewJWTService("test-secret", time.Hour, 24*time.Hour)
+	sessionID := uuid.New()
+
+	sign := func(claims jwt.Claims, key []byte) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		require.NoError(t, err)
+		return token
+	}
+	checkInClaims := func(audience string, expiresAt time.Time) *CheckInClaims {
+		return &CheckInClaims{
+			SessionID: sessionID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Minute)),
+				ID:        uuid.New().String(),
+			},
+		}
+	}
+
+	valid, _, err := j.GenerateCheckInToken(sessionID, time.Minute)
+	require.NoError(t, err)
+	parts := strings.Split(valid, ".")
+	forged := sign(checkInClaims(checkInAudience, time.Now().Add(time.Minute)), []byte("test-secret"))
+	forgedParts := strings.Split(forged, ".")
+
+	login, err := j.GenerateToken(uuid.New(), "student", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "expired", token: sign(checkInClaims(checkInAudience, time.Now().Add(-time.Minute)), j.checkInSecret())},
+		{name: "wrong audience", token: sign(checkInClaims("somewhere-else", time.Now().Add(time.Minute)), j.checkInSecret())},
+		{name: "no expiry", token: sign(&CheckInClaims{SessionID: sessionID, RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{checkInAudience},
+			ID:       uuid.New().String(),
+		}}, j.checkInSecret())},
+		{name: "no token ID", token: sign(&CheckInClaims{SessionID: sessionID, RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{checkInAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}}, j.checkInSecret())},
+		{name: "signed with the login key", token: forged},
+		{name: "tampered payload", token: parts[0] + "." + forgedParts[1] + "." + parts[2]},
+		{name: "tampered signature", token: parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2]))},
+		{name: "signed by another server", token: func() string {
+			other, _, err := NewJWTService("other-secret", time.Hour, time.Hour).GenerateCheckInToken(sessionID, time.Minute)
+			require.NoError(t, err)
+			return other
+		}()},
+		{name: "login token", token: login},
+		{name: "garbage", token: "not-a-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := j.ValidateCheckInToken(tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCheckInTokenIsNotALoginToken(t *testing.T) {
+	j := NewJWTService("test-secret", time.Hour, 24*time.Hour)
+
+	token, _, err := j.GenerateCheckInToken(uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	_, err = j.ValidateToken(token)
+	assert.Error(t, err)
+}
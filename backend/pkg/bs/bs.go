@@ -0,0 +1,147 @@
+// Package bs converts between the Bikram Sambat calendar used in Nepal and
+// the Gregorian (AD) calendar.
+package bs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MinYear and MaxYear bound the BS years the converter knows about.
+	MinYear = 2000
+	MaxYear = 2090
+)
+
+var (
+	ErrInvalidDate = errors.New("invalid BS date")
+	ErrOutOfRange  = fmt.Errorf("date is outside BS %d-%d", MinYear, MaxYear)
+)
+
+// Kathmandu is Nepal Standard Time, UTC+05:45 all year round. BS days
+// begin at midnight here, so convert instants into it before FromAD.
+var Kathmandu = time.FixedZone("Asia/Kathmandu", 5*3600+45*60)
+
+// epoch is 1 Baisakh 2000 BS.
+var epoch = time.Date(1943, time.April, 14, 0, 0, 0, 0, time.UTC)
+
+var monthNames = [12]string{
+	"Baisakh", "Jestha", "Asar", "Shrawan", "Bhadra", "Ashwin",
+	"Kartik", "Mangsir", "Poush", "Magh", "Falgun", "Chaitra",
+}
+
+// Date is a day in the BS calendar. Month runs from 1 (Baisakh) to 12
+// (Chaitra).
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// Parse reads a BS date written as YYYY-MM-DD.
+func Parse(s string) (Date, error) {
+	var d Date
+	if len(s) != len("2006-01-02") {
+		return Date{}, ErrInvalidDate
+	}
+	if _, err := fmt.Sscanf(s, "%4d-%2d-%2d", &d.Year, &d.Month, &d.Day); err != nil {
+		return Date{}, ErrInvalidDate
+	}
+	if err := d.check(); err != nil {
+		return Date{}, err
+	}
+	return d, nil
+}
+
+// FromAD returns the BS date of the calendar day t falls on. Only the year,
+// month and day of t are used.
+func FromAD(t time.Time) (Date, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(epoch) {
+		return Date{}, ErrOutOfRange
+	}
+
+	days := int(day.Sub(epoch).Hours() / 24)
+	for y, months := range monthDays {
+		for m, n := range months {
+			if days < n {
+				return Date{Year: MinYear + y, Month: m + 1, Day: days + 1}, nil
+			}
+			days -= n
+		}
+	}
+	return Date{}, ErrOutOfRange
+}
+
+// ToAD returns the Gregorian date as midnight UTC.
+func (d Date) ToAD() (time.Time, error) {
+	if err := d.check(); err != nil {
+		return time.Time{}, err
+	}
+
+	days := d.Day - 1
+	for y := MinYear; y <= d.Year; y++ {
+		for m, n := range monthDays[y-MinYear] {
+			if y == d.Year && m+1 == d.Month {
+				break
+			}
+			days += n
+		}
+	}
+	return epoch.AddDate(0, 0, days), nil
+}
+
+// MonthName returns the name of the month, such as "Baisakh".
+func (d Date) MonthName() string {
+	return MonthName(d.Month)
+}
+
+// String formats the date as YYYY-MM-DD.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MonthName returns the name of a BS month numbered 1 to 12.
+func MonthName(month int) string {
+	if month < 1 || month > 12 {
+		return ""
+	}
+	return monthNames[month-1]
+}
+
+// DaysInMonth returns the length of a BS month, or 0 outside the range the
+// converter covers.
+func DaysInMonth(year, month int) int {
+	if year < MinYear || year > MaxYear || month < 1 || month > 12 {
+		return 0
+	}
+	return monthDays[year-MinYear][month-1]
+}
+
+func (d Date) check() error {
+	if d.Year < MinYear || d.Year > MaxYear {
+		return ErrOutOfRange
+	}
+	if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > DaysInMonth(d.Year, d.Month) {
+		return ErrInvalidDate
+	}
+	return nil
+}
+
+type contextKey string
+
+const calendarKey contextKey = "calendar"
+
+// WithCalendar marks ctx as belonging to a request that reads and writes
+// dates in BS.
+func WithCalendar(ctx context.Context) context.Context {
+	return context.WithValue(ctx, calendarKey, true)
+}
+
+// Requested reports whether the request asked for BS dates.
+func Requested(ctx context.Context) bool {
+	on, _ := ctx.Value(calendarKey).(bool)
+	return on
+}
@@ -0,0 +1,92 @@
+package bs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToAD(t *testing.T) {
+	tests := map[string]string{
+		"2000-01-01": "1943-04-14",
+		"2057-01-01": "2000-04-13",
+		"2070-01-01": "2013-04-14",
+		"2077-01-01": "2020-04-13",
+		"2080-01-01": "2023-04-14",
+		"2081-01-01": "2024-04-13",
+		"2081-04-01": "2024-07-16",
+		"2082-01-01": "2025-04-14",
+		"2082-04-01": "2025-07-17",
+		"2082-10-01": "2026-01-15",
+		"2083-01-01": "2026-04-14",
+		// Computed years, see monthDays.
+		"2083-04-01": "2026-07-17",
+		"2084-01-01": "2027-04-14",
+		"2084-04-01": "2027-07-17",
+		"2085-01-01": "2028-04-14",
+		"2085-04-01": "2028-07-16",
+		"2086-01-01": "2029-04-14",
+		"2086-04-01": "2029-07-17",
+		"2087-01-01": "2030-04-14",
+		"2087-04-01": "2030-07-17",
+		"2088-01-01": "2031-04-14",
+		"2088-04-01": "2031-07-17",
+		"2089-01-01": "2032-04-14",
+		"2089-04-01": "2032-07-16",
+		"2090-01-01": "2033-04-14",
+		"2090-04-01": "2033-07-17",
+		"2090-12-30": "2034-04-13",
+	}
+
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			d, err := Parse(in)
+			require.NoError(t, err)
+			got, err := d.ToAD()
+			require.NoError(t, err)
+			assert.Equal(t, want, got.Format("2006-01-02"))
+		})
+	}
+}
+
+func TestNewYearFallsInMidApril(t *testing.T) {
+	for year := MinYear; year <= MaxYear; year++ {
+		ad, err := Date{Year: year, Month: 1, Day: 1}.ToAD()
+		require.NoError(t, err)
+		assert.Equal(t, time.April, ad.Month(), "year %d", year)
+		assert.True(t, ad.Day() >= 12 && ad.Day() <= 15, "year %d starts on %s", year, ad.Format("2006-01-02"))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	start := time.Date(1943, time.April, 14, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2034, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		d, err := FromAD(day)
+		require.NoError(t, err)
+		back, err := d.ToAD()
+		require.NoError(t, err)
+		require.Equal(t, day, back, "via %s", d)
+	}
+}
+
+func TestFromADIgnoresTimeOfDay(t *testing.T) {
+	kathmandu := time.FixedZone("NPT", 5*3600+45*60)
+	d, err := FromAD(time.Date(2024, time.July, 16, 23, 30, 0, 0, kathmandu))
+	require.NoError(t, err)
+	assert.Equal(t, Date{Year: 2081, Month: 4, Day: 1}, d)
+	assert.Equal(t, "Shrawan", d.MonthName())
+}
+
+func TestParseRejectsBadDates(t *testing.T) {
+	for _, in := range []string{"2081-13-01", "2081-01-32", "2081-1-1", "1999-12-30", "not a date"} {
+		_, err := Parse(in)
+		assert.Error(t, err, in)
+	}
+
+	_, err := FromAD(time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
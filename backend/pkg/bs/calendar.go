@@ -0,0 +1,101 @@
+package bs
+
+// monthDays holds the length of each month for the years the converter
+// covers. Month lengths in Bikram Sambat follow the solar ephemeris rather
+// than a rule, so they come from the published calendars. Years after 2082
+// have no published calendar yet; their rows are computed from the solar
+// ingress times, fitted to the published years, and should be checked
+// against the official calendar as each one is released.
+var monthDays = [MaxYear - MinYear + 1][12]int{
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2000
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2010
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30}, // 2020
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 32, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31}, // 2030
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 31, 29, 30, 30, 29, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2040
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31}, // 2050
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 32, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30}, // 2060
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 31, 29, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 29, 30, 30, 29, 30, 30}, // 2070
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 31, 32, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 30}, // 2080
+	{31, 31, 32, 32, 31, 30, 30, 30, 29, 30, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30},
+	{31, 31, 32, 31, 32, 30, 30, 29, 30, 29, 30, 30},
+	{31, 32, 31, 32, 31, 30, 30, 30, 29, 29, 30, 31},
+	{30, 32, 31, 32, 31, 30, 30, 30, 29, 30, 29, 31},
+	{31, 31, 32, 31, 31, 31, 30, 29, 30, 29, 30, 30}, // 2090
+}
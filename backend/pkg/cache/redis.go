@@ -2,12 +2,16 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrNotFound is returned by Get for keys that do not exist or have expired.
+var ErrNotFound = errors.New("key not found")
+
 type RedisCache struct {
 	client *redis.Client
 }
@@ -37,7 +41,7 @@ func NewRedis(addr, password string, db int) (*RedisCache, error) {
 func (r *RedisCache) Get(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return "", fmt.Errorf("key not found: %s", key)
+		return "", fmt.Errorf("%w: %s", ErrNotFound, key)
 	}
 	if err != nil {
 		return "", fmt.Errorf("failed to get key: %w", err)
@@ -52,6 +56,23 @@ func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, exp
 	return nil
 }
 
+// SetNX sets key only if it does not exist yet and reports whether it did.
+func (r *RedisCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, key, value, expiration).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to set key: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check key: %w", err)
+	}
+	return n > 0, nil
+}
+
 func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	if err := r.client.Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete key: %w", err)
@@ -14,6 +14,7 @@ var (
 	ErrConflict          = errors.New("resource already exists")
 	ErrInternalServer    = errors.New("internal server error")
 	ErrValidation        = errors.New("validation error")
+	ErrTooLarge          = errors.New("payload too large")
 	ErrDuplicateEmail    = errors.New("email already exists")
 	ErrInvalidCredentials = errors.New("invalid email or password")
 )
@@ -67,6 +68,14 @@ func Unauthorized(message string) *AppError {
 	}
 }
 
+func Forbidden(message string) *AppError {
+	return &AppError{
+		Err:        ErrForbidden,
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+	}
+}
+
 func BadRequest(message string) *AppError {
 	return &AppError{
 		Err:        ErrBadRequest,
@@ -83,6 +92,21 @@ func Conflict(message string) *AppError {
 	}
 }
 
+func TooLarge(message string) *AppError {
+	return &AppError{
+		Err:        ErrTooLarge,
+		Message:    message,
+		StatusCode: http.StatusRequestEntityTooLarge,
+	}
+}
+
+// WithDetails attaches structured details that are returned to the client
+// alongside the message.
+func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
+	e.Details = details
+	return e
+}
+
 func Validation(details map[string]interface{}) *AppError {
 	return &AppError{
 		Err:        ErrValidation,
@@ -109,6 +133,8 @@ func GetStatusCode(err error) int {
 		return http.StatusBadRequest
 	case errors.Is(err, ErrConflict):
 		return http.StatusConflict
+	case errors.Is(err, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,184 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// geoJSONObject holds the members of any GeoJSON object this package reads.
+type geoJSONObject struct {
+	Type        string           `json:"type"`
+	Features    []geoJSONObject  `json:"features"`
+	Geometry    *geoJSONObject   `json:"geometry"`
+	Geometries  []geoJSONObject  `json:"geometries"`
+	Coordinates json.RawMessage  `json:"coordinates"`
+	Properties  *json.RawMessage `json:"properties"`
+}
+
+// ParseGeoJSON reads the line strings of a GeoJSON document. Timestamps are
+// taken from a feature's "coordTimes" property, the convention used by most
+// GPS apps that export GeoJSON. Points and polygons are ignored.
+func ParseGeoJSON(r io.Reader) (*Track, error) {
+	var obj geoJSONObject
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	track := &Track{}
+	if err := readGeoJSON(track, &obj, nil); err != nil {
+		return nil, err
+	}
+	return check(track)
+}
+
+func readGeoJSON(track *Track, obj *geoJSONObject, times json.RawMessage) error {
+	switch obj.Type {
+	case "FeatureCollection":
+		for i := range obj.Features {
+			if err := readGeoJSON(track, &obj.Features[i], nil); err != nil {
+				return err
+			}
+		}
+
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil
+		}
+		if obj.Properties != nil {
+			var props struct {
+				CoordTimes json.RawMessage `json:"coordTimes"`
+			}
+			if err := json.Unmarshal(*obj.Properties, &props); err == nil {
+				times = props.CoordTimes
+			}
+		}
+		return readGeoJSON(track, obj.Geometry, times)
+
+	case "GeometryCollection":
+		for i := range obj.Geometries {
+			if err := readGeoJSON(track, &obj.Geometries[i], nil); err != nil {
+				return err
+			}
+		}
+
+	case "LineString":
+		var coords [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil {
+			return fmt.Errorf("invalid GeoJSON LineString: %w", err)
+		}
+		var lineTimes []string
+		if len(times) > 0 {
+			json.Unmarshal(times, &lineTimes)
+		}
+		points, err := geoJSONPoints(coords, lineTimes)
+		if err != nil {
+			return err
+		}
+		track.Segments = append(track.Segments, points)
+
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &lines); err != nil {
+			return fmt.Errorf("invalid GeoJSON MultiLineString: %w", err)
+		}
+		var lineTimes [][]string
+		if len(times) > 0 {
+			json.Unmarshal(times, &lineTimes)
+		}
+		for i, coords := range lines {
+			var t []string
+			if i < len(lineTimes) {
+				t = lineTimes[i]
+			}
+			points, err := geoJSONPoints(coords, t)
+			if err != nil {
+				return err
+			}
+			track.Segments = append(track.Segments, points)
+		}
+
+	case "Point", "MultiPoint", "Polygon", "MultiPolygon":
+		// Not part of a route.
+
+	default:
+		return ErrUnknownFormat
+	}
+	return nil
+}
+
+// geoJSONPoints converts [lon, lat] positions, pairing them with times when
+// there is one for every position.
+func geoJSONPoints(coords [][]float64, times []string) ([]Point, error) {
+	if len(times) != len(coords) {
+		times = nil
+	}
+
+	points := make([]Point, 0, len(coords))
+	for i, c := range coords {
+		if len(c) < 2 {
+			return nil, ErrInvalidCoordinate
+		}
+		p := Point{Lon: c[0], Lat: c[1]}
+		if times != nil {
+			at, err := parseTime(times[i])
+			if err != nil {
+				return nil, err
+			}
+			p.Time = at
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// Feature is a GeoJSON feature, ready to be encoded for map clients.
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// ToGeoJSON converts the track to a LineString feature, or a
+// MultiLineString when it has several segments. Timestamps are written to
+// the "coordTimes" property so the feature reads back unchanged.
+func ToGeoJSON(t *Track) Feature {
+	lines := make([][][2]float64, len(t.Segments))
+	times := make([][]string, len(t.Segments))
+	timed := false
+
+	for i, seg := range t.Segments {
+		lines[i] = make([][2]float64, len(seg))
+		times[i] = make([]string, len(seg))
+		for j, p := range seg {
+			lines[i][j] = [2]float64{p.Lon, p.Lat}
+			if !p.Time.IsZero() {
+				times[i][j] = p.Time.UTC().Format(time.RFC3339)
+				timed = true
+			}
+		}
+	}
+
+	feature := Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "MultiLineString", Coordinates: lines},
+		Properties: map[string]interface{}{},
+	}
+	if timed {
+		feature.Properties["coordTimes"] = times
+	}
+	if len(lines) == 1 {
+		feature.Geometry.Coordinates = lines[0]
+		if timed {
+			feature.Properties["coordTimes"] = times[0]
+		}
+	}
+	return feature
+}
@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var errInvalidTime = errors.New("track has an invalid timestamp")
+
+type gpxFile struct {
+	XMLName xml.Name `xml:"gpx"`
+	Tracks  []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+// ParseGPX reads the tracks of a GPX file, falling back to its routes when
+// it has no recorded tracks.
+func ParseGPX(r io.Reader) (*Track, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		var unexpected xml.UnmarshalError
+		if errors.As(err, &unexpected) {
+			return nil, ErrUnknownFormat
+		}
+		return nil, fmt.Errorf("invalid GPX: %w", err)
+	}
+
+	track := &Track{}
+	for _, trk := range file.Tracks {
+		for _, seg := range trk.Segments {
+			points, err := gpxPoints(seg.Points)
+			if err != nil {
+				return nil, err
+			}
+			track.Segments = append(track.Segments, points)
+		}
+	}
+	if len(track.Segments) == 0 {
+		for _, rte := range file.Routes {
+			points, err := gpxPoints(rte.Points)
+			if err != nil {
+				return nil, err
+			}
+			track.Segments = append(track.Segments, points)
+		}
+	}
+
+	return check(track)
+}
+
+func gpxPoints(in []gpxPoint) ([]Point, error) {
+	points := make([]Point, 0, len(in))
+	for _, p := range in {
+		at, err := parseTime(p.Time)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, Point{Lat: p.Lat, Lon: p.Lon, Time: at})
+	}
+	return points, nil
+}
+
+// parseTime reads an ISO 8601 timestamp, taking ones without a zone as UTC.
+// An empty string is the zero time.
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02T15:04:05.999999999", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errInvalidTime
+}
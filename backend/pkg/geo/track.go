@@ -0,0 +1,189 @@
+// Package geo reads GPS tracks recorded during driving lessons and
+// summarises where and how far the student drove.
+package geo
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	FormatGPX     = "gpx"
+	FormatGeoJSON = "geojson"
+)
+
+var (
+	ErrUnknownFormat     = errors.New("track must be GPX or GeoJSON")
+	ErrNoPoints          = errors.New("track has no points")
+	ErrInvalidCoordinate = errors.New("track has a coordinate out of range")
+)
+
+// earthRadius is the mean Earth radius in meters.
+const earthRadius = 6371008.8
+
+// speedWindow is the shortest stretch of time speeds are measured over, so
+// that jitter between two close fixes does not show up as a burst of speed.
+const speedWindow = 5 * time.Second
+
+// Point is a single GPS fix. Time is zero when the recording has no
+// timestamps.
+type Point struct {
+	Lat  float64   `json:"lat"`
+	Lon  float64   `json:"lon"`
+	Time time.Time `json:"time,omitzero"`
+}
+
+// Track is a recorded route. Each segment is a continuous recording; the
+// gap between segments, such as when the phone lost signal, is not counted
+// as distance driven.
+type Track struct {
+	Segments [][]Point
+}
+
+// Summary describes a track for reports.
+type Summary struct {
+	DistanceMeters  float64    `json:"distance_meters"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	MaxSpeedKPH     float64    `json:"max_speed_kph"`
+	Points          int        `json:"points"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+}
+
+// Parse reads a GPX or GeoJSON track, telling the two apart by content. It
+// returns the format found.
+func Parse(data []byte) (*Track, string, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	if len(trimmed) == 0 {
+		return nil, "", ErrUnknownFormat
+	}
+
+	switch trimmed[0] {
+	case '<':
+		track, err := ParseGPX(bytes.NewReader(trimmed))
+		return track, FormatGPX, err
+	case '{':
+		track, err := ParseGeoJSON(bytes.NewReader(trimmed))
+		return track, FormatGeoJSON, err
+	}
+	return nil, "", ErrUnknownFormat
+}
+
+// Summarize measures the distance, duration and top speed of the track.
+// Duration and speed are zero when the track has no timestamps.
+func Summarize(t *Track) Summary {
+	var s Summary
+	var first, last time.Time
+
+	for _, seg := range t.Segments {
+		s.Points += len(seg)
+		for i, p := range seg {
+			if i > 0 {
+				s.DistanceMeters += Distance(seg[i-1], p)
+			}
+			if p.Time.IsZero() {
+				continue
+			}
+			if first.IsZero() || p.Time.Before(first) {
+				first = p.Time
+			}
+			if p.Time.After(last) {
+				last = p.Time
+			}
+		}
+		if speed := maxSpeed(seg); speed > s.MaxSpeedKPH {
+			s.MaxSpeedKPH = speed
+		}
+	}
+
+	if !first.IsZero() {
+		s.StartedAt = &first
+		s.EndedAt = &last
+		s.DurationSeconds = int64(last.Sub(first) / time.Second)
+	}
+	s.DistanceMeters = math.Round(s.DistanceMeters*10) / 10
+	s.MaxSpeedKPH = math.Round(s.MaxSpeedKPH*10) / 10
+	return s
+}
+
+// Distance returns the great-circle distance between two points in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// maxSpeed returns the highest average speed in km/h over any stretch of
+// the segment lasting at least speedWindow.
+func maxSpeed(seg []Point) float64 {
+	type fix struct {
+		at      time.Time
+		covered float64
+	}
+
+	// Only timestamped points can be measured against, but distance to them
+	// still runs through every point in between.
+	var fixes []fix
+	var covered float64
+	for i, p := range seg {
+		if i > 0 {
+			covered += Distance(seg[i-1], p)
+		}
+		if !p.Time.IsZero() {
+			fixes = append(fixes, fix{at: p.Time, covered: covered})
+		}
+	}
+
+	var best float64
+	start := 0
+	for end := 1; end < len(fixes); end++ {
+		for start+1 < end && fixes[end].at.Sub(fixes[start+1].at) >= speedWindow {
+			start++
+		}
+		elapsed := fixes[end].at.Sub(fixes[start].at)
+		if elapsed < speedWindow {
+			continue
+		}
+		speed := (fixes[end].covered - fixes[start].covered) / elapsed.Seconds() * 3.6
+		if speed > best {
+			best = speed
+		}
+	}
+	return best
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func validPoint(p Point) bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// check drops empty segments and rejects tracks with no points or with
+// coordinates off the globe.
+func check(t *Track) (*Track, error) {
+	segments := t.Segments[:0]
+	for _, seg := range t.Segments {
+		if len(seg) == 0 {
+			continue
+		}
+		for _, p := range seg {
+			if !validPoint(p) {
+				return nil, ErrInvalidCoordinate
+			}
+		}
+		segments = append(segments, seg)
+	}
+	if len(segments) == 0 {
+		return nil, ErrNoPoints
+	}
+	t.Segments = segments
+	return t, nil
+}
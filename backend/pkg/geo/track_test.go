@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// One thousandth of a degree of latitude is about 111.2 meters.
+const gpxTrack = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk>
+    <trkseg>
+      <trkpt lat="27.700" lon="85.300"><time>2024-05-01T06:00:00Z</time></trkpt>
+      <trkpt lat="27.701" lon="85.300"><time>2024-05-01T06:00:10Z</time></trkpt>
+      <trkpt lat="27.702" lon="85.300"><time>2024-05-01T06:00:20Z</time></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="27.710" lon="85.300"><time>2024-05-01T06:05:00Z</time></trkpt>
+      <trkpt lat="27.711" lon="85.300"><time>2024-05-01T06:05:40Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+func TestParseGPX(t *testing.T) {
+	track, format, err := Parse([]byte(gpxTrack))
+	require.NoError(t, err)
+	assert.Equal(t, FormatGPX, format)
+	require.Len(t, track.Segments, 2)
+	assert.Len(t, track.Segments[0], 3)
+	assert.Equal(t, 27.701, track.Segments[0][1].Lat)
+	assert.Equal(t, time.Date(2024, 5, 1, 6, 0, 10, 0, time.UTC), track.Segments[0][1].Time)
+}
+
+func TestSummarize(t *testing.T) {
+	track, _, err := Parse([]byte(gpxTrack))
+	require.NoError(t, err)
+
+	s := Summarize(track)
+	assert.Equal(t, 5, s.Points)
+	// Three legs of ~111 m; the gap between segments is not driven.
+	assert.InDelta(t, 333.6, s.DistanceMeters, 0.5)
+	assert.Equal(t, int64(340), s.DurationSeconds)
+	// 111.2 m in 10 s is ~40 km/h.
+	assert.InDelta(t, 40.0, s.MaxSpeedKPH, 0.2)
+	require.NotNil(t, s.StartedAt)
+	assert.Equal(t, time.Date(2024, 5, 1, 6, 5, 40, 0, time.UTC), *s.EndedAt)
+}
+
+func TestSummarizeIgnoresJitter(t *testing.T) {
+	start := time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)
+	track := &Track{Segments: [][]Point{{
+		{Lat: 27.7000, Lon: 85.3, Time: start},
+		// A 50 m jump within a second would read as 180 km/h.
+		{Lat: 27.70045, Lon: 85.3, Time: start.Add(time.Second)},
+		{Lat: 27.7000, Lon: 85.3, Time: start.Add(2 * time.Second)},
+		{Lat: 27.7000, Lon: 85.3, Time: start.Add(60 * time.Second)},
+	}}}
+
+	s := Summarize(track)
+	assert.Less(t, s.MaxSpeedKPH, 80.0)
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	t.Run("reads coordTimes", func(t *testing.T) {
+		doc := `{"type":"FeatureCollection","features":[{"type":"Feature",
+			"properties":{"coordTimes":["2024-05-01T06:00:00Z","2024-05-01T06:00:10Z"]},
+			"geometry":{"type":"LineString","coordinates":[[85.3,27.700],[85.3,27.701]]}}]}`
+
+		track, format, err := Parse([]byte(doc))
+		require.NoError(t, err)
+		assert.Equal(t, FormatGeoJSON, format)
+		require.Len(t, track.Segments, 1)
+		assert.Equal(t, 85.3, track.Segments[0][0].Lon)
+		assert.Equal(t, int64(10), Summarize(track).DurationSeconds)
+	})
+
+	t.Run("accepts untimed geometry", func(t *testing.T) {
+		track, _, err := Parse([]byte(`{"type":"MultiLineString","coordinates":[[[85.3,27.7],[85.3,27.701]],[[85.3,27.71],[85.3,27.711]]]}`))
+		require.NoError(t, err)
+		s := Summarize(track)
+		assert.Len(t, track.Segments, 2)
+		assert.Zero(t, s.DurationSeconds)
+		assert.Nil(t, s.StartedAt)
+	})
+
+	t.Run("round trips through ToGeoJSON", func(t *testing.T) {
+		track, _, err := Parse([]byte(gpxTrack))
+		require.NoError(t, err)
+
+		encoded, err := json.Marshal(ToGeoJSON(track))
+		require.NoError(t, err)
+
+		back, _, err := Parse(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, track.Segments, back.Segments)
+	})
+}
+
+func TestParseRejectsBadTracks(t *testing.T) {
+	tests := map[string]struct {
+		doc string
+		err error
+	}{
+		"unknown format":   {doc: "lat,lon\n27.7,85.3", err: ErrUnknownFormat},
+		"other XML":        {doc: "<kml></kml>", err: ErrUnknownFormat},
+		"no points":        {doc: `<gpx><trk><trkseg></trkseg></trk></gpx>`, err: ErrNoPoints},
+		"latitude too big": {doc: `{"type":"LineString","coordinates":[[85.3,97.7]]}`, err: ErrInvalidCoordinate},
+		"bad timestamp":    {doc: `<gpx><trk><trkseg><trkpt lat="1" lon="1"><time>yesterday</time></trkpt></trkseg></trk></gpx>`, err: errInvalidTime},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := Parse([]byte(tt.doc))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Package money holds amounts of money as an exact count of paisa, the
+// hundredth part of the rupee, so that sums and balance checks never drift
+// the way float64 arithmetic does.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Amount is a sum of money in minor units (paisa). It is written to JSON
+// and to the database as a decimal with two places, so existing clients
+// and decimal(10,2) columns keep working.
+type Amount int64
+
+// Zero is no money at all.
+const Zero Amount = 0
+
+var ErrInvalid = errors.New("invalid amount of money")
+
+// FromMinor returns the amount for a count of paisa.
+func FromMinor(paisa int64) Amount {
+	return Amount(paisa)
+}
+
+// FromFloat converts a float, rounding to the nearest paisa with halves
+// rounded away from zero. The float's shortest decimal form is rounded, so
+// 1.005 becomes 1.01 rather than the 1.00 its binary value would give.
+func FromFloat(f float64) Amount {
+	a, err := Parse(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Zero
+	}
+	return a
+}
+
+// Parse reads a decimal such as "1250", "1250.5" or "-3.75", rounding to
+// the nearest paisa with halves rounded away from zero.
+func Parse(s string) (Amount, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt64(100))
+	if !fitsInt64(r) {
+		return Zero, fmt.Errorf("%w: %q is out of range", ErrInvalid, s)
+	}
+	return roundRat(r), nil
+}
+
+// roundRat rounds a count of paisa to a whole one, halves away from zero.
+func roundRat(r *big.Rat) Amount {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() != 0 && new(big.Int).Abs(new(big.Int).Lsh(m, 1)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Num().Sign())))
+	}
+	return Amount(q.Int64())
+}
+
+// fitsInt64 reports whether r rounds to a value an Amount can hold.
+func fitsInt64(r *big.Rat) bool {
+	q := new(big.Int).Quo(r.Num(), r.Denom())
+	return q.IsInt64() && q.Int64() > math.MinInt64 && q.Int64() < math.MaxInt64
+}
+
+// Minor returns the amount in paisa.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount in rupees, for ratios and charts only; never
+// do arithmetic on the result.
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+// Mul returns the amount multiplied by a whole quantity.
+func (a Amount) Mul(n int) Amount {
+	return a * Amount(n)
+}
+
+// Percent returns p percent of the amount, rounded to the nearest paisa
+// with halves rounded away from zero.
+func (a Amount) Percent(p float64) Amount {
+	rate, ok := new(big.Rat).SetString(strconv.FormatFloat(p, 'f', -1, 64))
+	if !ok {
+		return Zero
+	}
+	share := rate.Mul(rate, new(big.Rat).SetInt64(int64(a)))
+	share.Quo(share, new(big.Rat).SetInt64(100))
+	return roundRat(share)
+}
+
+// MulDiv returns the amount multiplied by num/den, rounded to the nearest
+// paisa with halves rounded away from zero.
+func (a Amount) MulDiv(num, den int64) Amount {
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(num)), big.NewInt(den))
+	return roundRat(r)
+}
+
+// String formats the amount with two decimal places, such as "-3.75".
+func (a Amount) String() string {
+	sign := ""
+	n := int64(a)
+	if n < 0 {
+		sign = "-"
+	}
+	// Work in uint64 so the most negative amount does not overflow.
+	u := uint64(n)
+	if n < 0 {
+		u = -u
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, u/100, u%100)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one, so clients
+// that send "1250.50" to avoid float issues of their own are understood.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalid, s)
+		}
+		s = unquoted
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan reads a decimal column.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Zero
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = Amount(v * 100)
+	case float64:
+		*a = FromFloat(v)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+	return nil
+}
+
+func (a *Amount) scanString(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value writes the amount as an exact decimal string.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
@@ -0,0 +1,99 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Amount{
+		"0":        0,
+		"1250":     125000,
+		"1250.5":   125050,
+		"0.01":     1,
+		"-3.75":    -375,
+		"1.005":    101,
+		"1.00499":  100,
+		"-1.005":   -101,
+		"2.675":    268,
+		"1e3":      100000,
+		"12.34000": 1234,
+	}
+
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			got, err := Parse(in)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	_, err := Parse("ten")
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = Parse("1e30")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestFromFloat(t *testing.T) {
+	// 0.1 + 0.2 is 0.30000000000000004 as a float.
+	assert.Equal(t, Amount(30), FromFloat(0.1+0.2))
+	assert.Equal(t, Amount(101), FromFloat(1.005))
+	assert.Equal(t, Amount(-101), FromFloat(-1.005))
+}
+
+func TestPercent(t *testing.T) {
+	assert.Equal(t, Amount(1300), Amount(10000).Percent(13))
+	assert.Equal(t, Amount(2), Amount(15).Percent(12.5))
+	assert.Equal(t, Amount(-2), Amount(-15).Percent(12.5))
+	assert.Equal(t, Amount(1015), Amount(10150).Percent(10))
+	assert.Equal(t, Zero, Amount(10150).Percent(0))
+}
+
+func TestMulDiv(t *testing.T) {
+	// The net price inside 1130.00 including 13% tax.
+	assert.Equal(t, Amount(100000), Amount(113000).MulDiv(100, 113))
+	assert.Equal(t, Amount(33), Amount(100).MulDiv(1, 3))
+	assert.Equal(t, Amount(-67), Amount(-100).MulDiv(2, 3))
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "0.00", Zero.String())
+	assert.Equal(t, "1250.50", Amount(125050).String())
+	assert.Equal(t, "-0.05", Amount(-5).String())
+	assert.Equal(t, "-92233720368547758.08", Amount(math.MinInt64).String())
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Amount Amount  `json:"amount"`
+		Opt    *Amount `json:"opt"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 1499.995, "opt": "20.1"}`), &v))
+	assert.Equal(t, Amount(150000), v.Amount)
+	require.NotNil(t, v.Opt)
+	assert.Equal(t, Amount(2010), *v.Opt)
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 1500.00, "opt": 20.10}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": true}`), &v))
+}
+
+func TestScan(t *testing.T) {
+	var a Amount
+	require.NoError(t, a.Scan([]byte("10.10")))
+	assert.Equal(t, Amount(1010), a)
+
+	require.NoError(t, a.Scan(nil))
+	assert.Equal(t, Zero, a)
+
+	value, err := Amount(-1234).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "-12.34", value)
+}
@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
@@ -16,6 +17,10 @@ type Server struct {
 	mux    *asynq.ServeMux
 }
 
+type Scheduler struct {
+	scheduler *asynq.Scheduler
+}
+
 func NewClient(redisAddr, password string, db int) *Client {
 	client := asynq.NewClient(asynq.RedisClientOpt{
 		Addr:     redisAddr,
@@ -51,6 +56,21 @@ func NewServer(redisAddr, password string, db int, concurrency int) *Server {
 	}
 }
 
+func NewScheduler(redisAddr, password string, db int) *Scheduler {
+	scheduler := asynq.NewScheduler(
+		asynq.RedisClientOpt{
+			Addr:     redisAddr,
+			Password: password,
+			DB:       db,
+		},
+		&asynq.SchedulerOpts{
+			Location: time.Local,
+		},
+	)
+
+	return &Scheduler{scheduler: scheduler}
+}
+
 func (c *Client) Enqueue(ctx context.Context, task *asynq.Task, opts ...asynq.Option) error {
 	info, err := c.client.EnqueueContext(ctx, task, opts...)
 	if err != nil {
@@ -77,4 +97,24 @@ func (s *Server) Start() error {
 
 func (s *Server) Stop() {
 	s.server.Shutdown()
+}
+
+// Register enqueues a payload-less task of the given type on every tick of
+// the cron spec.
+func (s *Scheduler) Register(cronspec, taskType string, opts ...asynq.Option) error {
+	if _, err := s.scheduler.Register(cronspec, asynq.NewTask(taskType, nil), opts...); err != nil {
+		return fmt.Errorf("failed to register scheduled task %s: %w", taskType, err)
+	}
+	return nil
+}
+
+func (s *Scheduler) Start() error {
+	if err := s.scheduler.Start(); err != nil {
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) Stop() {
+	s.scheduler.Shutdown()
 }
\ No newline at end of file
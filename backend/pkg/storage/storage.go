@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound        = errors.New("file not found")
+	ErrTooLarge        = errors.New("file is too large")
+	ErrUnsupportedType = errors.New("file type is not supported")
+	ErrInvalidKey      = errors.New("invalid file key")
+)
+
+// Storage keeps uploaded files under slash-separated keys such as
+// "receipts/<institute>/<expense>.pdf".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a download link for the key that stops working after
+	// expiry.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// Upload is a file received from a client, before it has been inspected.
+type Upload struct {
+	File     io.Reader
+	Size     int64
+	FileName string
+}
+
+// Link is a time-limited download URL handed out to clients.
+type Link struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+	TypePDF  = "application/pdf"
+)
+
+var extensions = map[string]string{
+	TypeJPEG: ".jpg",
+	TypePNG:  ".png",
+	TypePDF:  ".pdf",
+}
+
+// Extension returns the file extension used when storing the content type.
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// Inspect checks an upload against the size limit and the allowed content
+// types. The type is sniffed from the content rather than trusted from the
+// client; the returned reader still yields the whole file.
+func Inspect(r io.Reader, size, maxSize int64, allowed ...string) (string, io.Reader, error) {
+	if size > maxSize {
+		return "", nil, ErrTooLarge
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !slices.Contains(allowed, contentType) {
+		return "", nil, ErrUnsupportedType
+	}
+
+	return contentType, io.LimitReader(br, maxSize), nil
+}
+
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
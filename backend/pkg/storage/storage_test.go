@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pdf = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+
+func TestInspect(t *testing.T) {
+	t.Run("detects the type from content", func(t *testing.T) {
+		contentType, body, err := Inspect(bytes.NewReader(pdf), int64(len(pdf)), 1024, TypePDF)
+		require.NoError(t, err)
+		assert.Equal(t, TypePDF, contentType)
+
+		all, err := io.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, pdf, all)
+	})
+
+	t.Run("rejects types not allowed", func(t *testing.T) {
+		_, _, err := Inspect(bytes.NewReader(pdf), int64(len(pdf)), 1024, TypeJPEG, TypePNG)
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+	})
+
+	t.Run("rejects files over the limit", func(t *testing.T) {
+		_, _, err := Inspect(bytes.NewReader(pdf), int64(len(pdf)), 8, TypePDF)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir(), "http://localhost:8080/api/v1/files/", "secret")
+	require.NoError(t, err)
+
+	key := "receipts/a/b/c.pdf"
+	require.NoError(t, store.Put(ctx, key, bytes.NewReader(pdf), int64(len(pdf)), TypePDF))
+
+	f, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	all, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, pdf, all)
+
+	t.Run("signed urls verify until they expire", func(t *testing.T) {
+		raw, err := store.SignedURL(ctx, key, time.Minute)
+		require.NoError(t, err)
+
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v1/files/"+key, u.Path)
+
+		q := u.Query()
+		assert.NoError(t, store.Verify(key, q.Get("expires"), q.Get("signature")))
+		assert.Error(t, store.Verify("receipts/a/b/other.pdf", q.Get("expires"), q.Get("signature")))
+
+		expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+		assert.Error(t, store.Verify(key, expired, store.sign(key, expired)))
+	})
+
+	t.Run("rejects keys escaping the root", func(t *testing.T) {
+		assert.ErrorIs(t, store.Put(ctx, "../x.pdf", bytes.NewReader(pdf), 0, TypePDF), ErrInvalidKey)
+		_, err := store.Get(ctx, "a//b")
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStorageRequiresASigningKey(t *testing.T) {
+	_, err := NewLocal(t.TempDir(), "http://localhost:8080/api/v1/files/", "")
+	assert.Error(t, err)
+}
+
+// TestS3Storage runs against a MinIO instance, such as the one in
+// docker-compose.yml, when STORAGE_TEST_S3_ENDPOINT is set.
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("STORAGE_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("STORAGE_TEST_S3_ENDPOINT not set")
+	}
+
+	ctx := context.Background()
+	store, err := NewS3(ctx, S3Options{
+		Endpoint:  endpoint,
+		AccessKey: os.Getenv("STORAGE_TEST_S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("STORAGE_TEST_S3_SECRET_KEY"),
+		Bucket:    "chalak-test",
+		Region:    "us-east-1",
+	})
+	require.NoError(t, err)
+
+	key := "students/test/photo.pdf"
+	require.NoError(t, store.Put(ctx, key, bytes.NewReader(pdf), int64(len(pdf)), TypePDF))
+
+	f, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	all, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, pdf, all)
+
+	raw, err := store.SignedURL(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, raw, "X-Amz-Signature")
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,29 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const instituteIDKey contextKey = "institute_id"
+
+// WithInstituteID returns a copy of ctx scoped to the given institute.
+func WithInstituteID(ctx context.Context, instituteID uuid.UUID) context.Context {
+	return context.WithValue(ctx, instituteIDKey, instituteID)
+}
+
+// InstituteID returns the institute the request is scoped to. ok is false for
+// unscoped contexts such as platform admins and background jobs.
+func InstituteID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(instituteIDKey).(uuid.UUID)
+	return id, ok
+}
+
+// CanAccess reports whether the caller may see data belonging to instituteID.
+func CanAccess(ctx context.Context, instituteID uuid.UUID) bool {
+	id, ok := InstituteID(ctx)
+	return !ok || id == instituteID
+}